@@ -0,0 +1,691 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// AccountSettingDefaultArgs defines inputs for AWS ECS account settings.
+type AccountSettingDefaultArgs struct {
+	Name  string
+	Value pulumi.StringInput
+}
+
+// CapacityProviderArgs defines inputs for AWS ECS capacity provider.
+type CapacityProviderArgs struct {
+	AutoScalingGroupProvider *ecs.CapacityProviderAutoScalingGroupProviderArgs
+	Name                     string
+	Tags                     pulumi.StringMapInput
+}
+
+// ClusterArgs defines inputs for creating an AWS ECS cluster.
+type ClusterArgs struct {
+	Configuration          *ecs.ClusterConfigurationArgs
+	Name                   pulumi.StringInput
+	ServiceConnectDefaults *ecs.ClusterServiceConnectDefaultsArgs
+	Settings               ecs.ClusterSettingArray
+	Tags                   pulumi.StringMapInput
+}
+
+// ClusterCapacityProviderArgs defines inputs for setting up capacity providers for an AWS ECS cluster.
+type ClusterCapacityProviderArgs struct {
+	CapacityProviders                 pulumi.StringArrayInput
+	ClusterName                       pulumi.StringInput
+	DefaultCapacityProviderStrategies ecs.ClusterCapacityProvidersDefaultCapacityProviderStrategyArray
+}
+
+// ServiceArgs defines inputs for creating an AWS ECS service.
+type ServiceArgs struct {
+	Alarms                          *ecs.ServiceAlarmsArgs
+	AutoScaling                     *ServiceAutoScalingArgs
+	BlueGreen                       *ServiceBlueGreenArgs
+	CapacityProviderStrategies      ecs.ServiceCapacityProviderStrategyArray
+	ClusterArn                      pulumi.StringInput
+	DeploymentCircuitBreaker        *ecs.ServiceDeploymentCircuitBreakerArgs
+	DeploymentController            *ecs.ServiceDeploymentControllerArgs
+	DeploymentMaximumPercent        pulumi.IntPtrInput
+	DeploymentMinimumHealthyPercent pulumi.IntPtrInput
+	DesiredCount                    pulumi.IntPtrInput
+	EnableEcsManagedTags            pulumi.BoolPtrInput
+	EnableExecuteCommand            pulumi.BoolPtrInput
+	ForceNewDeployment              pulumi.BoolPtrInput
+	HealthCheckGracePeriodSeconds   pulumi.IntPtrInput
+	IamRole                         pulumi.StringPtrInput
+	Ingress                         *ServiceIngressArgs
+	LaunchType                      pulumi.StringPtrInput
+	LoadBalancers                   ecs.ServiceLoadBalancerArray
+	Name                            pulumi.StringInput
+	NetworkConfiguration            *ecs.ServiceNetworkConfigurationArgs
+	OrderedPlacementStrategies      ecs.ServiceOrderedPlacementStrategyArray
+	PlacementConstraints            ecs.ServicePlacementConstraintArray
+	PlatformVersion                 pulumi.StringPtrInput
+	PropagateTags                   pulumi.StringPtrInput
+	SchedulingStrategy              pulumi.StringPtrInput
+	ServiceConnectConfiguration     *ecs.ServiceServiceConnectConfigurationArgs
+	ServiceRegistry                 *ecs.ServiceServiceRegistriesArgs
+	ServiceVolumeConfiguration      *ecs.ServiceVolumeConfigurationArgs
+	Tags                            pulumi.StringMapInput
+	Targets                         []*ServiceTargetArgs
+	TaskDefinition                  pulumi.StringPtrInput
+	Triggers                        pulumi.StringMapInput
+	WaitForSteadyState              pulumi.BoolPtrInput
+}
+
+// TaskDefinitionArgs defines inputs for creating an AWS ECS task definition.
+type TaskDefinitionArgs struct {
+	// ContainerDefinitions is a JSON encoded list of container definitions.
+	ContainerDefinitions    pulumi.StringInput
+	CPU                     pulumi.StringPtrInput
+	EphemeralStorage        *ecs.TaskDefinitionEphemeralStorageArgs
+	ExecutionRole           *TaskDefinitionRoleArgs
+	ExecutionRoleArn        pulumi.StringPtrInput
+	InferenceAccelerators   ecs.TaskDefinitionInferenceAcceleratorArray
+	IpcMode                 pulumi.StringPtrInput
+	Logging                 *TaskLoggingArgs
+	Memory                  pulumi.StringPtrInput
+	Name                    pulumi.StringInput
+	NetworkMode             pulumi.StringPtrInput
+	PidMode                 pulumi.StringPtrInput
+	PlacementConstraints    ecs.TaskDefinitionPlacementConstraintArray
+	ProxyConfiguration      *ecs.TaskDefinitionProxyConfigurationArgs
+	RequiresCompatibilities pulumi.StringArrayInput
+	RuntimePlatform         *ecs.TaskDefinitionRuntimePlatformArgs
+	Secrets                 []*TaskSecretArgs
+	SkipDestroy             pulumi.BoolPtrInput
+	Tags                    pulumi.StringMapInput
+	TaskRole                *TaskDefinitionRoleArgs
+	TaskRoleArn             pulumi.StringPtrInput
+	TrackLatest             pulumi.BoolPtrInput
+	Volumes                 ecs.TaskDefinitionVolumeArray
+}
+
+// TaskSetArgs defines inputs for creating an AWS ECS task set.
+type TaskSetArgs struct {
+	CapacityProviderStrategies ecs.TaskSetCapacityProviderStrategyArray
+	Cluster                    pulumi.StringInput
+	ExternalID                 pulumi.StringPtrInput
+	ForceDelete                pulumi.BoolPtrInput
+	LaunchType                 pulumi.StringPtrInput
+	LoadBalancers              ecs.TaskSetLoadBalancerArray
+	Name                       string
+	NetworkConfiguration       *ecs.TaskSetNetworkConfigurationArgs
+	PlatformVersion            pulumi.StringPtrInput
+	Scale                      *ecs.TaskSetScaleArgs
+	Service                    pulumi.StringInput
+	ServiceRegistries          *ecs.TaskSetServiceRegistriesArgs
+	Tags                       pulumi.StringMapInput
+	TaskDefinition             pulumi.StringInput
+	WaitUntilStable            pulumi.BoolPtrInput
+	WaitUntilStableTimeout     pulumi.StringPtrInput
+}
+
+// ToArgs converts the account setting configuration into inputs.
+func (c AccountSettingDefaultConfig) ToArgs() *AccountSettingDefaultArgs {
+	return &AccountSettingDefaultArgs{
+		Name:  c.Name,
+		Value: pulumi.String(c.Value),
+	}
+}
+
+// ToArgs converts the capacity provider configuration into inputs.
+func (c CapacityProviderConfig) ToArgs() *CapacityProviderArgs {
+	var managedScaling *ecs.CapacityProviderAutoScalingGroupProviderManagedScalingArgs
+	if c.AutoscalingGroupProvider.ManagedScaling != nil {
+		managedScaling = &ecs.CapacityProviderAutoScalingGroupProviderManagedScalingArgs{
+			InstanceWarmupPeriod:   pulumi.IntPtrFromPtr(c.AutoscalingGroupProvider.ManagedScaling.InstanceWarmupPeriod),
+			MaximumScalingStepSize: pulumi.IntPtrFromPtr(c.AutoscalingGroupProvider.ManagedScaling.MaximumScalingStepSize),
+			MinimumScalingStepSize: pulumi.IntPtrFromPtr(c.AutoscalingGroupProvider.ManagedScaling.MinimumScalingStepSize),
+			Status:                 pulumi.StringPtrFromPtr(c.AutoscalingGroupProvider.ManagedScaling.Status),
+			TargetCapacity:         pulumi.IntPtrFromPtr(c.AutoscalingGroupProvider.ManagedScaling.TargetCapacity),
+		}
+	}
+
+	return &CapacityProviderArgs{
+		AutoScalingGroupProvider: &ecs.CapacityProviderAutoScalingGroupProviderArgs{
+			AutoScalingGroupArn:          pulumi.String(c.AutoscalingGroupProvider.AutoscalingGroupArn),
+			ManagedDraining:              pulumi.StringPtrFromPtr(c.AutoscalingGroupProvider.ManagedDraining),
+			ManagedScaling:               managedScaling,
+			ManagedTerminationProtection: pulumi.StringPtrFromPtr(c.AutoscalingGroupProvider.ManagedTerminationProtection),
+		},
+		Name: c.Name,
+		Tags: pulumi.ToStringMap(c.Tags),
+	}
+}
+
+// ToArgs converts the cluster configuration into inputs.
+func (c ClusterConfig) ToArgs() *ClusterArgs {
+	var settings ecs.ClusterSettingArray
+	for _, setting := range c.Settings {
+		settings = append(settings, &ecs.ClusterSettingArgs{
+			Name:  pulumi.String(setting.Name),
+			Value: pulumi.String(setting.Value),
+		})
+	}
+
+	var configuration *ecs.ClusterConfigurationArgs
+	if c.Configuration != nil {
+		var logConfiguration *ecs.ClusterConfigurationExecuteCommandConfigurationLogConfigurationArgs
+		if c.Configuration.ExecuteCommand.LogConfiguration != nil {
+			logConfiguration = &ecs.ClusterConfigurationExecuteCommandConfigurationLogConfigurationArgs{
+				CloudWatchEncryptionEnabled: pulumi.BoolPtrFromPtr(c.Configuration.ExecuteCommand.LogConfiguration.CloudWatchEncryptionEnabled),
+				CloudWatchLogGroupName:      pulumi.StringPtrFromPtr(c.Configuration.ExecuteCommand.LogConfiguration.CloudWatchLogGroupName),
+				S3BucketEncryptionEnabled:   pulumi.BoolPtrFromPtr(c.Configuration.ExecuteCommand.LogConfiguration.S3BucketEncryptionEnabled),
+				S3BucketName:                pulumi.StringPtrFromPtr(c.Configuration.ExecuteCommand.LogConfiguration.S3BucketName),
+				S3KeyPrefix:                 pulumi.StringPtrFromPtr(c.Configuration.ExecuteCommand.LogConfiguration.S3KeyPrefix),
+			}
+		}
+		configuration = &ecs.ClusterConfigurationArgs{
+			ExecuteCommandConfiguration: &ecs.ClusterConfigurationExecuteCommandConfigurationArgs{
+				KmsKeyId:         pulumi.StringPtrFromPtr(c.Configuration.ExecuteCommand.KmsKeyID),
+				LogConfiguration: logConfiguration,
+				Logging:          pulumi.StringPtrFromPtr(c.Configuration.ExecuteCommand.Logging),
+			},
+		}
+	}
+
+	var serviceConnectDefaults *ecs.ClusterServiceConnectDefaultsArgs
+	if c.ServiceConnectDefaults != nil {
+		serviceConnectDefaults = &ecs.ClusterServiceConnectDefaultsArgs{
+			Namespace: pulumi.String(c.ServiceConnectDefaults.Namespace),
+		}
+	}
+
+	return &ClusterArgs{
+		Configuration:          configuration,
+		Name:                   pulumi.String(c.Name),
+		ServiceConnectDefaults: serviceConnectDefaults,
+		Settings:               settings,
+		Tags:                   pulumi.ToStringMap(c.Tags),
+	}
+}
+
+// ToArgs converts the cluster capacity provider configuration into inputs.
+func (c ClusterCapacityProviderConfig) ToArgs() *ClusterCapacityProviderArgs {
+	var defaultCapacityProviderStrategies ecs.ClusterCapacityProvidersDefaultCapacityProviderStrategyArray
+	for _, defaultCapacityProviderStrategy := range c.DefaultCapacityProviderStrategies {
+		defaultCapacityProviderStrategies = append(defaultCapacityProviderStrategies, &ecs.ClusterCapacityProvidersDefaultCapacityProviderStrategyArgs{
+			Base:             pulumi.IntPtrFromPtr(defaultCapacityProviderStrategy.Base),
+			CapacityProvider: pulumi.String(defaultCapacityProviderStrategy.CapacityProvider),
+			Weight:           pulumi.IntPtrFromPtr(defaultCapacityProviderStrategy.Weight),
+		})
+	}
+
+	return &ClusterCapacityProviderArgs{
+		CapacityProviders:                 pulumi.ToStringArray(c.CapacityProviders),
+		ClusterName:                       pulumi.String(c.ClusterName),
+		DefaultCapacityProviderStrategies: defaultCapacityProviderStrategies,
+	}
+}
+
+func createServiceConnectConfiguration(config ServiceConfig) *ecs.ServiceServiceConnectConfigurationArgs {
+	var serviceConnectConfiguration *ecs.ServiceServiceConnectConfigurationArgs
+	if config.ServiceConnectConfiguration != nil {
+		var serviceConnectServicesLogConfiguration *ecs.ServiceServiceConnectConfigurationLogConfigurationArgs
+		var serviceConnectLogsSecretOptions ecs.ServiceServiceConnectConfigurationLogConfigurationSecretOptionArray
+		if config.ServiceConnectConfiguration.LogConfiguration != nil {
+			for _, serviceConnectLogsSecretOption := range config.ServiceConnectConfiguration.LogConfiguration.SecretOptions {
+				serviceConnectLogsSecretOptions = append(serviceConnectLogsSecretOptions, &ecs.ServiceServiceConnectConfigurationLogConfigurationSecretOptionArgs{
+					Name:      pulumi.String(serviceConnectLogsSecretOption.Name),
+					ValueFrom: pulumi.String(serviceConnectLogsSecretOption.ValueFrom),
+				})
+			}
+
+			serviceConnectServicesLogConfiguration = &ecs.ServiceServiceConnectConfigurationLogConfigurationArgs{
+				LogDriver:     pulumi.String(config.ServiceConnectConfiguration.LogConfiguration.LogDriver),
+				Options:       pulumi.ToStringMap(config.ServiceConnectConfiguration.LogConfiguration.Options),
+				SecretOptions: serviceConnectLogsSecretOptions,
+			}
+		}
+
+		var serviceConnectServices ecs.ServiceServiceConnectConfigurationServiceArray
+		for _, serviceConnectService := range config.ServiceConnectConfiguration.Services {
+			var clientAliases ecs.ServiceServiceConnectConfigurationServiceClientAliasArray
+			for _, clientAlias := range serviceConnectService.ClientAlias {
+				clientAliases = append(clientAliases, &ecs.ServiceServiceConnectConfigurationServiceClientAliasArgs{
+					DnsName: pulumi.StringPtrFromPtr(clientAlias.DNSName),
+					Port:    pulumi.Int(clientAlias.Port),
+				})
+			}
+
+			var timeout *ecs.ServiceServiceConnectConfigurationServiceTimeoutArgs
+			if serviceConnectService.Timeout != nil {
+				timeout = &ecs.ServiceServiceConnectConfigurationServiceTimeoutArgs{
+					IdleTimeoutSeconds:       pulumi.IntPtrFromPtr(serviceConnectService.Timeout.IdleTimeoutSeconds),
+					PerRequestTimeoutSeconds: pulumi.IntPtrFromPtr(serviceConnectService.Timeout.PerRequestTimeoutSeconds),
+				}
+			}
+
+			var tls *ecs.ServiceServiceConnectConfigurationServiceTlsArgs
+			if serviceConnectService.TLS != nil {
+				tls = &ecs.ServiceServiceConnectConfigurationServiceTlsArgs{
+					IssuerCertAuthority: &ecs.ServiceServiceConnectConfigurationServiceTlsIssuerCertAuthorityArgs{
+						AwsPcaAuthorityArn: pulumi.String(serviceConnectService.TLS.IssuerCertAuthority.AwsPcaAuthorityArn),
+					},
+					KmsKey:  pulumi.StringPtrFromPtr(serviceConnectService.TLS.KmsKey),
+					RoleArn: pulumi.StringPtrFromPtr(serviceConnectService.TLS.RoleArn),
+				}
+			}
+
+			serviceConnectServices = append(serviceConnectServices, &ecs.ServiceServiceConnectConfigurationServiceArgs{
+				ClientAlias:         clientAliases,
+				DiscoveryName:       pulumi.StringPtrFromPtr(serviceConnectService.DiscoveryName),
+				IngressPortOverride: pulumi.IntPtrFromPtr(serviceConnectService.IngressPortOverride),
+				PortName:            pulumi.String(serviceConnectService.PortName),
+				Timeout:             timeout,
+				Tls:                 tls,
+			})
+		}
+
+		serviceConnectConfiguration = &ecs.ServiceServiceConnectConfigurationArgs{
+			Enabled:          pulumi.Bool(config.ServiceConnectConfiguration.Enabled),
+			LogConfiguration: serviceConnectServicesLogConfiguration,
+			Namespace:        pulumi.StringPtrFromPtr(config.ServiceConnectConfiguration.Namespace),
+			Services:         serviceConnectServices,
+		}
+	}
+
+	return serviceConnectConfiguration
+}
+
+// ToArgs converts the service configuration into inputs.
+func (c ServiceConfig) ToArgs() *ServiceArgs {
+	var alarms *ecs.ServiceAlarmsArgs
+	if c.Alarms != nil {
+		alarms = &ecs.ServiceAlarmsArgs{
+			AlarmNames: pulumi.ToStringArray(c.Alarms.AlarmNames),
+			Enable:     pulumi.Bool(c.Alarms.Enable),
+			Rollback:   pulumi.Bool(c.Alarms.Rollback),
+		}
+	}
+
+	var capacityProviderStrategies ecs.ServiceCapacityProviderStrategyArray
+	for _, capacityProviderStrategy := range c.CapacityProviderStrategies {
+		capacityProviderStrategies = append(capacityProviderStrategies, &ecs.ServiceCapacityProviderStrategyArgs{
+			CapacityProvider: pulumi.String(capacityProviderStrategy.CapacityProvider),
+			Base:             pulumi.IntPtrFromPtr(capacityProviderStrategy.Base),
+			Weight:           pulumi.IntPtrFromPtr(capacityProviderStrategy.Weight),
+		})
+	}
+
+	var deploymentCircuitBreaker *ecs.ServiceDeploymentCircuitBreakerArgs
+	if c.DeploymentCircuitBreaker != nil {
+		deploymentCircuitBreaker = &ecs.ServiceDeploymentCircuitBreakerArgs{
+			Enable:   pulumi.Bool(c.DeploymentCircuitBreaker.Enable),
+			Rollback: pulumi.Bool(c.DeploymentCircuitBreaker.Rollback),
+		}
+	}
+
+	var deploymentController *ecs.ServiceDeploymentControllerArgs
+	if c.DeploymentController != nil {
+		deploymentController = &ecs.ServiceDeploymentControllerArgs{
+			Type: pulumi.StringPtrFromPtr(c.DeploymentController.Type),
+		}
+	}
+
+	var loadBalancers ecs.ServiceLoadBalancerArray
+	for _, loadBalancer := range c.LoadBalancers {
+		loadBalancers = append(loadBalancers, &ecs.ServiceLoadBalancerArgs{
+			ContainerName:  pulumi.String(loadBalancer.ContainerName),
+			ContainerPort:  pulumi.Int(loadBalancer.ContainerPort),
+			ElbName:        pulumi.StringPtrFromPtr(loadBalancer.ElbName),
+			TargetGroupArn: pulumi.StringPtrFromPtr(loadBalancer.TargetGroupArn),
+		})
+	}
+
+	var networkConfiguration *ecs.ServiceNetworkConfigurationArgs
+	if c.NetworkConfiguration != nil {
+		networkConfiguration = &ecs.ServiceNetworkConfigurationArgs{
+			AssignPublicIp: pulumi.BoolPtrFromPtr(c.NetworkConfiguration.AssignPublicIP),
+			SecurityGroups: pulumi.ToStringArray(c.NetworkConfiguration.SecurityGroups),
+			Subnets:        pulumi.ToStringArray(c.NetworkConfiguration.Subnets),
+		}
+	}
+
+	var orderedPlacementStrategies ecs.ServiceOrderedPlacementStrategyArray
+	for _, orderedPlacementStrategy := range c.OrderedPlacementStrategies {
+		orderedPlacementStrategies = append(orderedPlacementStrategies, &ecs.ServiceOrderedPlacementStrategyArgs{
+			Field: pulumi.StringPtrFromPtr(orderedPlacementStrategy.Field),
+			Type:  pulumi.String(orderedPlacementStrategy.Type),
+		})
+	}
+
+	var placementConstraints ecs.ServicePlacementConstraintArray
+	for _, placementConstraint := range c.PlacementConstraints {
+		placementConstraints = append(placementConstraints, &ecs.ServicePlacementConstraintArgs{
+			Expression: pulumi.StringPtrFromPtr(placementConstraint.Expression),
+			Type:       pulumi.String(placementConstraint.Type),
+		})
+	}
+
+	var serviceRegistries *ecs.ServiceServiceRegistriesArgs
+	if c.ServiceRegistry != nil {
+		serviceRegistries = &ecs.ServiceServiceRegistriesArgs{
+			ContainerName: pulumi.StringPtrFromPtr(c.ServiceRegistry.ContainerName),
+			ContainerPort: pulumi.IntPtrFromPtr(c.ServiceRegistry.ContainerPort),
+			Port:          pulumi.IntPtrFromPtr(c.ServiceRegistry.Port),
+			RegistryArn:   pulumi.String(c.ServiceRegistry.RegistryArn),
+		}
+	}
+
+	var serviceVolumeConfiguration *ecs.ServiceVolumeConfigurationArgs
+	if c.ServiceVolumeConfiguration != nil {
+		serviceVolumeConfiguration = &ecs.ServiceVolumeConfigurationArgs{
+			ManagedEbsVolume: &ecs.ServiceVolumeConfigurationManagedEbsVolumeArgs{
+				Encrypted:      pulumi.BoolPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.Encrypted),
+				FileSystemType: pulumi.StringPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.FileSystemType),
+				Iops:           pulumi.IntPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.Iops),
+				KmsKeyId:       pulumi.StringPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.KmsKeyID),
+				RoleArn:        pulumi.String(c.ServiceVolumeConfiguration.ManagedEBSVolume.RoleArn),
+				SizeInGb:       pulumi.IntPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.SizeInGB),
+				SnapshotId:     pulumi.StringPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.SnapshotID),
+				Throughput:     pulumi.StringPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.Throughput),
+				VolumeType:     pulumi.StringPtrFromPtr(c.ServiceVolumeConfiguration.ManagedEBSVolume.VolumeType),
+			},
+			Name: pulumi.String(c.ServiceVolumeConfiguration.Name),
+		}
+	}
+
+	args := &ServiceArgs{
+		Alarms:                          alarms,
+		CapacityProviderStrategies:      capacityProviderStrategies,
+		ClusterArn:                      pulumi.String(c.ClusterArn),
+		DeploymentCircuitBreaker:        deploymentCircuitBreaker,
+		DeploymentController:            deploymentController,
+		DeploymentMaximumPercent:        pulumi.IntPtrFromPtr(c.DeploymentMaximumPercent),
+		DeploymentMinimumHealthyPercent: pulumi.IntPtrFromPtr(c.DeploymentMinimumHealthyPercent),
+		DesiredCount:                    pulumi.IntPtrFromPtr(c.DesiredCount),
+		EnableEcsManagedTags:            pulumi.BoolPtrFromPtr(c.EnableEcsManagedTags),
+		EnableExecuteCommand:            pulumi.BoolPtrFromPtr(c.EnableExecuteCommand),
+		ForceNewDeployment:              pulumi.BoolPtrFromPtr(c.ForceNewDeployment),
+		HealthCheckGracePeriodSeconds:   pulumi.IntPtrFromPtr(c.HealthCheckGracePeriodSeconds),
+		IamRole:                         pulumi.StringPtrFromPtr(c.IamRole),
+		LaunchType:                      pulumi.StringPtrFromPtr(c.LaunchType),
+		LoadBalancers:                   loadBalancers,
+		Name:                            pulumi.String(c.Name),
+		NetworkConfiguration:            networkConfiguration,
+		OrderedPlacementStrategies:      orderedPlacementStrategies,
+		PlacementConstraints:            placementConstraints,
+		PlatformVersion:                 pulumi.StringPtrFromPtr(c.PlatformVersion),
+		PropagateTags:                   pulumi.StringPtrFromPtr(c.PropagateTags),
+		SchedulingStrategy:              pulumi.StringPtrFromPtr(c.SchedulingStrategy),
+		ServiceConnectConfiguration:     createServiceConnectConfiguration(c),
+		ServiceRegistry:                 serviceRegistries,
+		ServiceVolumeConfiguration:      serviceVolumeConfiguration,
+		Tags:                            pulumi.ToStringMap(c.Tags),
+		TaskDefinition:                  pulumi.StringPtrFromPtr(c.TaskDefinition),
+		Triggers:                        pulumi.ToStringMap(c.Triggers),
+		WaitForSteadyState:              pulumi.BoolPtrFromPtr(c.WaitForSteadyState),
+	}
+	c.setExtensionArgs(args)
+
+	return args
+}
+
+// setExtensionArgs converts the auto scaling, blue/green, ingress and targets blocks of the service configuration
+// into inputs on args.
+func (c ServiceConfig) setExtensionArgs(args *ServiceArgs) {
+	if c.AutoScaling != nil {
+		args.AutoScaling = c.AutoScaling.toArgs()
+	}
+	if c.BlueGreen != nil {
+		args.BlueGreen = c.BlueGreen.toArgs()
+	}
+	for _, target := range c.Targets {
+		args.Targets = append(args.Targets, target.toArgs())
+	}
+	if c.Ingress != nil {
+		args.Ingress = c.Ingress.toArgs()
+	}
+}
+
+// ToArgs converts the task definition configuration into inputs.
+func (c TaskDefinitionConfig) ToArgs() (*TaskDefinitionArgs, error) {
+	containers := c.ContainerDefinitions
+	if c.Observability != nil {
+		containers = c.Observability.apply(containers, c.NetworkMode)
+	}
+	if c.LogRouting != nil {
+		containers = c.LogRouting.apply(c.Name, containers)
+	}
+
+	containerDefinitions, err := json.Marshal(containers)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal container definitions json: %v", err)
+	}
+
+	var ephemeralStorage *ecs.TaskDefinitionEphemeralStorageArgs
+	if c.EphemeralStorage != nil {
+		ephemeralStorage = &ecs.TaskDefinitionEphemeralStorageArgs{
+			SizeInGib: pulumi.Int(c.EphemeralStorage.SizeInGB),
+		}
+	}
+
+	var inferenceAccelerators ecs.TaskDefinitionInferenceAcceleratorArray
+	for _, inferenceAccelerator := range c.InferenceAccelerators {
+		inferenceAccelerators = append(inferenceAccelerators, &ecs.TaskDefinitionInferenceAcceleratorArgs{
+			DeviceName: pulumi.String(inferenceAccelerator.DeviceName),
+			DeviceType: pulumi.String(inferenceAccelerator.DeviceType),
+		})
+	}
+
+	var placementConstraints ecs.TaskDefinitionPlacementConstraintArray
+	for _, placementConstraint := range c.PlacementConstraints {
+		placementConstraints = append(placementConstraints, &ecs.TaskDefinitionPlacementConstraintArgs{
+			Expression: pulumi.StringPtrFromPtr(placementConstraint.Expression),
+			Type:       pulumi.String(placementConstraint.Type),
+		})
+	}
+
+	var proxyConfiguration *ecs.TaskDefinitionProxyConfigurationArgs
+	if c.ProxyConfiguration != nil {
+		proxyConfiguration = &ecs.TaskDefinitionProxyConfigurationArgs{
+			ContainerName: pulumi.String(c.ProxyConfiguration.ContainerName),
+			Properties:    pulumi.ToStringMap(c.ProxyConfiguration.Properties),
+			Type:          pulumi.StringPtrFromPtr(c.ProxyConfiguration.Type),
+		}
+	}
+
+	var runtimePlatform *ecs.TaskDefinitionRuntimePlatformArgs
+	if c.RuntimePlatform != nil {
+		runtimePlatform = &ecs.TaskDefinitionRuntimePlatformArgs{
+			CpuArchitecture:       pulumi.StringPtrFromPtr(c.RuntimePlatform.CPUArchitecture),
+			OperatingSystemFamily: pulumi.StringPtrFromPtr(c.RuntimePlatform.OperatingSystemFamily),
+		}
+	}
+
+	executionRole, taskRole, err := c.roleArgs(containers)
+	if err != nil {
+		return nil, err
+	}
+
+	var logging *TaskLoggingArgs
+	if c.Logging != nil {
+		logging = c.Logging.toArgs(containers)
+	}
+
+	var secrets []*TaskSecretArgs
+	for _, secret := range c.Secrets {
+		secrets = append(secrets, secret.toArgs())
+	}
+
+	return &TaskDefinitionArgs{
+		ContainerDefinitions:    pulumi.String(containerDefinitions),
+		CPU:                     pulumi.StringPtrFromPtr(c.CPU),
+		EphemeralStorage:        ephemeralStorage,
+		ExecutionRole:           executionRole,
+		ExecutionRoleArn:        pulumi.StringPtrFromPtr(c.ExecutionRoleArn),
+		InferenceAccelerators:   inferenceAccelerators,
+		IpcMode:                 pulumi.StringPtrFromPtr(c.IpcMode),
+		Logging:                 logging,
+		Memory:                  pulumi.StringPtrFromPtr(c.Memory),
+		Name:                    pulumi.String(c.Name),
+		NetworkMode:             pulumi.StringPtrFromPtr(c.NetworkMode),
+		PidMode:                 pulumi.StringPtrFromPtr(c.PidMode),
+		PlacementConstraints:    placementConstraints,
+		ProxyConfiguration:      proxyConfiguration,
+		RequiresCompatibilities: pulumi.ToStringArray(c.RequiresCompatibilities),
+		RuntimePlatform:         runtimePlatform,
+		Secrets:                 secrets,
+		SkipDestroy:             pulumi.BoolPtrFromPtr(c.SkipDestroy),
+		Tags:                    pulumi.ToStringMap(c.Tags),
+		TaskRole:                taskRole,
+		TaskRoleArn:             pulumi.StringPtrFromPtr(c.TaskRoleArn),
+		TrackLatest:             pulumi.BoolPtrFromPtr(c.TrackLatest),
+		Volumes:                 c.volumeArgs(),
+	}, nil
+}
+
+// volumeArgs converts the volumes of the task definition configuration into inputs.
+func (c TaskDefinitionConfig) volumeArgs() ecs.TaskDefinitionVolumeArray {
+	var volumes ecs.TaskDefinitionVolumeArray
+	for _, volume := range c.Volumes {
+		var dockerVolumeConfiguration *ecs.TaskDefinitionVolumeDockerVolumeConfigurationArgs
+		if volume.DockerVolumeConfiguration != nil {
+			dockerVolumeConfiguration = &ecs.TaskDefinitionVolumeDockerVolumeConfigurationArgs{
+				Autoprovision: pulumi.BoolPtrFromPtr(volume.DockerVolumeConfiguration.Autoprovision),
+				Driver:        pulumi.StringPtrFromPtr(volume.DockerVolumeConfiguration.Driver),
+				DriverOpts:    pulumi.ToStringMap(volume.DockerVolumeConfiguration.DriverOpts),
+				Labels:        pulumi.ToStringMap(volume.DockerVolumeConfiguration.Labels),
+				Scope:         pulumi.StringPtrFromPtr(volume.DockerVolumeConfiguration.Scope),
+			}
+		}
+
+		var efsVolumeConfiguration *ecs.TaskDefinitionVolumeEfsVolumeConfigurationArgs
+		if volume.EfsVolumeConfiguration != nil {
+			var authorizationConfig *ecs.TaskDefinitionVolumeEfsVolumeConfigurationAuthorizationConfigArgs
+			if volume.EfsVolumeConfiguration.AuthorizationConfig != nil {
+				authorizationConfig = &ecs.TaskDefinitionVolumeEfsVolumeConfigurationAuthorizationConfigArgs{
+					AccessPointId: pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.AuthorizationConfig.AccessPointID),
+					Iam:           pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.AuthorizationConfig.Iam),
+				}
+			}
+
+			efsVolumeConfiguration = &ecs.TaskDefinitionVolumeEfsVolumeConfigurationArgs{
+				AuthorizationConfig:   authorizationConfig,
+				FileSystemId:          pulumi.String(volume.EfsVolumeConfiguration.FileSystemID),
+				RootDirectory:         pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.RootDirectory),
+				TransitEncryption:     pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.TransitEncryption),
+				TransitEncryptionPort: pulumi.IntPtrFromPtr(volume.EfsVolumeConfiguration.TransitEncryptionPort),
+			}
+		}
+
+		var fsxVolumeConfiguration *ecs.TaskDefinitionVolumeFsxWindowsFileServerVolumeConfigurationArgs
+		if volume.FsxWindowsFileServerVolumeConfiguration != nil {
+			fsxVolumeConfiguration = &ecs.TaskDefinitionVolumeFsxWindowsFileServerVolumeConfigurationArgs{
+				AuthorizationConfig: &ecs.TaskDefinitionVolumeFsxWindowsFileServerVolumeConfigurationAuthorizationConfigArgs{
+					CredentialsParameter: pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.AuthorizationConfig.CredentialsParameter),
+					Domain:               pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.AuthorizationConfig.Domain),
+				},
+				FileSystemId:  pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.FileSystemID),
+				RootDirectory: pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.RootDirectory),
+			}
+		}
+
+		volumes = append(volumes, &ecs.TaskDefinitionVolumeArgs{
+			DockerVolumeConfiguration:               dockerVolumeConfiguration,
+			EfsVolumeConfiguration:                  efsVolumeConfiguration,
+			FsxWindowsFileServerVolumeConfiguration: fsxVolumeConfiguration,
+			HostPath:                                pulumi.StringPtrFromPtr(volume.HostPath),
+			Name:                                    pulumi.String(volume.Name),
+		})
+	}
+
+	return volumes
+}
+
+// roleArgs converts the roles of the task definition configuration into inputs. The execution role is allowed to
+// start the containers and the task role to use the log routing and observability destinations.
+func (c TaskDefinitionConfig) roleArgs(containers []ContainerDefinition) (executionRole, taskRole *TaskDefinitionRoleArgs, err error) {
+	if c.ExecutionRole != nil {
+		executionRole, err = c.ExecutionRole.toArgs(executionRoleStatements(containers))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if c.TaskRole != nil {
+		var statements []policyStatement
+		if c.LogRouting != nil {
+			statements = append(statements, c.LogRouting.taskRoleStatements()...)
+		}
+		if c.Observability != nil {
+			statements = append(statements, c.Observability.taskRoleStatements()...)
+		}
+		taskRole, err = c.TaskRole.toArgs(statements)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return executionRole, taskRole, nil
+}
+
+// ToArgs converts the task set configuration into inputs.
+func (c TaskSetConfig) ToArgs() *TaskSetArgs {
+	var capacityProviderStrategies ecs.TaskSetCapacityProviderStrategyArray
+	for _, capacityProviderStrategy := range c.CapacityProviderStrategies {
+		capacityProviderStrategies = append(capacityProviderStrategies, &ecs.TaskSetCapacityProviderStrategyArgs{
+			Base:             pulumi.IntPtrFromPtr(capacityProviderStrategy.Base),
+			CapacityProvider: pulumi.String(capacityProviderStrategy.CapacityProvider),
+			Weight:           pulumi.Int(capacityProviderStrategy.Weight),
+		})
+	}
+
+	var loadBalancers ecs.TaskSetLoadBalancerArray
+	for _, loadBalancer := range c.LoadBalancers {
+		loadBalancers = append(loadBalancers, &ecs.TaskSetLoadBalancerArgs{
+			ContainerName:    pulumi.String(loadBalancer.ContainerName),
+			ContainerPort:    pulumi.IntPtrFromPtr(loadBalancer.ContainerPort),
+			LoadBalancerName: pulumi.StringPtrFromPtr(loadBalancer.LoadBalancerName),
+			TargetGroupArn:   pulumi.StringPtrFromPtr(loadBalancer.TargetGroupArn),
+		})
+	}
+
+	var networkConfiguration *ecs.TaskSetNetworkConfigurationArgs
+	if c.NetworkConfiguration != nil {
+		networkConfiguration = &ecs.TaskSetNetworkConfigurationArgs{
+			AssignPublicIp: pulumi.BoolPtrFromPtr(c.NetworkConfiguration.AssignPublicIP),
+			SecurityGroups: pulumi.ToStringArray(c.NetworkConfiguration.SecurityGroups),
+			Subnets:        pulumi.ToStringArray(c.NetworkConfiguration.Subnets),
+		}
+	}
+
+	var scale *ecs.TaskSetScaleArgs
+	if c.Scale != nil {
+		scale = &ecs.TaskSetScaleArgs{
+			Unit:  pulumi.StringPtrFromPtr(c.Scale.Unit),
+			Value: pulumi.Float64PtrFromPtr(c.Scale.Value),
+		}
+	}
+
+	var serviceRegistries *ecs.TaskSetServiceRegistriesArgs
+	if c.ServiceRegistries != nil {
+		serviceRegistries = &ecs.TaskSetServiceRegistriesArgs{
+			ContainerName: pulumi.StringPtrFromPtr(c.ServiceRegistries.ContainerName),
+			ContainerPort: pulumi.IntPtrFromPtr(c.ServiceRegistries.ContainerPort),
+			Port:          pulumi.IntPtrFromPtr(c.ServiceRegistries.Port),
+			RegistryArn:   pulumi.String(c.ServiceRegistries.RegistryArn),
+		}
+	}
+
+	return &TaskSetArgs{
+		CapacityProviderStrategies: capacityProviderStrategies,
+		Cluster:                    pulumi.String(c.Cluster),
+		ExternalID:                 pulumi.StringPtrFromPtr(c.ExternalID),
+		ForceDelete:                pulumi.BoolPtrFromPtr(c.ForceDelete),
+		LaunchType:                 pulumi.StringPtrFromPtr(c.LaunchType),
+		LoadBalancers:              loadBalancers,
+		Name:                       c.Name,
+		NetworkConfiguration:       networkConfiguration,
+		PlatformVersion:            pulumi.StringPtrFromPtr(c.PlatformVersion),
+		Scale:                      scale,
+		Service:                    pulumi.String(c.Service),
+		ServiceRegistries:          serviceRegistries,
+		Tags:                       pulumi.ToStringMap(c.Tags),
+		TaskDefinition:             pulumi.String(c.TaskDefinition),
+		WaitUntilStable:            pulumi.BoolPtrFromPtr(c.WaitUntilStable),
+		WaitUntilStableTimeout:     pulumi.StringPtrFromPtr(c.WaitUntilStableTimeout),
+	}
+}
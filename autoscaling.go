@@ -0,0 +1,336 @@
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/appautoscaling"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// ServiceAutoScalingConfig defines how the desired count of an AWS ECS service is scaled between MinCapacity and
+// MaxCapacity by Application Auto Scaling.
+type ServiceAutoScalingConfig struct {
+	MaxCapacity      int                            `json:"maxCapacity"`
+	MinCapacity      int                            `json:"minCapacity"`
+	ScheduledActions []ServiceScheduledActionConfig `json:"scheduledActions,omitempty"`
+	StepScaling      []ServiceStepScalingConfig     `json:"stepScaling,omitempty"`
+	TargetTracking   []ServiceTargetTrackingConfig  `json:"targetTracking,omitempty"`
+}
+
+// ServiceTargetTrackingConfig defines a target tracking policy that keeps a predefined metric of the service at
+// TargetValue. PredefinedMetricType is one of ECSServiceAverageCPUUtilization, ECSServiceAverageMemoryUtilization
+// and ALBRequestCountPerTarget; the latter requires the ResourceLabel of the target group.
+type ServiceTargetTrackingConfig struct {
+	DisableScaleIn       *bool   `json:"disableScaleIn,omitempty"`
+	Name                 *string `json:"name,omitempty"`
+	PredefinedMetricType string  `json:"predefinedMetricType"`
+	ResourceLabel        *string `json:"resourceLabel,omitempty"`
+	ScaleInCooldown      *int    `json:"scaleInCooldown,omitempty"`
+	ScaleOutCooldown     *int    `json:"scaleOutCooldown,omitempty"`
+	TargetValue          float64 `json:"targetValue"`
+}
+
+// ServiceStepScalingConfig defines a step scaling policy together with the CloudWatch alarm that triggers it.
+type ServiceStepScalingConfig struct {
+	AdjustmentType        string                     `json:"adjustmentType"`
+	Alarm                 ServiceScalingAlarmConfig  `json:"alarm"`
+	Cooldown              *int                       `json:"cooldown,omitempty"`
+	MetricAggregationType *string                    `json:"metricAggregationType,omitempty"`
+	Name                  string                     `json:"name"`
+	Steps                 []ServiceScalingStepConfig `json:"steps"`
+}
+
+// ServiceScalingAlarmConfig defines the CloudWatch alarm of a step scaling policy. The dimensions default to the
+// ClusterName and ServiceName of the service.
+type ServiceScalingAlarmConfig struct {
+	ComparisonOperator string            `json:"comparisonOperator"`
+	Dimensions         map[string]string `json:"dimensions,omitempty"`
+	EvaluationPeriods  int               `json:"evaluationPeriods"`
+	MetricName         string            `json:"metricName"`
+	Namespace          string            `json:"namespace"`
+	Period             int               `json:"period"`
+	Statistic          string            `json:"statistic"`
+	Threshold          float64           `json:"threshold"`
+}
+
+// ServiceScalingStepConfig defines one step of a step scaling policy. The bounds are relative to the alarm threshold.
+type ServiceScalingStepConfig struct {
+	MetricIntervalLowerBound *float64 `json:"metricIntervalLowerBound,omitempty"`
+	MetricIntervalUpperBound *float64 `json:"metricIntervalUpperBound,omitempty"`
+	ScalingAdjustment        int      `json:"scalingAdjustment"`
+}
+
+// ServiceScheduledActionConfig defines a scheduled change of the capacity limits of the service.
+type ServiceScheduledActionConfig struct {
+	MaxCapacity *int    `json:"maxCapacity,omitempty"`
+	MinCapacity *int    `json:"minCapacity,omitempty"`
+	Name        string  `json:"name"`
+	Schedule    string  `json:"schedule"`
+	Timezone    *string `json:"timezone,omitempty"`
+}
+
+// ServiceAutoScalingArgs defines inputs for scaling the desired count of an AWS ECS service. The step scaling
+// policies and scheduled actions are plain values, because their names determine the names of the resources.
+type ServiceAutoScalingArgs struct {
+	MaxCapacity      pulumi.IntInput
+	MinCapacity      pulumi.IntInput
+	ScheduledActions []ServiceScheduledActionConfig
+	StepScaling      []ServiceStepScalingConfig
+	TargetTracking   []*ServiceTargetTrackingArgs
+}
+
+// ServiceTargetTrackingArgs defines inputs for a target tracking policy. The name is a plain value, because it
+// determines the names of the resource and the AWS policy.
+type ServiceTargetTrackingArgs struct {
+	DisableScaleIn       pulumi.BoolPtrInput
+	Name                 string
+	PredefinedMetricType pulumi.StringInput
+	ResourceLabel        pulumi.StringPtrInput
+	ScaleInCooldown      pulumi.IntPtrInput
+	ScaleOutCooldown     pulumi.IntPtrInput
+	TargetValue          pulumi.Float64Input
+}
+
+// toArgs converts the auto scaling configuration into inputs.
+func (c ServiceAutoScalingConfig) toArgs() *ServiceAutoScalingArgs {
+	args := &ServiceAutoScalingArgs{
+		MaxCapacity:      pulumi.Int(c.MaxCapacity),
+		MinCapacity:      pulumi.Int(c.MinCapacity),
+		ScheduledActions: c.ScheduledActions,
+		StepScaling:      c.StepScaling,
+	}
+	for _, policy := range c.TargetTracking {
+		args.TargetTracking = append(args.TargetTracking, &ServiceTargetTrackingArgs{
+			DisableScaleIn:       pulumi.BoolPtrFromPtr(policy.DisableScaleIn),
+			Name:                 policy.policyName(),
+			PredefinedMetricType: pulumi.String(policy.PredefinedMetricType),
+			ResourceLabel:        pulumi.StringPtrFromPtr(policy.ResourceLabel),
+			ScaleInCooldown:      pulumi.IntPtrFromPtr(policy.ScaleInCooldown),
+			ScaleOutCooldown:     pulumi.IntPtrFromPtr(policy.ScaleOutCooldown),
+			TargetValue:          pulumi.Float64(policy.TargetValue),
+		})
+	}
+	return args
+}
+
+// policyName returns the name of the target tracking policy, which defaults to its predefined metric type.
+func (c ServiceTargetTrackingConfig) policyName() string {
+	if c.Name != nil {
+		return *c.Name
+	}
+	return c.PredefinedMetricType
+}
+
+func (c ServiceAutoScalingConfig) validate(v *validator, path string) {
+	v.between(path+".minCapacity", &c.MinCapacity, 0, 100000)
+	v.between(path+".maxCapacity", &c.MaxCapacity, 1, 100000)
+	if c.MinCapacity > c.MaxCapacity {
+		v.errorf(path+".minCapacity", "(%d) must not be greater than maxCapacity (%d)", c.MinCapacity, c.MaxCapacity)
+	}
+	if len(c.TargetTracking)+len(c.StepScaling)+len(c.ScheduledActions) == 0 {
+		v.errorf(path, "must define at least one of targetTracking, stepScaling and scheduledActions")
+	}
+
+	// Target tracking and step scaling policies share the names of their resources and AWS policies.
+	policies := map[string]bool{}
+	for i, policy := range c.TargetTracking {
+		policyPath := fmt.Sprintf("%s.targetTracking[%d]", path, i)
+		if policies[policy.policyName()] {
+			v.errorf(policyPath+".name", "%q is used by another scaling policy", policy.policyName())
+		}
+		policies[policy.policyName()] = true
+		policy.validate(v, policyPath)
+	}
+	for i, policy := range c.StepScaling {
+		policyPath := fmt.Sprintf("%s.stepScaling[%d]", path, i)
+		if policy.Name != "" && policies[policy.Name] {
+			v.errorf(policyPath+".name", "%q is used by another scaling policy", policy.Name)
+		}
+		policies[policy.Name] = true
+		policy.validate(v, policyPath)
+	}
+
+	actions := map[string]bool{}
+	for i, action := range c.ScheduledActions {
+		actionPath := fmt.Sprintf("%s.scheduledActions[%d]", path, i)
+		if action.Name != "" && actions[action.Name] {
+			v.errorf(actionPath+".name", "%q is used by another scheduled action", action.Name)
+		}
+		actions[action.Name] = true
+		action.validate(v, actionPath)
+	}
+}
+
+func (c ServiceTargetTrackingConfig) validate(v *validator, path string) {
+	v.oneOf(path+".predefinedMetricType", &c.PredefinedMetricType,
+		"ECSServiceAverageCPUUtilization", "ECSServiceAverageMemoryUtilization", "ALBRequestCountPerTarget")
+	if c.PredefinedMetricType == "ALBRequestCountPerTarget" && c.ResourceLabel == nil {
+		v.errorf(path+".resourceLabel", "is required for ALBRequestCountPerTarget")
+	}
+	if c.TargetValue <= 0 {
+		v.errorf(path+".targetValue", "must be positive, got %g", c.TargetValue)
+	}
+}
+
+func (c ServiceStepScalingConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	v.oneOf(path+".adjustmentType", &c.AdjustmentType, "ChangeInCapacity", "ExactCapacity", "PercentChangeInCapacity")
+	v.oneOf(path+".metricAggregationType", c.MetricAggregationType, "Average", "Minimum", "Maximum")
+	if len(c.Steps) == 0 {
+		v.errorf(path+".steps", "must contain at least one step")
+	}
+	for i, step := range c.Steps {
+		if step.MetricIntervalLowerBound == nil && step.MetricIntervalUpperBound == nil && len(c.Steps) > 1 {
+			v.errorf(fmt.Sprintf("%s.steps[%d]", path, i), "must set metricIntervalLowerBound or metricIntervalUpperBound")
+		}
+	}
+	c.Alarm.validate(v, path+".alarm")
+}
+
+func (c ServiceScalingAlarmConfig) validate(v *validator, path string) {
+	v.oneOf(path+".comparisonOperator", &c.ComparisonOperator,
+		"GreaterThanOrEqualToThreshold", "GreaterThanThreshold", "LessThanThreshold", "LessThanOrEqualToThreshold")
+	v.required(path+".metricName", c.MetricName)
+	v.required(path+".namespace", c.Namespace)
+	v.oneOf(path+".statistic", &c.Statistic, "SampleCount", "Average", "Sum", "Minimum", "Maximum")
+	v.between(path+".evaluationPeriods", &c.EvaluationPeriods, 1, 1000)
+	if c.Period < 10 || (c.Period > 30 && c.Period%60 != 0) {
+		v.errorf(path+".period", "must be 10, 30 or a multiple of 60, got %d", c.Period)
+	}
+}
+
+func (c ServiceScheduledActionConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	v.required(path+".schedule", c.Schedule)
+	if c.MinCapacity == nil && c.MaxCapacity == nil {
+		v.errorf(path, "must set minCapacity or maxCapacity")
+	}
+	if c.MinCapacity != nil && c.MaxCapacity != nil && *c.MinCapacity > *c.MaxCapacity {
+		v.errorf(path+".minCapacity", "(%d) must not be greater than maxCapacity (%d)", *c.MinCapacity, *c.MaxCapacity)
+	}
+}
+
+// newServiceAutoScaling registers the service as a scalable target and creates its scaling policies and scheduled
+// actions as children of parent.
+func newServiceAutoScaling(ctx *pulumi.Context, name string, args *ServiceAutoScalingArgs, clusterArn, serviceName pulumi.StringOutput, tags pulumi.StringMapInput, parent pulumi.Resource) error {
+	clusterName := clusterNameOf(clusterArn)
+
+	target, err := appautoscaling.NewTarget(ctx, name, &appautoscaling.TargetArgs{
+		MaxCapacity:       args.MaxCapacity,
+		MinCapacity:       args.MinCapacity,
+		ResourceId:        pulumi.Sprintf("service/%s/%s", clusterName, serviceName),
+		ScalableDimension: pulumi.String("ecs:service:DesiredCount"),
+		ServiceNamespace:  pulumi.String("ecs"),
+		Tags:              tags,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return fmt.Errorf("failed to create new scalable target: %v", err)
+	}
+
+	for _, policy := range args.TargetTracking {
+		_, err = appautoscaling.NewPolicy(ctx, name+"-"+policy.Name, &appautoscaling.PolicyArgs{
+			Name:              pulumi.Sprintf("%s-%s", serviceName, policy.Name),
+			PolicyType:        pulumi.String("TargetTrackingScaling"),
+			ResourceId:        target.ResourceId,
+			ScalableDimension: target.ScalableDimension,
+			ServiceNamespace:  target.ServiceNamespace,
+			TargetTrackingScalingPolicyConfiguration: &appautoscaling.PolicyTargetTrackingScalingPolicyConfigurationArgs{
+				DisableScaleIn: policy.DisableScaleIn,
+				PredefinedMetricSpecification: &appautoscaling.PolicyTargetTrackingScalingPolicyConfigurationPredefinedMetricSpecificationArgs{
+					PredefinedMetricType: policy.PredefinedMetricType,
+					ResourceLabel:        policy.ResourceLabel,
+				},
+				ScaleInCooldown:  policy.ScaleInCooldown,
+				ScaleOutCooldown: policy.ScaleOutCooldown,
+				TargetValue:      policy.TargetValue,
+			},
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return fmt.Errorf("failed to create new target tracking policy: %v", err)
+		}
+	}
+
+	for _, policy := range args.StepScaling {
+		var steps appautoscaling.PolicyStepScalingPolicyConfigurationStepAdjustmentArray
+		for _, step := range policy.Steps {
+			steps = append(steps, &appautoscaling.PolicyStepScalingPolicyConfigurationStepAdjustmentArgs{
+				MetricIntervalLowerBound: formatBound(step.MetricIntervalLowerBound),
+				MetricIntervalUpperBound: formatBound(step.MetricIntervalUpperBound),
+				ScalingAdjustment:        pulumi.Int(step.ScalingAdjustment),
+			})
+		}
+
+		scalingPolicy, err := appautoscaling.NewPolicy(ctx, name+"-"+policy.Name, &appautoscaling.PolicyArgs{
+			Name:              pulumi.Sprintf("%s-%s", serviceName, policy.Name),
+			PolicyType:        pulumi.String("StepScaling"),
+			ResourceId:        target.ResourceId,
+			ScalableDimension: target.ScalableDimension,
+			ServiceNamespace:  target.ServiceNamespace,
+			StepScalingPolicyConfiguration: &appautoscaling.PolicyStepScalingPolicyConfigurationArgs{
+				AdjustmentType:        pulumi.String(policy.AdjustmentType),
+				Cooldown:              pulumi.IntPtrFromPtr(policy.Cooldown),
+				MetricAggregationType: pulumi.StringPtrFromPtr(policy.MetricAggregationType),
+				StepAdjustments:       steps,
+			},
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return fmt.Errorf("failed to create new step scaling policy: %v", err)
+		}
+
+		alarm := policy.Alarm
+		dimensions := pulumi.StringMap{
+			"ClusterName": clusterName,
+			"ServiceName": serviceName,
+		}
+		if alarm.Dimensions != nil {
+			dimensions = pulumi.ToStringMap(alarm.Dimensions)
+		}
+
+		_, err = cloudwatch.NewMetricAlarm(ctx, name+"-"+policy.Name, &cloudwatch.MetricAlarmArgs{
+			AlarmActions:       pulumi.Array{scalingPolicy.Arn},
+			ComparisonOperator: pulumi.String(alarm.ComparisonOperator),
+			Dimensions:         dimensions,
+			EvaluationPeriods:  pulumi.Int(alarm.EvaluationPeriods),
+			MetricName:         pulumi.String(alarm.MetricName),
+			Name:               pulumi.Sprintf("%s-%s", serviceName, policy.Name),
+			Namespace:          pulumi.String(alarm.Namespace),
+			Period:             pulumi.Int(alarm.Period),
+			Statistic:          pulumi.String(alarm.Statistic),
+			Tags:               tags,
+			Threshold:          pulumi.Float64(alarm.Threshold),
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return fmt.Errorf("failed to create new metric alarm: %v", err)
+		}
+	}
+
+	for _, action := range args.ScheduledActions {
+		_, err = appautoscaling.NewScheduledAction(ctx, name+"-"+action.Name, &appautoscaling.ScheduledActionArgs{
+			Name:              pulumi.Sprintf("%s-%s", serviceName, action.Name),
+			ResourceId:        target.ResourceId,
+			ScalableDimension: target.ScalableDimension,
+			ScalableTargetAction: &appautoscaling.ScheduledActionScalableTargetActionArgs{
+				MaxCapacity: pulumi.IntPtrFromPtr(action.MaxCapacity),
+				MinCapacity: pulumi.IntPtrFromPtr(action.MinCapacity),
+			},
+			Schedule:         pulumi.String(action.Schedule),
+			ServiceNamespace: target.ServiceNamespace,
+			Timezone:         pulumi.StringPtrFromPtr(action.Timezone),
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return fmt.Errorf("failed to create new scheduled action: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// formatBound formats a step adjustment bound, which the provider takes as a string.
+func formatBound(bound *float64) pulumi.StringPtrInput {
+	if bound == nil {
+		return nil
+	}
+	return pulumi.String(strconv.FormatFloat(*bound, 'f', -1, 64))
+}
@@ -0,0 +1,184 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+const serviceAutoScalingJSON = `{
+  "minCapacity": 1,
+  "maxCapacity": 10,
+  "targetTracking": [
+    { "predefinedMetricType": "ECSServiceAverageCPUUtilization", "targetValue": 60, "scaleInCooldown": 300 }
+  ],
+  "stepScaling": [
+    {
+      "name": "queue-depth",
+      "adjustmentType": "ChangeInCapacity",
+      "alarm": {
+        "comparisonOperator": "GreaterThanThreshold",
+        "evaluationPeriods": 2,
+        "metricName": "ApproximateNumberOfMessagesVisible",
+        "namespace": "AWS/SQS",
+        "period": 60,
+        "statistic": "Average",
+        "threshold": 100,
+        "dimensions": { "QueueName": "jobs" }
+      },
+      "steps": [
+        { "metricIntervalLowerBound": 0, "metricIntervalUpperBound": 400, "scalingAdjustment": 1 },
+        { "metricIntervalLowerBound": 400, "scalingAdjustment": 3 }
+      ]
+    }
+  ],
+  "scheduledActions": [
+    { "name": "nightly", "schedule": "cron(0 22 * * ? *)", "maxCapacity": 2, "timezone": "Europe/Amsterdam" }
+  ]
+}`
+
+// TestNewServiceAutoScaling is a unit test that checks the scalable target, policies, alarm and scheduled action created for a service with an autoScaling block.
+func TestNewServiceAutoScaling(t *testing.T) {
+	serviceConfig, create := serviceFixture(t)
+	serviceConfig.AutoScaling = &ServiceAutoScalingConfig{}
+	assert.NoError(t, DecodeConfig([]byte(serviceAutoScalingJSON), serviceConfig.AutoScaling))
+
+	m := create(nil)
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, []string{"desiredCount"}, service.IgnoreChanges)
+
+	target := m.resource(t, "aws:appautoscaling/target:Target", "my-ecs-service")
+	assert.Equal(t, componentURN(ServiceType, "my-ecs-service"), target.Parent)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"maxCapacity":       10,
+		"minCapacity":       1,
+		"resourceId":        "service/my-cluster/my-ecs-service",
+		"scalableDimension": "ecs:service:DesiredCount",
+		"serviceNamespace":  "ecs",
+		"tags":              map[string]interface{}{"Environment": "Production", "Team": "DevOps"},
+	}), target.Inputs)
+
+	cpu := m.resource(t, "aws:appautoscaling/policy:Policy", "my-ecs-service-ECSServiceAverageCPUUtilization")
+	assert.Equal(t, resource.NewStringProperty("TargetTrackingScaling"), cpu.Inputs["policyType"])
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"predefinedMetricSpecification": map[string]interface{}{"predefinedMetricType": "ECSServiceAverageCPUUtilization"},
+		"scaleInCooldown":               300,
+		"targetValue":                   60,
+	}), cpu.Inputs["targetTrackingScalingPolicyConfiguration"].ObjectValue())
+
+	queueDepth := m.resource(t, "aws:appautoscaling/policy:Policy", "my-ecs-service-queue-depth")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"adjustmentType": "ChangeInCapacity",
+		"stepAdjustments": []interface{}{
+			map[string]interface{}{"metricIntervalLowerBound": "0", "metricIntervalUpperBound": "400", "scalingAdjustment": 1},
+			map[string]interface{}{"metricIntervalLowerBound": "400", "scalingAdjustment": 3},
+		},
+	}), queueDepth.Inputs["stepScalingPolicyConfiguration"].ObjectValue())
+
+	alarm := m.resource(t, "aws:cloudwatch/metricAlarm:MetricAlarm", "my-ecs-service-queue-depth")
+	assert.Equal(t, resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-ecs-service-queue-depth"),
+	}), alarm.Inputs["alarmActions"])
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"QueueName": "jobs"}), alarm.Inputs["dimensions"].ObjectValue())
+
+	nightly := m.resource(t, "aws:appautoscaling/scheduledAction:ScheduledAction", "my-ecs-service-nightly")
+	assert.Equal(t, resource.NewStringProperty("cron(0 22 * * ? *)"), nightly.Inputs["schedule"])
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"maxCapacity": 2}), nightly.Inputs["scalableTargetAction"].ObjectValue())
+}
+
+// TestNewServiceFromArgsAutoScaling is a unit test that checks that target tracking policies accept the resource label of a target group created in the same program.
+func TestNewServiceFromArgsAutoScaling(t *testing.T) {
+	serviceConfig, create := serviceFixture(t)
+	serviceConfig.AutoScaling = &ServiceAutoScalingConfig{
+		MaxCapacity: 10,
+		MinCapacity: 1,
+		TargetTracking: []ServiceTargetTrackingConfig{
+			{PredefinedMetricType: "ALBRequestCountPerTarget", ResourceLabel: pulumi.StringRef("placeholder"), TargetValue: 1000},
+		},
+	}
+
+	m := create(func(ctx *pulumi.Context, args *ServiceArgs) error {
+		args.AutoScaling.TargetTracking[0].ResourceLabel = pulumi.Sprintf("app/my-alb/0123456789abcdef/%s", pulumi.String("targetgroup/my-tg/0123456789abcdef"))
+		return nil
+	})
+
+	policy := m.resource(t, "aws:appautoscaling/policy:Policy", "my-ecs-service-ALBRequestCountPerTarget")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"predefinedMetricType": "ALBRequestCountPerTarget",
+		"resourceLabel":        "app/my-alb/0123456789abcdef/targetgroup/my-tg/0123456789abcdef",
+	}), policy.Inputs["targetTrackingScalingPolicyConfiguration"].ObjectValue()["predefinedMetricSpecification"].ObjectValue())
+}
+
+// TestNewServiceWithoutAutoScaling is a unit test that checks that the desired count of a service without an autoScaling block is still managed.
+func TestNewServiceWithoutAutoScaling(t *testing.T) {
+	_, create := serviceFixture(t)
+
+	m := create(nil)
+
+	assert.Empty(t, m.resource(t, "aws:ecs/service:Service", "my-ecs-service").IgnoreChanges)
+	assert.Equal(t, 0, m.count("aws:appautoscaling/target:Target"))
+}
+
+// TestValidateServiceAutoScaling is a unit test that checks the checks of the autoScaling block of a service.
+func TestValidateServiceAutoScaling(t *testing.T) {
+	serviceConfig, _ := serviceFixture(t)
+	serviceConfig.AutoScaling = &ServiceAutoScalingConfig{
+		MaxCapacity: 2,
+		MinCapacity: 4,
+		TargetTracking: []ServiceTargetTrackingConfig{
+			{PredefinedMetricType: "ALBRequestCountPerTarget", TargetValue: 1000},
+		},
+		ScheduledActions: []ServiceScheduledActionConfig{
+			{Name: "nightly", Schedule: "cron(0 22 * * ? *)"},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"service.autoScaling.minCapacity":                     "(4) must not be greater than maxCapacity (2)",
+		"service.autoScaling.scheduledActions[0]":             "must set minCapacity or maxCapacity",
+		"service.autoScaling.targetTracking[0].resourceLabel": "is required for ALBRequestCountPerTarget",
+	}, fieldErrors(t, serviceConfig.Validate()))
+}
+
+// TestValidateServiceAutoScalingNames is a unit test that checks that scaling policies and scheduled actions with the same name are rejected.
+func TestValidateServiceAutoScalingNames(t *testing.T) {
+	serviceConfig, _ := serviceFixture(t)
+
+	alarm := ServiceScalingAlarmConfig{
+		ComparisonOperator: "GreaterThanThreshold",
+		EvaluationPeriods:  1,
+		MetricName:         "CPUUtilization",
+		Namespace:          "AWS/ECS",
+		Period:             60,
+		Statistic:          "Average",
+	}
+	steps := []ServiceScalingStepConfig{{ScalingAdjustment: 1}}
+	serviceConfig.AutoScaling = &ServiceAutoScalingConfig{
+		MaxCapacity: 4,
+		MinCapacity: 1,
+		TargetTracking: []ServiceTargetTrackingConfig{
+			{PredefinedMetricType: "ALBRequestCountPerTarget", ResourceLabel: pulumi.StringRef("app/my-alb/1/targetgroup/a/1"), TargetValue: 1000},
+			{PredefinedMetricType: "ALBRequestCountPerTarget", ResourceLabel: pulumi.StringRef("app/my-alb/1/targetgroup/b/2"), TargetValue: 1000},
+			{PredefinedMetricType: "ECSServiceAverageCPUUtilization", TargetValue: 50},
+		},
+		StepScaling: []ServiceStepScalingConfig{
+			{Name: "ECSServiceAverageCPUUtilization", AdjustmentType: "ChangeInCapacity", Alarm: alarm, Steps: steps},
+			{Name: "scale-out", AdjustmentType: "ChangeInCapacity", Alarm: alarm, Steps: steps},
+			{Name: "scale-out", AdjustmentType: "ChangeInCapacity", Alarm: alarm, Steps: steps},
+		},
+		ScheduledActions: []ServiceScheduledActionConfig{
+			{Name: "nightly", Schedule: "cron(0 22 * * ? *)", MaxCapacity: pulumi.IntRef(2)},
+			{Name: "nightly", Schedule: "cron(0 23 * * ? *)", MaxCapacity: pulumi.IntRef(1)},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"service.autoScaling.targetTracking[1].name":   `"ALBRequestCountPerTarget" is used by another scaling policy`,
+		"service.autoScaling.stepScaling[0].name":      `"ECSServiceAverageCPUUtilization" is used by another scaling policy`,
+		"service.autoScaling.stepScaling[2].name":      `"scale-out" is used by another scaling policy`,
+		"service.autoScaling.scheduledActions[1].name": `"nightly" is used by another scheduled action`,
+	}, fieldErrors(t, serviceConfig.Validate()))
+}
@@ -0,0 +1,351 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/codedeploy"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// codeDeployAssumeRolePolicy allows CodeDeploy to assume a role.
+const codeDeployAssumeRolePolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Effect": "Allow",
+		"Principal": {"Service": "codedeploy.amazonaws.com"},
+		"Action": "sts:AssumeRole"
+	}]
+}`
+
+// codeDeployRolePolicy is the path of the AWS managed policy that allows CodeDeploy to deploy ECS services.
+const codeDeployRolePolicy = "AWSCodeDeployRoleForECS"
+
+// ServiceBlueGreenConfig defines a blue/green deployment of an AWS ECS service by CodeDeploy. The service is
+// registered with a blue and a green target group behind the production listener and, if set, the test listener
+// of an existing load balancer. Unless set, the target groups forward HTTP traffic to IP targets, the listener rules
+// match every path, traffic shifts all at once and the old tasks are terminated 5 minutes after a deployment.
+type ServiceBlueGreenConfig struct {
+	ContainerName                string                 `json:"containerName"`
+	ContainerPort                int                    `json:"containerPort"`
+	DeregistrationDelay          *int                   `json:"deregistrationDelay,omitempty"`
+	HealthCheckPath              *string                `json:"healthCheckPath,omitempty"`
+	HostHeaders                  []string               `json:"hostHeaders,omitempty"`
+	PathPatterns                 []string               `json:"pathPatterns,omitempty"`
+	Priority                     *int                   `json:"priority,omitempty"`
+	ProductionListenerArn        string                 `json:"productionListenerArn"`
+	Protocol                     *string                `json:"protocol,omitempty"`
+	TargetType                   *string                `json:"targetType,omitempty"`
+	TerminationWaitTimeInMinutes *int                   `json:"terminationWaitTimeInMinutes,omitempty"`
+	TestListenerArn              *string                `json:"testListenerArn,omitempty"`
+	TrafficShifting              *TrafficShiftingConfig `json:"trafficShifting,omitempty"`
+	VpcID                        string                 `json:"vpcId"`
+}
+
+// TrafficShiftingConfig defines how CodeDeploy shifts traffic to the green target group. Type is AllAtOnce, Canary
+// or Linear. A Canary shift moves Percentage of the traffic first and the rest IntervalMinutes later; a Linear
+// shift moves Percentage of the traffic every IntervalMinutes.
+type TrafficShiftingConfig struct {
+	IntervalMinutes *int   `json:"intervalMinutes,omitempty"`
+	Percentage      *int   `json:"percentage,omitempty"`
+	Type            string `json:"type"`
+}
+
+// ServiceBlueGreenArgs defines inputs for a blue/green deployment of an AWS ECS service by CodeDeploy. The path
+// patterns, host headers and traffic shifting are plain values, because they determine the listener rules and the
+// deployment configuration.
+type ServiceBlueGreenArgs struct {
+	ContainerName                pulumi.StringInput
+	ContainerPort                pulumi.IntInput
+	DeregistrationDelay          pulumi.IntPtrInput
+	HealthCheckPath              pulumi.StringPtrInput
+	HostHeaders                  []string
+	PathPatterns                 []string
+	Priority                     pulumi.IntPtrInput
+	ProductionListenerArn        pulumi.StringInput
+	Protocol                     pulumi.StringPtrInput
+	TargetType                   pulumi.StringPtrInput
+	TerminationWaitTimeInMinutes pulumi.IntPtrInput
+	TestListenerArn              pulumi.StringPtrInput
+	TrafficShifting              *TrafficShiftingConfig
+	VpcID                        pulumi.StringInput
+}
+
+// blueGreenTargets holds the target groups of a blue/green deployment and the listener rules that route to them.
+type blueGreenTargets struct {
+	blue          *lb.TargetGroup
+	green         *lb.TargetGroup
+	listenerRules []pulumi.Resource
+}
+
+// toArgs converts the blue/green deployment configuration into inputs.
+func (c ServiceBlueGreenConfig) toArgs() *ServiceBlueGreenArgs {
+	return &ServiceBlueGreenArgs{
+		ContainerName:                pulumi.String(c.ContainerName),
+		ContainerPort:                pulumi.Int(c.ContainerPort),
+		DeregistrationDelay:          pulumi.IntPtrFromPtr(c.DeregistrationDelay),
+		HealthCheckPath:              pulumi.StringPtrFromPtr(c.HealthCheckPath),
+		HostHeaders:                  c.HostHeaders,
+		PathPatterns:                 c.PathPatterns,
+		Priority:                     pulumi.IntPtrFromPtr(c.Priority),
+		ProductionListenerArn:        pulumi.String(c.ProductionListenerArn),
+		Protocol:                     pulumi.StringPtrFromPtr(c.Protocol),
+		TargetType:                   pulumi.StringPtrFromPtr(c.TargetType),
+		TerminationWaitTimeInMinutes: pulumi.IntPtrFromPtr(c.TerminationWaitTimeInMinutes),
+		TestListenerArn:              pulumi.StringPtrFromPtr(c.TestListenerArn),
+		TrafficShifting:              c.TrafficShifting,
+		VpcID:                        pulumi.String(c.VpcID),
+	}
+}
+
+func (c ServiceBlueGreenConfig) validate(v *validator, path string) {
+	v.required(path+".containerName", c.ContainerName)
+	v.between(path+".containerPort", &c.ContainerPort, 1, 65535)
+	v.required(path+".productionListenerArn", c.ProductionListenerArn)
+	v.required(path+".vpcId", c.VpcID)
+	v.oneOf(path+".protocol", c.Protocol, "HTTP", "HTTPS")
+	v.oneOf(path+".targetType", c.TargetType, "ip", "instance")
+	v.between(path+".priority", c.Priority, 1, 50000)
+	v.between(path+".deregistrationDelay", c.DeregistrationDelay, 0, 3600)
+	v.between(path+".terminationWaitTimeInMinutes", c.TerminationWaitTimeInMinutes, 0, 2880)
+	if c.TestListenerArn != nil && *c.TestListenerArn == c.ProductionListenerArn {
+		v.errorf(path+".testListenerArn", "must differ from productionListenerArn")
+	}
+
+	shifting := c.TrafficShifting
+	if shifting == nil {
+		return
+	}
+	shiftingPath := path + ".trafficShifting"
+	v.oneOf(shiftingPath+".type", &shifting.Type, "AllAtOnce", "Canary", "Linear")
+	if shifting.Type == "AllAtOnce" {
+		if shifting.Percentage != nil || shifting.IntervalMinutes != nil {
+			v.errorf(shiftingPath, "percentage and intervalMinutes are not supported by AllAtOnce")
+		}
+		return
+	}
+	if shifting.Percentage == nil {
+		v.errorf(shiftingPath+".percentage", "is required for %s", shifting.Type)
+	}
+	if shifting.IntervalMinutes == nil {
+		v.errorf(shiftingPath+".intervalMinutes", "is required for %s", shifting.Type)
+	}
+	v.between(shiftingPath+".percentage", shifting.Percentage, 1, 99)
+	v.between(shiftingPath+".intervalMinutes", shifting.IntervalMinutes, 1, 2880)
+}
+
+// newBlueGreenTargets creates the blue and green target groups of a service and the listener rules that route the
+// production and test traffic to the blue one. CodeDeploy swaps the target groups of the rules on every deployment,
+// so changes to their actions are ignored.
+func newBlueGreenTargets(ctx *pulumi.Context, name string, args *ServiceBlueGreenArgs, tags pulumi.StringMapInput, parent pulumi.Resource) (*blueGreenTargets, error) {
+	protocol := args.Protocol
+	if protocol == nil {
+		protocol = pulumi.String("HTTP")
+	}
+	targetType := args.TargetType
+	if targetType == nil {
+		targetType = pulumi.String("ip")
+	}
+
+	targets := &blueGreenTargets{}
+	for _, color := range []string{"blue", "green"} {
+		targetGroup, err := lb.NewTargetGroup(ctx, name+"-"+color, &lb.TargetGroupArgs{
+			DeregistrationDelay: args.DeregistrationDelay,
+			HealthCheck: &lb.TargetGroupHealthCheckArgs{
+				Path: args.HealthCheckPath,
+			},
+			Port:       args.ContainerPort,
+			Protocol:   protocol,
+			Tags:       tags,
+			TargetType: targetType,
+			VpcId:      args.VpcID,
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new target group: %v", err)
+		}
+
+		if color == "blue" {
+			targets.blue = targetGroup
+		} else {
+			targets.green = targetGroup
+		}
+	}
+
+	conditions := listenerRuleConditions(args.PathPatterns, args.HostHeaders)
+	listeners := map[string]pulumi.StringInput{"production": args.ProductionListenerArn}
+	if args.TestListenerArn != nil {
+		listeners["test"] = args.TestListenerArn.ToStringPtrOutput().Elem()
+	}
+	for _, route := range sortedKeys(listeners) {
+		rule, err := lb.NewListenerRule(ctx, name+"-"+route, &lb.ListenerRuleArgs{
+			Actions: lb.ListenerRuleActionArray{
+				&lb.ListenerRuleActionArgs{
+					TargetGroupArn: targets.blue.Arn,
+					Type:           pulumi.String("forward"),
+				},
+			},
+			Conditions:  conditions,
+			ListenerArn: listeners[route],
+			Priority:    args.Priority,
+			Tags:        tags,
+		}, pulumi.Parent(parent), pulumi.IgnoreChanges([]string{"actions"}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new listener rule: %v", err)
+		}
+		targets.listenerRules = append(targets.listenerRules, rule)
+	}
+
+	return targets, nil
+}
+
+// listenerRuleConditions returns the conditions of a listener rule that matches the given path patterns and host
+// headers, or every path if there are neither.
+func listenerRuleConditions(pathPatterns, hostHeaders []string) lb.ListenerRuleConditionArray {
+	if len(pathPatterns) == 0 && len(hostHeaders) == 0 {
+		pathPatterns = []string{"/*"}
+	}
+
+	var conditions lb.ListenerRuleConditionArray
+	if len(pathPatterns) > 0 {
+		conditions = append(conditions, &lb.ListenerRuleConditionArgs{
+			PathPattern: &lb.ListenerRuleConditionPathPatternArgs{Values: pulumi.ToStringArray(pathPatterns)},
+		})
+	}
+	if len(hostHeaders) > 0 {
+		conditions = append(conditions, &lb.ListenerRuleConditionArgs{
+			HostHeader: &lb.ListenerRuleConditionHostHeaderArgs{Values: pulumi.ToStringArray(hostHeaders)},
+		})
+	}
+	return conditions
+}
+
+// loadBalancer returns the load balancer configuration that registers the tasks of the service with the blue
+// target group.
+func (t *blueGreenTargets) loadBalancer(args *ServiceBlueGreenArgs) *ecs.ServiceLoadBalancerArgs {
+	return &ecs.ServiceLoadBalancerArgs{
+		ContainerName:  args.ContainerName,
+		ContainerPort:  args.ContainerPort,
+		TargetGroupArn: t.blue.Arn,
+	}
+}
+
+// newBlueGreenDeployment creates the CodeDeploy application, deployment configuration, deployment group and service
+// role that deploy the service with the given cluster and name.
+func newBlueGreenDeployment(ctx *pulumi.Context, name string, args *ServiceBlueGreenArgs, targets *blueGreenTargets, clusterArn, serviceName pulumi.StringOutput, tags pulumi.StringMapInput, parent pulumi.Resource) error {
+	role, err := iam.NewRole(ctx, name+"-codedeploy", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(codeDeployAssumeRolePolicy),
+		Tags:             tags,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return fmt.Errorf("failed to create new codedeploy role: %v", err)
+	}
+
+	policyArn, err := managedPolicyArn(ctx, codeDeployRolePolicy, parent)
+	if err != nil {
+		return err
+	}
+	attachment, err := iam.NewRolePolicyAttachment(ctx, name+"-codedeploy", &iam.RolePolicyAttachmentArgs{
+		PolicyArn: policyArn,
+		Role:      role.Name,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return fmt.Errorf("failed to attach codedeploy role policy: %v", err)
+	}
+
+	application, err := codedeploy.NewApplication(ctx, name, &codedeploy.ApplicationArgs{
+		ComputePlatform: pulumi.String("ECS"),
+		Name:            serviceName,
+		Tags:            tags,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return fmt.Errorf("failed to create new codedeploy application: %v", err)
+	}
+
+	deploymentConfigName := pulumi.StringInput(pulumi.String("CodeDeployDefault.ECSAllAtOnce"))
+	if shifting := args.TrafficShifting; shifting != nil && shifting.Type != "AllAtOnce" {
+		routing := &codedeploy.DeploymentConfigTrafficRoutingConfigArgs{
+			Type: pulumi.String("TimeBased" + shifting.Type),
+		}
+		if shifting.Type == "Canary" {
+			routing.TimeBasedCanary = &codedeploy.DeploymentConfigTrafficRoutingConfigTimeBasedCanaryArgs{
+				Interval:   pulumi.IntPtrFromPtr(shifting.IntervalMinutes),
+				Percentage: pulumi.IntPtrFromPtr(shifting.Percentage),
+			}
+		} else {
+			routing.TimeBasedLinear = &codedeploy.DeploymentConfigTrafficRoutingConfigTimeBasedLinearArgs{
+				Interval:   pulumi.IntPtrFromPtr(shifting.IntervalMinutes),
+				Percentage: pulumi.IntPtrFromPtr(shifting.Percentage),
+			}
+		}
+
+		deploymentConfig, err := codedeploy.NewDeploymentConfig(ctx, name, &codedeploy.DeploymentConfigArgs{
+			ComputePlatform:      pulumi.String("ECS"),
+			DeploymentConfigName: pulumi.Sprintf("%s-%s", serviceName, strings.ToLower(shifting.Type)),
+			TrafficRoutingConfig: routing,
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return fmt.Errorf("failed to create new codedeploy deployment config: %v", err)
+		}
+		deploymentConfigName = deploymentConfig.DeploymentConfigName
+	}
+
+	terminationWaitTime := args.TerminationWaitTimeInMinutes
+	if terminationWaitTime == nil {
+		terminationWaitTime = pulumi.Int(5)
+	}
+
+	targetGroupPair := &codedeploy.DeploymentGroupLoadBalancerInfoTargetGroupPairInfoArgs{
+		ProdTrafficRoute: &codedeploy.DeploymentGroupLoadBalancerInfoTargetGroupPairInfoProdTrafficRouteArgs{
+			ListenerArns: pulumi.StringArray{args.ProductionListenerArn},
+		},
+		TargetGroups: codedeploy.DeploymentGroupLoadBalancerInfoTargetGroupPairInfoTargetGroupArray{
+			&codedeploy.DeploymentGroupLoadBalancerInfoTargetGroupPairInfoTargetGroupArgs{Name: targets.blue.Name},
+			&codedeploy.DeploymentGroupLoadBalancerInfoTargetGroupPairInfoTargetGroupArgs{Name: targets.green.Name},
+		},
+	}
+	if args.TestListenerArn != nil {
+		targetGroupPair.TestTrafficRoute = &codedeploy.DeploymentGroupLoadBalancerInfoTargetGroupPairInfoTestTrafficRouteArgs{
+			ListenerArns: pulumi.StringArray{args.TestListenerArn.ToStringPtrOutput().Elem()},
+		}
+	}
+
+	_, err = codedeploy.NewDeploymentGroup(ctx, name, &codedeploy.DeploymentGroupArgs{
+		AppName: application.Name,
+		AutoRollbackConfiguration: &codedeploy.DeploymentGroupAutoRollbackConfigurationArgs{
+			Enabled: pulumi.Bool(true),
+			Events:  pulumi.StringArray{pulumi.String("DEPLOYMENT_FAILURE")},
+		},
+		BlueGreenDeploymentConfig: &codedeploy.DeploymentGroupBlueGreenDeploymentConfigArgs{
+			DeploymentReadyOption: &codedeploy.DeploymentGroupBlueGreenDeploymentConfigDeploymentReadyOptionArgs{
+				ActionOnTimeout: pulumi.String("CONTINUE_DEPLOYMENT"),
+			},
+			TerminateBlueInstancesOnDeploymentSuccess: &codedeploy.DeploymentGroupBlueGreenDeploymentConfigTerminateBlueInstancesOnDeploymentSuccessArgs{
+				Action:                       pulumi.String("TERMINATE"),
+				TerminationWaitTimeInMinutes: terminationWaitTime,
+			},
+		},
+		DeploymentConfigName: deploymentConfigName,
+		DeploymentGroupName:  serviceName,
+		DeploymentStyle: &codedeploy.DeploymentGroupDeploymentStyleArgs{
+			DeploymentOption: pulumi.String("WITH_TRAFFIC_CONTROL"),
+			DeploymentType:   pulumi.String("BLUE_GREEN"),
+		},
+		EcsService: &codedeploy.DeploymentGroupEcsServiceArgs{
+			ClusterName: clusterNameOf(clusterArn),
+			ServiceName: serviceName,
+		},
+		LoadBalancerInfo: &codedeploy.DeploymentGroupLoadBalancerInfoArgs{
+			TargetGroupPairInfo: targetGroupPair,
+		},
+		ServiceRoleArn: role.Arn,
+		Tags:           tags,
+	}, pulumi.Parent(parent), pulumi.DependsOn([]pulumi.Resource{attachment}))
+	if err != nil {
+		return fmt.Errorf("failed to create new codedeploy deployment group: %v", err)
+	}
+
+	return nil
+}
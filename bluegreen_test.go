@@ -0,0 +1,128 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	productionListenerArn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/my-alb/0123456789abcdef/0123456789abcdef"
+	testListenerArn       = "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/my-alb/0123456789abcdef/fedcba9876543210"
+)
+
+// blueGreenServiceFixture returns the service fixture, deployed blue/green by CodeDeploy.
+func blueGreenServiceFixture(t *testing.T) (*ServiceConfig, createService) {
+	serviceConfig, create := serviceFixture(t)
+	serviceConfig.DeploymentController = nil
+	serviceConfig.DeploymentCircuitBreaker = nil
+	serviceConfig.BlueGreen = &ServiceBlueGreenConfig{
+		ContainerName:         "my-container",
+		ContainerPort:         80,
+		HealthCheckPath:       pulumi.StringRef("/health"),
+		ProductionListenerArn: productionListenerArn,
+		TestListenerArn:       pulumi.StringRef(testListenerArn),
+		TrafficShifting:       &TrafficShiftingConfig{Type: "Canary", Percentage: pulumi.IntRef(10), IntervalMinutes: pulumi.IntRef(5)},
+		VpcID:                 "vpc-0123456789abcdef0",
+	}
+	return serviceConfig, create
+}
+
+// TestNewServiceBlueGreen is a unit test that checks the CodeDeploy resources created for a blue/green service and how they are wired to it.
+func TestNewServiceBlueGreen(t *testing.T) {
+	_, create := blueGreenServiceFixture(t)
+
+	m := create(nil)
+
+	blue := m.resource(t, "aws:lb/targetGroup:TargetGroup", "my-ecs-service-blue")
+	assert.Equal(t, componentURN(ServiceType, "my-ecs-service"), blue.Parent)
+	assert.Equal(t, resource.NewStringProperty("ip"), blue.Inputs["targetType"])
+	assert.Equal(t, 2, m.count("aws:lb/targetGroup:TargetGroup"))
+
+	for route, listenerArn := range map[string]string{"production": productionListenerArn, "test": testListenerArn} {
+		rule := m.resource(t, "aws:lb/listenerRule:ListenerRule", "my-ecs-service-"+route)
+		assert.Equal(t, resource.NewStringProperty(listenerArn), rule.Inputs["listenerArn"])
+		assert.Equal(t, []string{"actions"}, rule.IgnoreChanges)
+	}
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"type": "CODE_DEPLOY"}), service.Inputs["deploymentController"].ObjectValue())
+	assert.Equal(t, resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"containerName":  "my-container",
+		"containerPort":  80,
+		"targetGroupArn": "arn:aws:mock:us-west-2:123456789012:my-ecs-service-blue",
+	}))}), service.Inputs["loadBalancers"])
+	assert.Equal(t, []string{"taskDefinition", "loadBalancers"}, service.IgnoreChanges)
+
+	deploymentConfig := m.resource(t, "aws:codedeploy/deploymentConfig:DeploymentConfig", "my-ecs-service")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"timeBasedCanary": map[string]interface{}{"interval": 5, "percentage": 10},
+		"type":            "TimeBasedCanary",
+	}), deploymentConfig.Inputs["trafficRoutingConfig"].ObjectValue())
+
+	deploymentGroup := m.resource(t, "aws:codedeploy/deploymentGroup:DeploymentGroup", "my-ecs-service")
+	assert.Equal(t, resource.NewStringProperty("my-ecs-service-canary"), deploymentGroup.Inputs["deploymentConfigName"])
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"clusterName": "my-cluster",
+		"serviceName": "my-ecs-service",
+	}), deploymentGroup.Inputs["ecsService"].ObjectValue())
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"targetGroupPairInfo": map[string]interface{}{
+			"prodTrafficRoute": map[string]interface{}{"listenerArns": []interface{}{productionListenerArn}},
+			"targetGroups": []interface{}{
+				map[string]interface{}{"name": "my-ecs-service-blue"},
+				map[string]interface{}{"name": "my-ecs-service-green"},
+			},
+			"testTrafficRoute": map[string]interface{}{"listenerArns": []interface{}{testListenerArn}},
+		},
+	}), deploymentGroup.Inputs["loadBalancerInfo"].ObjectValue())
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-ecs-service-codedeploy"), deploymentGroup.Inputs["serviceRoleArn"])
+
+	attachment := m.resource(t, "aws:iam/rolePolicyAttachment:RolePolicyAttachment", "my-ecs-service-codedeploy")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:iam::aws:policy/AWSCodeDeployRoleForECS"), attachment.Inputs["policyArn"])
+}
+
+// TestNewServiceFromArgsBlueGreen is a unit test that checks that blue/green services created from inputs take their listeners as outputs, default to the CODE_DEPLOY deployment controller and reject any other.
+func TestNewServiceFromArgsBlueGreen(t *testing.T) {
+	serviceConfig, create := blueGreenServiceFixture(t)
+
+	var args *ServiceArgs
+	m := create(func(ctx *pulumi.Context, serviceArgs *ServiceArgs) error {
+		args = serviceArgs
+		assert.Nil(t, args.DeploymentController)
+		args.BlueGreen.TestListenerArn = pulumi.String(testListenerArn).ToStringOutput().ToStringPtrOutput()
+		return nil
+	})
+	rule := m.resource(t, "aws:lb/listenerRule:ListenerRule", "my-ecs-service-test")
+	assert.Equal(t, resource.NewStringProperty(testListenerArn), rule.Inputs["listenerArn"])
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"type": "CODE_DEPLOY"}), service.Inputs["deploymentController"].ObjectValue())
+	assert.Subset(t, service.IgnoreChanges, []string{"taskDefinition", "loadBalancers"})
+	assert.Nil(t, args.DeploymentController)
+
+	args.DeploymentController = &ecs.ServiceDeploymentControllerArgs{Type: pulumi.String("ECS")}
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		_, err := NewServiceFromArgs(ctx, serviceConfig.Name, args)
+		return err
+	}, pulumi.WithMocks("project", "stack", newMocks()))
+	assert.ErrorContains(t, err, `service my-ecs-service requires the CODE_DEPLOY deployment controller for blue/green deployments, got "ECS"`)
+}
+
+// TestValidateServiceBlueGreen is a unit test that checks the checks of the blueGreen block of a service.
+func TestValidateServiceBlueGreen(t *testing.T) {
+	serviceConfig, _ := blueGreenServiceFixture(t)
+	serviceConfig.DeploymentController = &struct {
+		Type *string `json:"type,omitempty"`
+	}{Type: pulumi.StringRef("ECS")}
+	serviceConfig.BlueGreen.TestListenerArn = pulumi.StringRef(productionListenerArn)
+	serviceConfig.BlueGreen.TrafficShifting = &TrafficShiftingConfig{Type: "Linear", Percentage: pulumi.IntRef(10)}
+
+	assert.Equal(t, map[string]string{
+		"service.blueGreen.testListenerArn":                 "must differ from productionListenerArn",
+		"service.blueGreen.trafficShifting.intervalMinutes": "is required for Linear",
+		"service.deploymentController.type":                 "must be CODE_DEPLOY for blue/green deployments",
+	}, fieldErrors(t, serviceConfig.Validate()))
+}
@@ -0,0 +1,295 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ContainerDefinition defines a container in an AWS ECS task definition.
+// Fields that are not modelled are kept in Extra, so a container definition survives a JSON round trip unchanged.
+type ContainerDefinition struct {
+	Command                []string                        `json:"command,omitempty"`
+	CPU                    *int                            `json:"cpu,omitempty"`
+	CredentialSpecs        []string                        `json:"credentialSpecs,omitempty"`
+	DependsOn              []ContainerDependency           `json:"dependsOn,omitempty"`
+	DisableNetworking      *bool                           `json:"disableNetworking,omitempty"`
+	DNSSearchDomains       []string                        `json:"dnsSearchDomains,omitempty"`
+	DNSServers             []string                        `json:"dnsServers,omitempty"`
+	DockerLabels           map[string]string               `json:"dockerLabels,omitempty"`
+	DockerSecurityOptions  []string                        `json:"dockerSecurityOptions,omitempty"`
+	EntryPoint             []string                        `json:"entryPoint,omitempty"`
+	Environment            []ContainerKeyValuePair         `json:"environment,omitempty"`
+	EnvironmentFiles       []ContainerEnvironmentFile      `json:"environmentFiles,omitempty"`
+	Essential              *bool                           `json:"essential,omitempty"`
+	ExtraHosts             []ContainerHostEntry            `json:"extraHosts,omitempty"`
+	FirelensConfiguration  *ContainerFirelensConfiguration `json:"firelensConfiguration,omitempty"`
+	HealthCheck            *ContainerHealthCheck           `json:"healthCheck,omitempty"`
+	Hostname               *string                         `json:"hostname,omitempty"`
+	Image                  string                          `json:"image,omitempty"`
+	Interactive            *bool                           `json:"interactive,omitempty"`
+	Links                  []string                        `json:"links,omitempty"`
+	LinuxParameters        *ContainerLinuxParameters       `json:"linuxParameters,omitempty"`
+	LogConfiguration       *ContainerLogConfiguration      `json:"logConfiguration,omitempty"`
+	Memory                 *int                            `json:"memory,omitempty"`
+	MemoryReservation      *int                            `json:"memoryReservation,omitempty"`
+	MountPoints            []ContainerMountPoint           `json:"mountPoints,omitempty"`
+	Name                   string                          `json:"name,omitempty"`
+	PortMappings           []ContainerPortMapping          `json:"portMappings,omitempty"`
+	Privileged             *bool                           `json:"privileged,omitempty"`
+	PseudoTerminal         *bool                           `json:"pseudoTerminal,omitempty"`
+	ReadonlyRootFilesystem *bool                           `json:"readonlyRootFilesystem,omitempty"`
+	RepositoryCredentials  *ContainerRepositoryCredentials `json:"repositoryCredentials,omitempty"`
+	ResourceRequirements   []ContainerResourceRequirement  `json:"resourceRequirements,omitempty"`
+	RestartPolicy          *ContainerRestartPolicy         `json:"restartPolicy,omitempty"`
+	Secrets                []ContainerSecret               `json:"secrets,omitempty"`
+	StartTimeout           *int                            `json:"startTimeout,omitempty"`
+	StopTimeout            *int                            `json:"stopTimeout,omitempty"`
+	SystemControls         []ContainerSystemControl        `json:"systemControls,omitempty"`
+	Ulimits                []ContainerUlimit               `json:"ulimits,omitempty"`
+	User                   *string                         `json:"user,omitempty"`
+	VersionConsistency     *string                         `json:"versionConsistency,omitempty"`
+	VolumesFrom            []ContainerVolumeFrom           `json:"volumesFrom,omitempty"`
+	WorkingDirectory       *string                         `json:"workingDirectory,omitempty"`
+
+	// Extra holds container definition fields that are not modelled by this type. They are passed to AWS as is.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ContainerDependency defines a dependency of a container on another container in the same task definition.
+type ContainerDependency struct {
+	Condition     string `json:"condition"`
+	ContainerName string `json:"containerName"`
+}
+
+// ContainerEnvironmentFile defines a file containing environment variables for a container.
+type ContainerEnvironmentFile struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ContainerFirelensConfiguration defines the FireLens configuration of a log router container.
+type ContainerFirelensConfiguration struct {
+	Options map[string]string `json:"options,omitempty"`
+	Type    string            `json:"type"`
+}
+
+// ContainerHealthCheck defines the health check of a container.
+type ContainerHealthCheck struct {
+	Command     []string `json:"command"`
+	Interval    *int     `json:"interval,omitempty"`
+	Retries     *int     `json:"retries,omitempty"`
+	StartPeriod *int     `json:"startPeriod,omitempty"`
+	Timeout     *int     `json:"timeout,omitempty"`
+}
+
+// ContainerHostEntry defines an entry in the /etc/hosts file of a container.
+type ContainerHostEntry struct {
+	Hostname  string `json:"hostname"`
+	IPAddress string `json:"ipAddress"`
+}
+
+// ContainerKeyValuePair defines an environment variable of a container.
+type ContainerKeyValuePair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ContainerLinuxParameters defines Linux-specific options of a container.
+type ContainerLinuxParameters struct {
+	Capabilities *struct {
+		Add  []string `json:"add,omitempty"`
+		Drop []string `json:"drop,omitempty"`
+	} `json:"capabilities,omitempty"`
+	Devices []struct {
+		ContainerPath *string  `json:"containerPath,omitempty"`
+		HostPath      string   `json:"hostPath"`
+		Permissions   []string `json:"permissions,omitempty"`
+	} `json:"devices,omitempty"`
+	InitProcessEnabled *bool `json:"initProcessEnabled,omitempty"`
+	MaxSwap            *int  `json:"maxSwap,omitempty"`
+	SharedMemorySize   *int  `json:"sharedMemorySize,omitempty"`
+	Swappiness         *int  `json:"swappiness,omitempty"`
+	Tmpfs              []struct {
+		ContainerPath string   `json:"containerPath"`
+		MountOptions  []string `json:"mountOptions,omitempty"`
+		Size          int      `json:"size"`
+	} `json:"tmpfs,omitempty"`
+}
+
+// ContainerLogConfiguration defines the log configuration of a container.
+type ContainerLogConfiguration struct {
+	LogDriver     string            `json:"logDriver"`
+	Options       map[string]string `json:"options,omitempty"`
+	SecretOptions []ContainerSecret `json:"secretOptions,omitempty"`
+}
+
+// ContainerMountPoint defines a volume mount of a container.
+type ContainerMountPoint struct {
+	ContainerPath *string `json:"containerPath,omitempty"`
+	ReadOnly      *bool   `json:"readOnly,omitempty"`
+	SourceVolume  *string `json:"sourceVolume,omitempty"`
+}
+
+// ContainerPortMapping defines a port mapping of a container.
+type ContainerPortMapping struct {
+	AppProtocol        *string `json:"appProtocol,omitempty"`
+	ContainerPort      *int    `json:"containerPort,omitempty"`
+	ContainerPortRange *string `json:"containerPortRange,omitempty"`
+	HostPort           *int    `json:"hostPort,omitempty"`
+	Name               *string `json:"name,omitempty"`
+	Protocol           *string `json:"protocol,omitempty"`
+}
+
+// ContainerRepositoryCredentials defines the credentials of a private image registry.
+type ContainerRepositoryCredentials struct {
+	CredentialsParameter string `json:"credentialsParameter"`
+}
+
+// ContainerResourceRequirement defines a GPU or inference accelerator requirement of a container.
+type ContainerResourceRequirement struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ContainerRestartPolicy defines the restart policy of a container.
+type ContainerRestartPolicy struct {
+	Enabled              bool  `json:"enabled"`
+	IgnoredExitCodes     []int `json:"ignoredExitCodes,omitempty"`
+	RestartAttemptPeriod *int  `json:"restartAttemptPeriod,omitempty"`
+}
+
+// ContainerSecret defines a secret exposed to a container, referenced by the ARN or name of a Secrets Manager secret
+// or Systems Manager parameter.
+type ContainerSecret struct {
+	Name      string `json:"name"`
+	ValueFrom string `json:"valueFrom"`
+}
+
+// ContainerSystemControl defines a namespaced kernel parameter of a container.
+type ContainerSystemControl struct {
+	Namespace *string `json:"namespace,omitempty"`
+	Value     *string `json:"value,omitempty"`
+}
+
+// ContainerUlimit defines a ulimit of a container.
+type ContainerUlimit struct {
+	HardLimit int    `json:"hardLimit"`
+	Name      string `json:"name"`
+	SoftLimit int    `json:"softLimit"`
+}
+
+// ContainerVolumeFrom defines a volume mounted from another container.
+type ContainerVolumeFrom struct {
+	ReadOnly        *bool   `json:"readOnly,omitempty"`
+	SourceContainer *string `json:"sourceContainer,omitempty"`
+}
+
+// containerDefinition has the fields of ContainerDefinition without its JSON methods.
+type containerDefinition ContainerDefinition
+
+// containerDefinitionFields lists the JSON names of the modelled container definition fields.
+var containerDefinitionFields = func() []string {
+	var fields []string
+	t := reflect.TypeOf(containerDefinition{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}()
+
+// MarshalJSON encodes the container definition, including the fields in Extra.
+func (c ContainerDefinition) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(containerDefinition(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range c.Extra {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes a container definition. Unknown fields are kept in Extra, unless they are so close to a
+// modelled field that they are most likely a typo, in which case an error is returned.
+func (c *ContainerDefinition) UnmarshalJSON(data []byte) error {
+	var definition containerDefinition
+	err := json.Unmarshal(data, &definition)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	definition.Extra = nil
+	for key, value := range fields {
+		suggestion, known := closestContainerDefinitionField(key)
+		if known {
+			continue
+		}
+		if suggestion != "" {
+			return fmt.Errorf("unknown field %q in container definition %q, did you mean %q?", key, definition.Name, suggestion)
+		}
+		if definition.Extra == nil {
+			definition.Extra = map[string]json.RawMessage{}
+		}
+		definition.Extra[key] = value
+	}
+
+	*c = ContainerDefinition(definition)
+	return nil
+}
+
+// closestContainerDefinitionField reports whether key is a modelled container definition field. If it is not, it
+// returns the modelled field that key is most likely a misspelling of, if any.
+func closestContainerDefinitionField(key string) (string, bool) {
+	var suggestion string
+	best := len(key)/4 + 1
+	for _, field := range containerDefinitionFields {
+		if strings.EqualFold(key, field) {
+			return field, true
+		}
+		if distance := levenshtein(strings.ToLower(key), strings.ToLower(field)); distance <= best && distance <= 2 {
+			suggestion, best = field, distance
+		}
+	}
+	return suggestion, false
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
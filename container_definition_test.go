@@ -0,0 +1,59 @@
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestContainerDefinitionRoundTrip is a unit test that checks that a container definition, including unmodelled fields, survives a JSON round trip.
+func TestContainerDefinitionRoundTrip(t *testing.T) {
+	input := `{
+		"name": "app",
+		"image": "nginx:latest",
+		"cpu": 256,
+		"memoryReservation": 128,
+		"essential": true,
+		"portMappings": [{"containerPort": 80, "hostPort": 80, "protocol": "tcp", "name": "http", "appProtocol": "http"}],
+		"environment": [{"name": "ENV", "value": "production"}],
+		"secrets": [{"name": "DB_PASSWORD", "valueFrom": "arn:aws:ssm:us-west-2:123456789012:parameter/db-password"}],
+		"healthCheck": {"command": ["CMD-SHELL", "curl -f http://localhost/ || exit 1"], "interval": 30, "retries": 3},
+		"logConfiguration": {"logDriver": "awslogs", "options": {"awslogs-group": "/ecs/app"}},
+		"dependsOn": [{"containerName": "log_router", "condition": "START"}],
+		"ulimits": [{"name": "nofile", "softLimit": 1024, "hardLimit": 4096}],
+		"linuxParameters": {"initProcessEnabled": true, "capabilities": {"add": ["SYS_PTRACE"]}},
+		"restartPolicy": {"enabled": true, "ignoredExitCodes": [0], "restartAttemptPeriod": 60},
+		"firelensConfiguration": {"type": "fluentbit", "options": {"enable-ecs-log-metadata": "true"}},
+		"someFutureField": {"nested": [1, 2.5, "three"]}
+	}`
+
+	var definition ContainerDefinition
+	err := json.Unmarshal([]byte(input), &definition)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "app", definition.Name)
+	assert.Equal(t, 128, *definition.MemoryReservation)
+	assert.Equal(t, 80, *definition.PortMappings[0].ContainerPort)
+	assert.Equal(t, "awslogs", definition.LogConfiguration.LogDriver)
+	assert.Contains(t, definition.Extra, "someFutureField")
+
+	output, err := json.Marshal(definition)
+	assert.NoError(t, err)
+	assert.JSONEq(t, input, string(output))
+}
+
+// TestContainerDefinitionTypo is a unit test that checks that a misspelled field is rejected with a suggestion.
+func TestContainerDefinitionTypo(t *testing.T) {
+	var definition ContainerDefinition
+	err := json.Unmarshal([]byte(`{"name": "app", "image": "nginx", "memoryReservaton": 128}`), &definition)
+	assert.EqualError(t, err,
+		`unknown field "memoryReservaton" in container definition "app", did you mean "memoryReservation"?`)
+}
+
+// TestContainerDefinitionWrongType is a unit test that checks that a field of the wrong type is rejected.
+func TestContainerDefinitionWrongType(t *testing.T) {
+	var definition ContainerDefinition
+	err := json.Unmarshal([]byte(`{"name": "app", "image": "nginx", "memory": "512"}`), &definition)
+	assert.Error(t, err)
+}
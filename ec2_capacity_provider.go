@@ -0,0 +1,335 @@
+package ecs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/autoscaling"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ec2"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Ec2CapacityProviderType is the type token of the Ec2CapacityProvider component resource.
+const Ec2CapacityProviderType = "ecscomponent:index:Ec2CapacityProvider"
+
+// defaultAmiParameter is the SSM parameter that holds the ID of the latest ECS-optimized Amazon Linux 2023 AMI.
+const defaultAmiParameter = "/aws/service/ecs/optimized-ami/amazon-linux-2023/recommended/image_id"
+
+// ec2AssumeRolePolicy allows EC2 instances to assume a role.
+const ec2AssumeRolePolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Effect": "Allow",
+		"Principal": {"Service": "ec2.amazonaws.com"},
+		"Action": "sts:AssumeRole"
+	}]
+}`
+
+// containerInstanceRolePolicy is the path of the AWS managed policy that allows an EC2 instance to register with ECS.
+const containerInstanceRolePolicy = "service-role/AmazonEC2ContainerServiceforEC2Role"
+
+// Ec2CapacityProviderConfig defines arguments for creating an AWS ECS capacity provider backed by EC2 instances.
+// The instances run the ECS-optimized AMI whose ID is stored in the SSM parameter amiParameter, which defaults to
+// the latest Amazon Linux 2023 image. Unless set, the Auto Scaling group starts with no instances and ECS scales it
+// to a target capacity of 100%.
+type Ec2CapacityProviderConfig struct {
+	AmiParameter    *string           `json:"amiParameter,omitempty"`
+	AttachToCluster *bool             `json:"attachToCluster,omitempty"`
+	ClusterName     string            `json:"clusterName"`
+	InstanceType    string            `json:"instanceType"`
+	KeyName         *string           `json:"keyName,omitempty"`
+	ManagedDraining *string           `json:"managedDraining,omitempty"`
+	MaxSize         int               `json:"maxSize"`
+	MinSize         *int              `json:"minSize,omitempty"`
+	Name            string            `json:"name"`
+	SecurityGroups  []string          `json:"securityGroups,omitempty"`
+	Subnets         []string          `json:"subnets"`
+	Tags            map[string]string `json:"tags,omitempty"`
+	TargetCapacity  *int              `json:"targetCapacity,omitempty"`
+}
+
+// Ec2CapacityProviderArgs defines inputs for creating an AWS ECS capacity provider backed by EC2 instances.
+type Ec2CapacityProviderArgs struct {
+	AmiParameter    string
+	AttachToCluster bool
+	ClusterName     pulumi.StringInput
+	InstanceType    pulumi.StringInput
+	KeyName         pulumi.StringPtrInput
+	ManagedDraining pulumi.StringPtrInput
+	MaxSize         pulumi.IntInput
+	MinSize         pulumi.IntInput
+	SecurityGroups  pulumi.StringArrayInput
+	Subnets         pulumi.StringArrayInput
+	Tags            pulumi.StringMapInput
+	TargetCapacity  pulumi.IntPtrInput
+}
+
+// Ec2CapacityProvider is a component resource that manages an AWS ECS capacity provider together with the
+// launch template, Auto Scaling group and instance profile of its EC2 instances.
+type Ec2CapacityProvider struct {
+	pulumi.ResourceState
+
+	// Arn is the ARN of the capacity provider.
+	Arn pulumi.StringOutput
+	// AutoScalingGroup is the Auto Scaling group that launches the instances.
+	AutoScalingGroup *autoscaling.Group
+	// CapacityProvider is the capacity provider.
+	CapacityProvider *CapacityProvider
+	// ClusterCapacityProvider attaches the capacity provider to the cluster, or is nil if it is not attached.
+	ClusterCapacityProvider *ClusterCapacityProvider
+	// InstanceProfile is the instance profile of the instances.
+	InstanceProfile *iam.InstanceProfile
+	// InstanceRole is the IAM role of the instances.
+	InstanceRole *iam.Role
+	// LaunchTemplate is the launch template of the instances.
+	LaunchTemplate *ec2.LaunchTemplate
+	// Name is the name of the capacity provider.
+	Name pulumi.StringOutput
+}
+
+// ToArgs converts the EC2 capacity provider configuration into inputs, filling in the defaults.
+func (c Ec2CapacityProviderConfig) ToArgs() *Ec2CapacityProviderArgs {
+	args := &Ec2CapacityProviderArgs{
+		AmiParameter:    defaultAmiParameter,
+		ClusterName:     pulumi.String(c.ClusterName),
+		InstanceType:    pulumi.String(c.InstanceType),
+		KeyName:         pulumi.StringPtrFromPtr(c.KeyName),
+		ManagedDraining: pulumi.StringPtrFromPtr(c.ManagedDraining),
+		MaxSize:         pulumi.Int(c.MaxSize),
+		MinSize:         pulumi.Int(0),
+		SecurityGroups:  pulumi.ToStringArray(c.SecurityGroups),
+		Subnets:         pulumi.ToStringArray(c.Subnets),
+		Tags:            pulumi.ToStringMap(c.Tags),
+		TargetCapacity:  pulumi.Int(100),
+	}
+
+	if c.AmiParameter != nil {
+		args.AmiParameter = *c.AmiParameter
+	}
+	if c.AttachToCluster != nil {
+		args.AttachToCluster = *c.AttachToCluster
+	}
+	if c.MinSize != nil {
+		args.MinSize = pulumi.Int(*c.MinSize)
+	}
+	if c.TargetCapacity != nil {
+		args.TargetCapacity = pulumi.Int(*c.TargetCapacity)
+	}
+
+	return args
+}
+
+// Validate checks the EC2 capacity provider configuration and returns a *ValidationError listing every problem found.
+func (c Ec2CapacityProviderConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "ec2CapacityProvider")
+	return v.err()
+}
+
+func (c Ec2CapacityProviderConfig) validate(v *validator, path string) {
+	validateCapacityProviderName(v, path+".name", c.Name)
+	v.required(path+".clusterName", c.ClusterName)
+	v.required(path+".instanceType", c.InstanceType)
+	if len(c.Subnets) == 0 {
+		v.errorf(path+".subnets", "must contain at least one subnet")
+	}
+
+	v.between(path+".maxSize", &c.MaxSize, 1, 10000)
+	v.between(path+".minSize", c.MinSize, 0, 10000)
+	if c.MinSize != nil && *c.MinSize > c.MaxSize {
+		v.errorf(path+".minSize", "(%d) must not be greater than maxSize (%d)", *c.MinSize, c.MaxSize)
+	}
+	v.between(path+".targetCapacity", c.TargetCapacity, 1, 100)
+	v.oneOf(path+".managedDraining", c.ManagedDraining, "ENABLED", "DISABLED")
+}
+
+// NewEc2CapacityProvider creates a new AWS ECS capacity provider backed by EC2 instances, together with the
+// resources that launch them.
+func NewEc2CapacityProvider(ctx *pulumi.Context, config Ec2CapacityProviderConfig, opts ...pulumi.ResourceOption) (*Ec2CapacityProvider, error) {
+	config, err := interpolate(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEc2CapacityProviderFromArgs(ctx, config.Name, config.ToArgs(), opts...)
+}
+
+// NewEc2CapacityProviderFromArgs creates a new AWS ECS capacity provider backed by EC2 instances from inputs.
+// The instances join the cluster through their user data and are protected from scale-in, so that ECS decides
+// which of them to terminate. If AttachToCluster is set, the capacity provider is attached to the cluster with
+// NewClusterCapacityProviderFromArgs and becomes its default; this replaces any capacity providers that were
+// attached to the cluster before.
+func NewEc2CapacityProviderFromArgs(ctx *pulumi.Context, name string, args *Ec2CapacityProviderArgs, opts ...pulumi.ResourceOption) (*Ec2CapacityProvider, error) {
+	if args == nil {
+		return nil, fmt.Errorf("ec2 capacity provider %s requires a cluster", name)
+	}
+
+	err := resolveReferences(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &Ec2CapacityProvider{}
+	err = ctx.RegisterComponentResource(Ec2CapacityProviderType, name, component, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource: %v", err)
+	}
+
+	component.InstanceRole, err = iam.NewRole(ctx, name+"-instance", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(ec2AssumeRolePolicy),
+		Tags:             args.Tags,
+	}, pulumi.Parent(component))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new instance role: %v", err)
+	}
+
+	policyArn, err := managedPolicyArn(ctx, containerInstanceRolePolicy, component)
+	if err != nil {
+		return nil, err
+	}
+	_, err = iam.NewRolePolicyAttachment(ctx, name+"-instance", &iam.RolePolicyAttachmentArgs{
+		PolicyArn: policyArn,
+		Role:      component.InstanceRole.Name,
+	}, pulumi.Parent(component))
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach instance role policy: %v", err)
+	}
+
+	component.InstanceProfile, err = iam.NewInstanceProfile(ctx, name, &iam.InstanceProfileArgs{
+		Role: component.InstanceRole.Name,
+		Tags: args.Tags,
+	}, pulumi.Parent(component))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new instance profile: %v", err)
+	}
+
+	userData := pulumi.Sprintf("#!/bin/bash\necho ECS_CLUSTER=%s >> /etc/ecs/ecs.config\n", args.ClusterName).
+		ApplyT(func(script string) string {
+			return base64.StdEncoding.EncodeToString([]byte(script))
+		}).(pulumi.StringOutput)
+
+	component.LaunchTemplate, err = ec2.NewLaunchTemplate(ctx, name, &ec2.LaunchTemplateArgs{
+		IamInstanceProfile: &ec2.LaunchTemplateIamInstanceProfileArgs{
+			Arn: component.InstanceProfile.Arn,
+		},
+		// EC2 resolves the AMI ID from the SSM parameter whenever an instance is launched.
+		ImageId:      pulumi.String("resolve:ssm:" + args.AmiParameter),
+		InstanceType: args.InstanceType,
+		KeyName:      args.KeyName,
+		MetadataOptions: &ec2.LaunchTemplateMetadataOptionsArgs{
+			HttpEndpoint: pulumi.String("enabled"),
+			HttpTokens:   pulumi.String("required"),
+		},
+		NamePrefix: pulumi.String(name + "-"),
+		TagSpecifications: ec2.LaunchTemplateTagSpecificationArray{
+			&ec2.LaunchTemplateTagSpecificationArgs{
+				ResourceType: pulumi.String("instance"),
+				Tags:         args.Tags,
+			},
+		},
+		Tags:                 args.Tags,
+		UpdateDefaultVersion: pulumi.Bool(true),
+		UserData:             userData,
+		VpcSecurityGroupIds:  args.SecurityGroups,
+	}, pulumi.Parent(component))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new launch template: %v", err)
+	}
+
+	// ECS requires the AmazonECSManaged tag on the Auto Scaling group and manages its desired capacity. The other
+	// tags are propagated to the instances the group launches.
+	component.AutoScalingGroup, err = autoscaling.NewGroup(ctx, name, &autoscaling.GroupArgs{
+		LaunchTemplate: &autoscaling.GroupLaunchTemplateArgs{
+			Id:      component.LaunchTemplate.ID(),
+			Version: pulumi.String("$Latest"),
+		},
+		MaxSize:            args.MaxSize,
+		MinSize:            args.MinSize,
+		NamePrefix:         pulumi.String(name + "-"),
+		ProtectFromScaleIn: pulumi.Bool(true),
+		Tags:               autoScalingGroupTags(args.Tags),
+		VpcZoneIdentifiers: args.Subnets,
+	}, pulumi.Parent(component), pulumi.IgnoreChanges([]string{"desiredCapacity"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new auto scaling group: %v", err)
+	}
+
+	capacityProviders, err := NewCapacityProvidersFromArgs(ctx, []*CapacityProviderArgs{{
+		AutoScalingGroupProvider: &ecs.CapacityProviderAutoScalingGroupProviderArgs{
+			AutoScalingGroupArn: component.AutoScalingGroup.Arn,
+			ManagedDraining:     args.ManagedDraining,
+			ManagedScaling: &ecs.CapacityProviderAutoScalingGroupProviderManagedScalingArgs{
+				Status:         pulumi.String("ENABLED"),
+				TargetCapacity: args.TargetCapacity,
+			},
+			ManagedTerminationProtection: pulumi.String("ENABLED"),
+		},
+		Name: name,
+		Tags: args.Tags,
+	}}, pulumi.Parent(component))
+	if err != nil {
+		return nil, err
+	}
+	component.CapacityProvider = capacityProviders[0]
+	component.Arn = component.CapacityProvider.Arn
+	component.Name = component.CapacityProvider.Name
+
+	if args.AttachToCluster {
+		component.ClusterCapacityProvider, err = NewClusterCapacityProviderFromArgs(ctx, name, &ClusterCapacityProviderArgs{
+			CapacityProviders: pulumi.StringArray{component.Name},
+			ClusterName:       args.ClusterName,
+			DefaultCapacityProviderStrategies: ecs.ClusterCapacityProvidersDefaultCapacityProviderStrategyArray{
+				&ecs.ClusterCapacityProvidersDefaultCapacityProviderStrategyArgs{
+					CapacityProvider: component.Name,
+					Weight:           pulumi.Int(1),
+				},
+			},
+		}, pulumi.Parent(component))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = ctx.RegisterResourceOutputs(component, pulumi.Map{
+		"arn":                 component.Arn,
+		"autoScalingGroupArn": component.AutoScalingGroup.Arn,
+		"instanceProfileArn":  component.InstanceProfile.Arn,
+		"instanceRoleArn":     component.InstanceRole.Arn,
+		"launchTemplateId":    component.LaunchTemplate.ID(),
+		"name":                component.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+	}
+
+	return component, nil
+}
+
+// autoScalingGroupTags converts tags into Auto Scaling group tags that are propagated at launch, and adds the
+// AmazonECSManaged tag.
+func autoScalingGroupTags(tags pulumi.StringMapInput) autoscaling.GroupTagArrayOutput {
+	if tags == nil {
+		tags = pulumi.StringMap{}
+	}
+
+	return tags.ToStringMapOutput().ApplyT(func(tags map[string]string) []autoscaling.GroupTag {
+		groupTags := []autoscaling.GroupTag{{Key: "AmazonECSManaged", PropagateAtLaunch: true, Value: "true"}}
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			groupTags = append(groupTags, autoscaling.GroupTag{Key: key, PropagateAtLaunch: true, Value: tags[key]})
+		}
+		return groupTags
+	}).(autoscaling.GroupTagArrayOutput)
+}
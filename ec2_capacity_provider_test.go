@@ -0,0 +1,98 @@
+package ecs
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func getEc2CapacityProviderConfig() Ec2CapacityProviderConfig {
+	return Ec2CapacityProviderConfig{
+		AttachToCluster: pulumi.BoolRef(true),
+		ClusterName:     "my-cluster",
+		InstanceType:    "t3.small",
+		MaxSize:         4,
+		Name:            "my-ec2-capacity-provider",
+		Subnets:         []string{"subnet-018d8f50ed69b2301"},
+		Tags:            map[string]string{"Team": "platform"},
+	}
+}
+
+// TestNewEc2CapacityProvider is a unit test that checks the launch template, Auto Scaling group and capacity provider created for EC2 capacity.
+func TestNewEc2CapacityProvider(t *testing.T) {
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewEc2CapacityProvider(ctx, getEc2CapacityProviderConfig())
+		return err
+	})
+
+	launchTemplate := m.resource(t, "aws:ec2/launchTemplate:LaunchTemplate", "my-ec2-capacity-provider")
+	assert.Equal(t, componentURN(Ec2CapacityProviderType, "my-ec2-capacity-provider"), launchTemplate.Parent)
+	assert.Equal(t, resource.NewStringProperty("resolve:ssm:/aws/service/ecs/optimized-ami/amazon-linux-2023/recommended/image_id"), launchTemplate.Inputs["imageId"])
+	userData, err := base64.StdEncoding.DecodeString(launchTemplate.Inputs["userData"].StringValue())
+	assert.NoError(t, err)
+	assert.Contains(t, string(userData), "echo ECS_CLUSTER=my-cluster >> /etc/ecs/ecs.config")
+
+	attachment := m.resource(t, "aws:iam/rolePolicyAttachment:RolePolicyAttachment", "my-ec2-capacity-provider-instance")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:iam::aws:policy/service-role/AmazonEC2ContainerServiceforEC2Role"), attachment.Inputs["policyArn"])
+
+	group := m.resource(t, "aws:autoscaling/group:Group", "my-ec2-capacity-provider")
+	assert.Equal(t, resource.NewBoolProperty(true), group.Inputs["protectFromScaleIn"])
+	assert.Equal(t, resource.NewNumberProperty(0), group.Inputs["minSize"])
+	assert.Equal(t, resource.NewNumberProperty(4), group.Inputs["maxSize"])
+	assert.Equal(t, resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{"key": "AmazonECSManaged", "propagateAtLaunch": true, "value": "true"})),
+		resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{"key": "Team", "propagateAtLaunch": true, "value": "platform"})),
+	}), group.Inputs["tags"])
+
+	capacityProvider := m.resource(t, "aws:ecs/capacityProvider:CapacityProvider", "my-ec2-capacity-provider")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"autoScalingGroupArn": "arn:aws:mock:us-west-2:123456789012:my-ec2-capacity-provider",
+		"managedScaling": map[string]interface{}{
+			"status":         "ENABLED",
+			"targetCapacity": 100,
+		},
+		"managedTerminationProtection": "ENABLED",
+	}), capacityProvider.Inputs["autoScalingGroupProvider"].ObjectValue())
+
+	assert.Empty(t, m.resource(t, CapacityProviderType, "my-ec2-capacity-provider").Aliases)
+	assert.Empty(t, capacityProvider.Aliases)
+
+	clusterCapacityProviders := m.resource(t, "aws:ecs/clusterCapacityProviders:ClusterCapacityProviders", "my-ec2-capacity-provider")
+	assert.Empty(t, m.resource(t, ClusterCapacityProviderType, "my-ec2-capacity-provider").Aliases)
+	assert.Empty(t, clusterCapacityProviders.Aliases)
+	assert.Equal(t, resource.NewStringProperty("my-cluster"), clusterCapacityProviders.Inputs["clusterName"])
+	assert.Equal(t, resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("my-ec2-capacity-provider")}), clusterCapacityProviders.Inputs["capacityProviders"])
+}
+
+// TestNewEc2CapacityProviderDetached is a unit test that checks that the capacity provider is not attached to the cluster unless asked to.
+func TestNewEc2CapacityProviderDetached(t *testing.T) {
+	config := getEc2CapacityProviderConfig()
+	config.AttachToCluster = nil
+
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewEc2CapacityProvider(ctx, config)
+		return err
+	})
+
+	assert.Equal(t, 1, m.count("aws:ecs/capacityProvider:CapacityProvider"))
+	assert.Equal(t, 0, m.count(ClusterCapacityProviderType))
+}
+
+// TestEc2CapacityProviderConfigValidate is a unit test that checks that an invalid EC2 capacity provider configuration is rejected with every problem listed.
+func TestEc2CapacityProviderConfigValidate(t *testing.T) {
+	config := getEc2CapacityProviderConfig()
+	config.MinSize = pulumi.IntRef(5)
+	config.Name = "ecs-capacity"
+	config.TargetCapacity = pulumi.IntRef(0)
+
+	assert.Equal(t, map[string]string{
+		"ec2CapacityProvider.minSize":        "(5) must not be greater than maxSize (4)",
+		"ec2CapacityProvider.name":           `must not start with "ecs"`,
+		"ec2CapacityProvider.targetCapacity": "must be between 1 and 100, got 0",
+	}, fieldErrors(t, config.Validate()))
+}
@@ -18,11 +18,39 @@ package ecs
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/cloudwatch"
 	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
+// Type tokens of the component resources provided by this package.
+const (
+	AccountSettingDefaultType   = "ecscomponent:index:AccountSettingDefault"
+	CapacityProviderType        = "ecscomponent:index:CapacityProvider"
+	ClusterType                 = "ecscomponent:index:Cluster"
+	ClusterCapacityProviderType = "ecscomponent:index:ClusterCapacityProvider"
+	ServiceType                 = "ecscomponent:index:Service"
+	TaskDefinitionType          = "ecscomponent:index:TaskDefinition"
+	TaskSetType                 = "ecscomponent:index:TaskSet"
+)
+
+// legacyTypes maps the type tokens of the component resources to the tokens they were registered with in the aws
+// namespace, before this package used its own namespace.
+var legacyTypes = map[string]string{
+	AccountSettingDefaultType:   "aws:ecs:AccountSettingsDefault",
+	CapacityProviderType:        "aws:ecs:capacityProvider",
+	ClusterType:                 "aws:ecs:Cluster",
+	ClusterCapacityProviderType: "aws:ecs:clusterCapacityProvider",
+	ServiceType:                 "aws:ecs:Service",
+	TaskDefinitionType:          "aws:ecs:TaskDefinition",
+	TaskSetType:                 "aws:ecs:TaskSet",
+}
+
 // AccountSettingDefaultConfig defines arguments for AWS ECS account settings.
 type AccountSettingDefaultConfig struct {
 	Name  string `json:"name"`
@@ -47,6 +75,26 @@ type CapacityProviderConfig struct {
 	Tags map[string]string `json:"tags,omitempty"`
 }
 
+// AccountSettingDefault is a component resource that manages an AWS ECS default account setting.
+type AccountSettingDefault struct {
+	pulumi.ResourceState
+
+	// Name is the name of the account setting.
+	Name pulumi.StringOutput
+	// Value is the value of the account setting.
+	Value pulumi.StringOutput
+}
+
+// CapacityProvider is a component resource that manages an AWS ECS capacity provider.
+type CapacityProvider struct {
+	pulumi.ResourceState
+
+	// Arn is the ARN of the capacity provider.
+	Arn pulumi.StringOutput
+	// Name is the name of the capacity provider.
+	Name pulumi.StringOutput
+}
+
 // ClusterConfig defines arguments for creating an AWS ECS cluster.
 type ClusterConfig struct {
 	Configuration *struct {
@@ -73,10 +121,16 @@ type ClusterConfig struct {
 	Tags map[string]string `json:"tags,omitempty"`
 }
 
-// clusterOutput defines outputs from the AWS ECS cluster creation.
-type clusterOutput struct {
-	clusterArn pulumi.StringInput
-	id         pulumi.StringInput
+// Cluster is a component resource that manages an AWS ECS cluster.
+type Cluster struct {
+	pulumi.ResourceState
+
+	// Arn is the ARN of the cluster.
+	Arn pulumi.StringOutput
+	// ID is the provider-assigned ID of the cluster.
+	ID pulumi.IDOutput
+	// Name is the name of the cluster.
+	Name pulumi.StringOutput
 }
 
 // ClusterCapacityProviderConfig defines arguments for setting up capacity providers for an AWS ECS cluster.
@@ -90,6 +144,16 @@ type ClusterCapacityProviderConfig struct {
 	} `json:"defaultCapacityProviderStrategies"`
 }
 
+// ClusterCapacityProvider is a component resource that manages the capacity providers of an AWS ECS cluster.
+type ClusterCapacityProvider struct {
+	pulumi.ResourceState
+
+	// CapacityProviders are the names of the capacity providers attached to the cluster.
+	CapacityProviders pulumi.StringArrayOutput
+	// ClusterName is the name of the cluster.
+	ClusterName pulumi.StringOutput
+}
+
 // ServiceConfig defines arguments for creating an AWS ECS service.
 type ServiceConfig struct {
 	Alarms *struct {
@@ -97,6 +161,8 @@ type ServiceConfig struct {
 		Enable     bool     `json:"enable"`
 		Rollback   bool     `json:"rollback"`
 	} `json:"alarms"`
+	AutoScaling                *ServiceAutoScalingConfig `json:"autoScaling,omitempty"`
+	BlueGreen                  *ServiceBlueGreenConfig   `json:"blueGreen,omitempty"`
 	CapacityProviderStrategies []struct {
 		CapacityProvider string `json:"name"`
 		Base             *int   `json:"base,omitempty"`
@@ -110,15 +176,16 @@ type ServiceConfig struct {
 	DeploymentController *struct {
 		Type *string `json:"type,omitempty"`
 	} `json:"deploymentController"`
-	DeploymentMaximumPercent        *int    `json:"deploymentMaximumPercent,omitempty"`
-	DeploymentMinimumHealthyPercent *int    `json:"deploymentMinimumHealthyPercent,omitempty"`
-	DesiredCount                    *int    `json:"desiredCount,omitempty"`
-	EnableEcsManagedTags            *bool   `json:"enableEcsManagedTags,omitempty"`
-	EnableExecuteCommand            *bool   `json:"enableExecuteCommand,omitempty"`
-	ForceNewDeployment              *bool   `json:"forceNewDeployment,omitempty"`
-	HealthCheckGracePeriodSeconds   *int    `json:"healthCheckGracePeriodSeconds,omitempty"`
-	IamRole                         *string `json:"iamRole,omitempty"`
-	LaunchType                      *string `json:"launchType,omitempty"`
+	DeploymentMaximumPercent        *int                  `json:"deploymentMaximumPercent,omitempty"`
+	DeploymentMinimumHealthyPercent *int                  `json:"deploymentMinimumHealthyPercent,omitempty"`
+	DesiredCount                    *int                  `json:"desiredCount,omitempty"`
+	EnableEcsManagedTags            *bool                 `json:"enableEcsManagedTags,omitempty"`
+	EnableExecuteCommand            *bool                 `json:"enableExecuteCommand,omitempty"`
+	ForceNewDeployment              *bool                 `json:"forceNewDeployment,omitempty"`
+	HealthCheckGracePeriodSeconds   *int                  `json:"healthCheckGracePeriodSeconds,omitempty"`
+	IamRole                         *string               `json:"iamRole,omitempty"`
+	Ingress                         *ServiceIngressConfig `json:"ingress,omitempty"`
+	LaunchType                      *string               `json:"launchType,omitempty"`
 	LoadBalancers                   []struct {
 		ContainerName  string  `json:"containerName"`
 		ContainerPort  int     `json:"containerPort"`
@@ -155,8 +222,8 @@ type ServiceConfig struct {
 		Namespace *string `json:"namespace,omitempty"`
 		Services  []struct {
 			ClientAlias []struct {
-				Port    int    `json:"port"`
-				DNSName string `json:"dnsName,omitempty"`
+				Port    int     `json:"port"`
+				DNSName *string `json:"dnsName,omitempty"`
 			} `json:"clientAlias"`
 			DiscoveryName       *string `json:"discoveryName,omitempty"`
 			IngressPortOverride *int    `json:"ingressPortOverride,omitempty"`
@@ -194,34 +261,48 @@ type ServiceConfig struct {
 		} `json:"managedEBSVolume"`
 		Name string `json:"name"`
 	} `json:"serviceVolumeConfiguration"`
-	Tags               map[string]string `json:"tags"`
-	TaskDefinition     *string           `json:"taskDefinition,omitempty"`
-	Triggers           map[string]string `json:"triggers"`
-	WaitForSteadyState *bool             `json:"waitForSteadyState,omitempty"`
+	Tags               map[string]string     `json:"tags"`
+	Targets            []ServiceTargetConfig `json:"targets,omitempty"`
+	TaskDefinition     *string               `json:"taskDefinition,omitempty"`
+	Triggers           map[string]string     `json:"triggers"`
+	WaitForSteadyState *bool                 `json:"waitForSteadyState,omitempty"`
 }
 
-// serviceOutput defines outputs from the AWS ECS service creation.
-type serviceOutput struct {
-	id pulumi.StringInput
+// Service is a component resource that manages an AWS ECS service.
+type Service struct {
+	pulumi.ResourceState
+
+	// Arn is the ARN of the service.
+	Arn pulumi.StringOutput
+	// ID is the provider-assigned ID of the service.
+	ID pulumi.IDOutput
+	// Name is the name of the service.
+	Name pulumi.StringOutput
+	// TargetGroups are the target groups created for the targets of the service, by target name.
+	TargetGroups map[string]*lb.TargetGroup
 }
 
 // TaskDefinitionConfig defines arguments for creating an AWS ECS task definition.
 type TaskDefinitionConfig struct {
-	ContainerDefinitions []map[string]interface{} `json:"containerDefinitions"`
-	CPU                  *string                  `json:"cpu,omitempty"`
+	ContainerDefinitions []ContainerDefinition `json:"containerDefinitions"`
+	CPU                  *string               `json:"cpu,omitempty"`
 	EphemeralStorage     *struct {
 		SizeInGB int `json:"sizeInGb"`
 	} `json:"ephemeralStorage"`
-	ExecutionRoleArn      *string `json:"executionRoleArn,omitempty"`
+	ExecutionRole         *TaskDefinitionRoleConfig `json:"executionRole,omitempty"`
+	ExecutionRoleArn      *string                   `json:"executionRoleArn,omitempty"`
 	InferenceAccelerators []struct {
 		DeviceName string `json:"deviceName"`
 		DeviceType string `json:"deviceType"`
 	} `json:"inferenceAccelerators"`
-	IpcMode              *string `json:"ipcMode,omitempty"`
-	Memory               *string `json:"memory,omitempty"`
-	Name                 string  `json:"name"`
-	NetworkMode          *string `json:"networkMode,omitempty"`
-	PidMode              *string `json:"pidMode,omitempty"`
+	IpcMode              *string                  `json:"ipcMode,omitempty"`
+	LogRouting           *TaskLogRoutingConfig    `json:"logRouting,omitempty"`
+	Logging              *TaskLoggingConfig       `json:"logging,omitempty"`
+	Memory               *string                  `json:"memory,omitempty"`
+	Name                 string                   `json:"name"`
+	NetworkMode          *string                  `json:"networkMode,omitempty"`
+	Observability        *TaskObservabilityConfig `json:"observability,omitempty"`
+	PidMode              *string                  `json:"pidMode,omitempty"`
 	PlacementConstraints []struct {
 		Expression *string `json:"expression,omitempty"`
 		Type       string  `json:"type"`
@@ -236,10 +317,12 @@ type TaskDefinitionConfig struct {
 		CPUArchitecture       *string `json:"cpuArchitecture,omitempty"`
 		OperatingSystemFamily *string `json:"operatingSystemFamily,omitempty"`
 	} `json:"runtimePlatform"`
-	SkipDestroy *bool             `json:"skipDestroy,omitempty"`
-	Tags        map[string]string `json:"tags"`
-	TaskRoleArn *string           `json:"taskRoleArn,omitempty"`
-	TrackLatest *bool             `json:"trackLatest,omitempty"`
+	Secrets     []TaskSecretConfig        `json:"secrets,omitempty"`
+	SkipDestroy *bool                     `json:"skipDestroy,omitempty"`
+	Tags        map[string]string         `json:"tags"`
+	TaskRole    *TaskDefinitionRoleConfig `json:"taskRole,omitempty"`
+	TaskRoleArn *string                   `json:"taskRoleArn,omitempty"`
+	TrackLatest *bool                     `json:"trackLatest,omitempty"`
 	Volumes     []struct {
 		ConfigureAtLaunch         *bool `json:"configureAtLaunch,omitempty"`
 		DockerVolumeConfiguration *struct {
@@ -272,9 +355,22 @@ type TaskDefinitionConfig struct {
 	} `json:"volumes"`
 }
 
-// taskDefinitionOutput defines outputs from the AWS ECS task definition creation.
-type taskDefinitionOutput struct {
-	arn pulumi.StringOutput
+// TaskDefinition is a component resource that manages an AWS ECS task definition.
+type TaskDefinition struct {
+	pulumi.ResourceState
+
+	// Arn is the full ARN of the task definition, including its revision.
+	Arn pulumi.StringOutput
+	// ExecutionRole is the execution role created for the task definition, or nil if none was created.
+	ExecutionRole *iam.Role
+	// Family is the family of the task definition.
+	Family pulumi.StringOutput
+	// LogGroups are the log groups created for the containers of the task definition, by name.
+	LogGroups map[string]*cloudwatch.LogGroup
+	// Revision is the revision of the task definition within its family.
+	Revision pulumi.IntOutput
+	// TaskRole is the task role created for the task definition, or nil if none was created.
+	TaskRole *iam.Role
 }
 
 // TaskSetConfig defines arguments for creating an AWS ECS task set.
@@ -318,600 +414,896 @@ type TaskSetConfig struct {
 	WaitUntilStableTimeout *string           `json:"waitUntilStableTimeout,omitempty"`
 }
 
-// taskSetOutput defines outputs from the AWS ECS task set creation.
-type taskSetOutput struct {
-	arn pulumi.StringOutput
+// TaskSet is a component resource that manages an AWS ECS task set.
+type TaskSet struct {
+	pulumi.ResourceState
+
+	// Arn is the ARN of the task set.
+	Arn pulumi.StringOutput
+	// ID is the ID of the task set.
+	ID pulumi.StringOutput
+	// StabilityStatus is the stability status of the task set, either STEADY_STATE or STABILIZING.
+	StabilityStatus pulumi.StringOutput
 }
 
 // NewAccountSettingsDefault creates new AWS ECS default account settings.
-func NewAccountSettingsDefault(ctx *pulumi.Context, accountSettingsDefault []AccountSettingDefaultConfig, opts ...pulumi.ResourceOption) ([]*ecs.AccountSettingDefault, error) {
-	component := &pulumi.ResourceState{}
+func NewAccountSettingsDefault(ctx *pulumi.Context, accountSettingsDefault []AccountSettingDefaultConfig, opts ...pulumi.ResourceOption) ([]*AccountSettingDefault, error) {
+	accountSettingsDefault, err := interpolate(ctx, accountSettingsDefault)
+	if err != nil {
+		return nil, err
+	}
 
-	var accountSettingsDefaultOutputs []*ecs.AccountSettingDefault
+	var args []*AccountSettingDefaultArgs
+	for _, accountSettingDefault := range accountSettingsDefault {
+		args = append(args, accountSettingDefault.ToArgs())
+	}
+
+	return NewAccountSettingsDefaultFromArgs(ctx, args, opts...)
+}
+
+// NewAccountSettingsDefaultFromArgs creates new AWS ECS default account settings from inputs.
+// Each account setting is registered as its own component resource, named after the setting.
+func NewAccountSettingsDefaultFromArgs(ctx *pulumi.Context, accountSettingsDefault []*AccountSettingDefaultArgs, opts ...pulumi.ResourceOption) ([]*AccountSettingDefault, error) {
+	var components []*AccountSettingDefault
+	opts, childAlias := legacyAliases(AccountSettingDefaultType, opts)
 	for i, accountSettingDefault := range accountSettingsDefault {
-		err := ctx.RegisterComponentResource("aws:ecs:AccountSettingsDefault", accountSettingDefault.Name, component, opts...)
+		err := resolveReferences(ctx, accountSettingDefault)
 		if err != nil {
-			return accountSettingsDefaultOutputs, fmt.Errorf("failed to register component resource: %v", err)
+			return components, err
 		}
 
-		output, err := ecs.NewAccountSettingDefault(ctx, fmt.Sprintf("accountSettingDefault-%d", i+1), &ecs.AccountSettingDefaultArgs{
+		component := &AccountSettingDefault{}
+		err = ctx.RegisterComponentResource(AccountSettingDefaultType, accountSettingDefault.Name, component, opts...)
+		if err != nil {
+			return components, fmt.Errorf("failed to register component resource: %v", err)
+		}
+
+		output, err := ecs.NewAccountSettingDefault(ctx, accountSettingDefault.Name, &ecs.AccountSettingDefaultArgs{
 			Name:  pulumi.String(accountSettingDefault.Name),
-			Value: pulumi.String(accountSettingDefault.Value),
-		}, pulumi.Parent(component))
+			Value: accountSettingDefault.Value,
+		}, pulumi.Parent(component), childAlias(fmt.Sprintf("accountSettingDefault-%d", i+1)))
 		if err != nil {
-			return accountSettingsDefaultOutputs, fmt.Errorf("failed to create new default account setting: %v", err)
+			return components, fmt.Errorf("failed to create new default account setting: %v", err)
 		}
-		accountSettingsDefaultOutputs = append(accountSettingsDefaultOutputs, output)
+
+		component.Name = output.Name
+		component.Value = output.Value
+
+		err = ctx.RegisterResourceOutputs(component, pulumi.Map{
+			"name":  component.Name,
+			"value": component.Value,
+		})
+		if err != nil {
+			return components, fmt.Errorf("failed to register component resource outputs: %v", err)
+		}
+
+		components = append(components, component)
 	}
 
-	return accountSettingsDefaultOutputs, nil
+	return components, nil
 }
 
 // NewCapacityProviders creates new ECS capacity providers.
-func NewCapacityProviders(ctx *pulumi.Context, capacityProviders []CapacityProviderConfig, opts ...pulumi.ResourceOption) error {
-	component := &pulumi.ResourceState{}
+func NewCapacityProviders(ctx *pulumi.Context, capacityProviders []CapacityProviderConfig, opts ...pulumi.ResourceOption) ([]*CapacityProvider, error) {
+	capacityProviders, err := interpolate(ctx, capacityProviders)
+	if err != nil {
+		return nil, err
+	}
 
+	v := &validator{}
 	for i, capacityProvider := range capacityProviders {
-		err := ctx.RegisterComponentResource("aws:ecs:capacityProvider", capacityProvider.Name, component, opts...)
+		capacityProvider.validate(v, fmt.Sprintf("capacityProviders[%d]", i))
+	}
+	err = v.err()
+	if err != nil {
+		return nil, err
+	}
+
+	var args []*CapacityProviderArgs
+	for _, capacityProvider := range capacityProviders {
+		args = append(args, capacityProvider.ToArgs())
+	}
+
+	return NewCapacityProvidersFromArgs(ctx, args, opts...)
+}
+
+// NewCapacityProvidersFromArgs creates new ECS capacity providers from inputs.
+// Each capacity provider is registered as its own component resource, named after the capacity provider.
+func NewCapacityProvidersFromArgs(ctx *pulumi.Context, capacityProviders []*CapacityProviderArgs, opts ...pulumi.ResourceOption) ([]*CapacityProvider, error) {
+	var components []*CapacityProvider
+	opts, childAlias := legacyAliases(CapacityProviderType, opts)
+	for i, capacityProvider := range capacityProviders {
+		err := resolveReferences(ctx, capacityProvider)
 		if err != nil {
-			return fmt.Errorf("failed to register component resource: %v", err)
+			return nil, err
 		}
 
-		var managedScaling *ecs.CapacityProviderAutoScalingGroupProviderManagedScalingArgs
-		if capacityProvider.AutoscalingGroupProvider.ManagedScaling != nil {
-			managedScaling = &ecs.CapacityProviderAutoScalingGroupProviderManagedScalingArgs{
-				InstanceWarmupPeriod:   pulumi.IntPtrFromPtr(capacityProvider.AutoscalingGroupProvider.ManagedScaling.InstanceWarmupPeriod),
-				MaximumScalingStepSize: pulumi.IntPtrFromPtr(capacityProvider.AutoscalingGroupProvider.ManagedScaling.MaximumScalingStepSize),
-				MinimumScalingStepSize: pulumi.IntPtrFromPtr(capacityProvider.AutoscalingGroupProvider.ManagedScaling.MinimumScalingStepSize),
-				Status:                 pulumi.StringPtrFromPtr(capacityProvider.AutoscalingGroupProvider.ManagedScaling.Status),
-				TargetCapacity:         pulumi.IntPtrFromPtr(capacityProvider.AutoscalingGroupProvider.ManagedScaling.TargetCapacity),
-			}
+		component := &CapacityProvider{}
+		err = ctx.RegisterComponentResource(CapacityProviderType, capacityProvider.Name, component, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register component resource: %v", err)
+		}
+
+		output, err := ecs.NewCapacityProvider(ctx, capacityProvider.Name, &ecs.CapacityProviderArgs{
+			AutoScalingGroupProvider: capacityProvider.AutoScalingGroupProvider,
+			Name:                     pulumi.String(capacityProvider.Name),
+			Tags:                     capacityProvider.Tags,
+		}, pulumi.Parent(component), childAlias(fmt.Sprintf("capacityProvider-%d", i+1)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new capacity provider: %v", err)
 		}
 
-		_, err = ecs.NewCapacityProvider(ctx, fmt.Sprintf("capacityProvider-%d", i+1), &ecs.CapacityProviderArgs{
-			AutoScalingGroupProvider: &ecs.CapacityProviderAutoScalingGroupProviderArgs{
-				AutoScalingGroupArn:          pulumi.String(capacityProvider.AutoscalingGroupProvider.AutoscalingGroupArn),
-				ManagedDraining:              pulumi.StringPtrFromPtr(capacityProvider.AutoscalingGroupProvider.ManagedDraining),
-				ManagedScaling:               managedScaling,
-				ManagedTerminationProtection: pulumi.StringPtrFromPtr(capacityProvider.AutoscalingGroupProvider.ManagedTerminationProtection),
-			},
-			Name: pulumi.String(capacityProvider.Name),
-			Tags: pulumi.ToStringMap(capacityProvider.Tags),
-		}, pulumi.Parent(component))
+		component.Arn = output.Arn
+		component.Name = output.Name
+
+		err = ctx.RegisterResourceOutputs(component, pulumi.Map{
+			"arn":  component.Arn,
+			"name": component.Name,
+		})
 		if err != nil {
-			return fmt.Errorf("failed to create new capacity provider: %v", err)
+			return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
 		}
+
+		components = append(components, component)
 	}
 
-	return nil
+	return components, nil
 }
 
 // NewCluster creates a new ECS cluster.
-func NewCluster(ctx *pulumi.Context, config ClusterConfig, opts ...pulumi.ResourceOption) (*clusterOutput, error) {
-	component := &pulumi.ResourceState{}
-	err := ctx.RegisterComponentResource("aws:ecs:Cluster", config.Name, component, opts...)
+func NewCluster(ctx *pulumi.Context, config ClusterConfig, opts ...pulumi.ResourceOption) (*Cluster, error) {
+	config, err := interpolate(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to register component resource: %v", err)
+		return nil, err
 	}
 
-	var settings ecs.ClusterSettingArray
-	for _, setting := range config.Settings {
-		settings = append(settings, &ecs.ClusterSettingArgs{
-			Name:  pulumi.String(setting.Name),
-			Value: pulumi.String(setting.Value),
-		})
+	err = config.Validate()
+	if err != nil {
+		return nil, err
 	}
 
-	var configuration *ecs.ClusterConfigurationArgs
-	if config.Configuration != nil {
-		var logConfiguration *ecs.ClusterConfigurationExecuteCommandConfigurationLogConfigurationArgs
-		if config.Configuration.ExecuteCommand.LogConfiguration != nil {
-			logConfiguration = &ecs.ClusterConfigurationExecuteCommandConfigurationLogConfigurationArgs{
-				CloudWatchEncryptionEnabled: pulumi.BoolPtrFromPtr(config.Configuration.ExecuteCommand.LogConfiguration.CloudWatchEncryptionEnabled),
-				CloudWatchLogGroupName:      pulumi.StringPtrFromPtr(config.Configuration.ExecuteCommand.LogConfiguration.CloudWatchLogGroupName),
-				S3BucketEncryptionEnabled:   pulumi.BoolPtrFromPtr(config.Configuration.ExecuteCommand.LogConfiguration.S3BucketEncryptionEnabled),
-				S3BucketName:                pulumi.StringPtrFromPtr(config.Configuration.ExecuteCommand.LogConfiguration.S3BucketName),
-				S3KeyPrefix:                 pulumi.StringPtrFromPtr(config.Configuration.ExecuteCommand.LogConfiguration.S3KeyPrefix),
-			}
-		}
-		configuration = &ecs.ClusterConfigurationArgs{
-			ExecuteCommandConfiguration: &ecs.ClusterConfigurationExecuteCommandConfigurationArgs{
-				KmsKeyId:         pulumi.StringPtrFromPtr(config.Configuration.ExecuteCommand.KmsKeyID),
-				LogConfiguration: logConfiguration,
-				Logging:          pulumi.StringPtrFromPtr(config.Configuration.ExecuteCommand.Logging),
-			},
-		}
+	return NewClusterFromArgs(ctx, config.Name, config.ToArgs(), opts...)
+}
+
+// NewClusterFromArgs creates a new ECS cluster from inputs.
+func NewClusterFromArgs(ctx *pulumi.Context, name string, args *ClusterArgs, opts ...pulumi.ResourceOption) (*Cluster, error) {
+	if args == nil {
+		args = &ClusterArgs{}
 	}
 
-	var serviceConnectDefaults *ecs.ClusterServiceConnectDefaultsArgs
-	if config.ServiceConnectDefaults != nil {
-		serviceConnectDefaults = &ecs.ClusterServiceConnectDefaultsArgs{
-			Namespace: pulumi.String(config.ServiceConnectDefaults.Namespace),
-		}
+	err := resolveReferences(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, childAlias := legacyAliases(ClusterType, opts)
+	component := &Cluster{}
+	err = ctx.RegisterComponentResource(ClusterType, name, component, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource: %v", err)
 	}
 
-	cluster, err := ecs.NewCluster(ctx, "cluster", &ecs.ClusterArgs{
-		Configuration:          configuration,
-		Name:                   pulumi.String(config.Name),
-		ServiceConnectDefaults: serviceConnectDefaults,
-		Settings:               settings,
-		Tags:                   pulumi.ToStringMap(config.Tags),
-	}, pulumi.Parent(component))
+	cluster, err := ecs.NewCluster(ctx, name, &ecs.ClusterArgs{
+		Configuration:          args.Configuration,
+		Name:                   args.Name,
+		ServiceConnectDefaults: args.ServiceConnectDefaults,
+		Settings:               args.Settings,
+		Tags:                   args.Tags,
+	}, pulumi.Parent(component), childAlias("cluster"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new cluster: %v", err)
 	}
 
-	return &clusterOutput{
-		clusterArn: cluster.Arn,
-		id:         cluster.ID(),
-	}, nil
+	component.Arn = cluster.Arn
+	component.ID = cluster.ID()
+	component.Name = cluster.Name
+
+	err = ctx.RegisterResourceOutputs(component, pulumi.Map{
+		"arn":  component.Arn,
+		"id":   component.ID,
+		"name": component.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+	}
+
+	return component, nil
 }
 
 // NewClusterCapacityProvider creates a new capacity provider for an AWS ECS cluster.
-func NewClusterCapacityProvider(ctx *pulumi.Context, config ClusterCapacityProviderConfig, opts ...pulumi.ResourceOption) error {
-	component := &pulumi.ResourceState{}
-	err := ctx.RegisterComponentResource("aws:ecs:clusterCapacityProvider", config.ClusterName, component, opts...)
+func NewClusterCapacityProvider(ctx *pulumi.Context, config ClusterCapacityProviderConfig, opts ...pulumi.ResourceOption) (*ClusterCapacityProvider, error) {
+	config, err := interpolate(ctx, config)
 	if err != nil {
-		return fmt.Errorf("failed to register component resource: %v", err)
+		return nil, err
 	}
 
-	var defaultCapacityProviderStrategies ecs.ClusterCapacityProvidersDefaultCapacityProviderStrategyArray
-	for _, defaultCapacityProviderStrategy := range config.DefaultCapacityProviderStrategies {
-		defaultCapacityProviderStrategies = append(defaultCapacityProviderStrategies, &ecs.ClusterCapacityProvidersDefaultCapacityProviderStrategyArgs{
-			Base:             pulumi.IntPtrFromPtr(defaultCapacityProviderStrategy.Base),
-			CapacityProvider: pulumi.String(defaultCapacityProviderStrategy.CapacityProvider),
-			Weight:           pulumi.IntPtrFromPtr(defaultCapacityProviderStrategy.Weight),
-		})
+	return NewClusterCapacityProviderFromArgs(ctx, config.ClusterName, config.ToArgs(), opts...)
+}
+
+// NewClusterCapacityProviderFromArgs creates a new capacity provider for an AWS ECS cluster from inputs.
+func NewClusterCapacityProviderFromArgs(ctx *pulumi.Context, name string, args *ClusterCapacityProviderArgs, opts ...pulumi.ResourceOption) (*ClusterCapacityProvider, error) {
+	if args == nil {
+		args = &ClusterCapacityProviderArgs{}
 	}
 
-	_, err = ecs.NewClusterCapacityProviders(ctx, "clusterCapacityProviders", &ecs.ClusterCapacityProvidersArgs{
-		CapacityProviders:                 pulumi.ToStringArray(config.CapacityProviders),
-		ClusterName:                       pulumi.String(config.ClusterName),
-		DefaultCapacityProviderStrategies: defaultCapacityProviderStrategies,
-	}, pulumi.Parent(component))
+	err := resolveReferences(ctx, args)
 	if err != nil {
-		return fmt.Errorf("failed to create new cluster capacity provider: %v", err)
+		return nil, err
 	}
 
-	return nil
+	opts, childAlias := legacyAliases(ClusterCapacityProviderType, opts)
+	component := &ClusterCapacityProvider{}
+	err = ctx.RegisterComponentResource(ClusterCapacityProviderType, name, component, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource: %v", err)
+	}
+
+	clusterCapacityProviders, err := ecs.NewClusterCapacityProviders(ctx, name, &ecs.ClusterCapacityProvidersArgs{
+		CapacityProviders:                 args.CapacityProviders,
+		ClusterName:                       args.ClusterName,
+		DefaultCapacityProviderStrategies: args.DefaultCapacityProviderStrategies,
+	}, pulumi.Parent(component), childAlias("clusterCapacityProviders"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new cluster capacity provider: %v", err)
+	}
+
+	component.CapacityProviders = clusterCapacityProviders.CapacityProviders
+	component.ClusterName = clusterCapacityProviders.ClusterName
+
+	err = ctx.RegisterResourceOutputs(component, pulumi.Map{
+		"capacityProviders": component.CapacityProviders,
+		"clusterName":       component.ClusterName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+	}
+
+	return component, nil
 }
 
-func createServiceConnectConfiguration(config ServiceConfig) *ecs.ServiceServiceConnectConfigurationArgs {
-	var serviceConnectConfiguration *ecs.ServiceServiceConnectConfigurationArgs
-	if config.ServiceConnectConfiguration != nil {
-		var serviceConnectServicesLogConfiguration *ecs.ServiceServiceConnectConfigurationLogConfigurationArgs
-		var serviceConnectLogsSecretOptions ecs.ServiceServiceConnectConfigurationLogConfigurationSecretOptionArray
-		if config.ServiceConnectConfiguration.LogConfiguration != nil {
-			for _, serviceConnectLogsSecretOption := range config.ServiceConnectConfiguration.LogConfiguration.SecretOptions {
-				serviceConnectLogsSecretOptions = append(serviceConnectLogsSecretOptions, &ecs.ServiceServiceConnectConfigurationLogConfigurationSecretOptionArgs{
-					Name:      pulumi.String(serviceConnectLogsSecretOption.Name),
-					ValueFrom: pulumi.String(serviceConnectLogsSecretOption.ValueFrom),
-				})
-			}
+// NewService creates a new AWS ECS service.
+func NewService(ctx *pulumi.Context, config ServiceConfig, opts ...pulumi.ResourceOption) (*Service, error) {
+	config, err := interpolate(ctx, config)
+	if err != nil {
+		return nil, err
+	}
 
-			serviceConnectServicesLogConfiguration = &ecs.ServiceServiceConnectConfigurationLogConfigurationArgs{
-				LogDriver:     pulumi.String(config.ServiceConnectConfiguration.LogConfiguration.LogDriver),
-				Options:       pulumi.ToStringMap(config.ServiceConnectConfiguration.LogConfiguration.Options),
-				SecretOptions: serviceConnectLogsSecretOptions,
-			}
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServiceFromArgs(ctx, config.Name, config.ToArgs(), opts...)
+}
+
+// NewServiceFromArgs creates a new AWS ECS service from inputs.
+func NewServiceFromArgs(ctx *pulumi.Context, name string, args *ServiceArgs, opts ...pulumi.ResourceOption) (*Service, error) {
+	if args == nil {
+		args = &ServiceArgs{}
+	}
+
+	err := resolveReferences(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err = withBlueGreenController(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, childAlias := legacyAliases(ServiceType, opts)
+	component := &Service{}
+	err = ctx.RegisterComponentResource(ServiceType, name, component, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource: %v", err)
+	}
+
+	ignoreChanges, warnings := serviceIgnoreChanges(args)
+	err = logWarnings(ctx, component, warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	routing, err := newServiceRouting(ctx, name, args, component)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceOpts := []pulumi.ResourceOption{pulumi.Parent(component), childAlias("service"), pulumi.IgnoreChanges(ignoreChanges)}
+	if len(routing.dependencies) > 0 {
+		serviceOpts = append(serviceOpts, pulumi.DependsOn(routing.dependencies))
+	}
+	service, err := ecs.NewService(ctx, name, &ecs.ServiceArgs{
+		Alarms:                          args.Alarms,
+		CapacityProviderStrategies:      args.CapacityProviderStrategies,
+		Cluster:                         args.ClusterArn,
+		DeploymentCircuitBreaker:        args.DeploymentCircuitBreaker,
+		DeploymentController:            args.DeploymentController,
+		DeploymentMaximumPercent:        args.DeploymentMaximumPercent,
+		DeploymentMinimumHealthyPercent: args.DeploymentMinimumHealthyPercent,
+		DesiredCount:                    args.DesiredCount,
+		EnableEcsManagedTags:            args.EnableEcsManagedTags,
+		EnableExecuteCommand:            args.EnableExecuteCommand,
+		ForceNewDeployment:              args.ForceNewDeployment,
+		HealthCheckGracePeriodSeconds:   routing.healthCheckGracePeriodSeconds,
+		IamRole:                         args.IamRole,
+		LaunchType:                      args.LaunchType,
+		LoadBalancers:                   routing.loadBalancers,
+		Name:                            args.Name,
+		NetworkConfiguration:            args.NetworkConfiguration,
+		OrderedPlacementStrategies:      args.OrderedPlacementStrategies,
+		PlacementConstraints:            args.PlacementConstraints,
+		PlatformVersion:                 args.PlatformVersion,
+		PropagateTags:                   args.PropagateTags,
+		SchedulingStrategy:              args.SchedulingStrategy,
+		ServiceConnectConfiguration:     args.ServiceConnectConfiguration,
+		ServiceRegistries:               args.ServiceRegistry,
+		Tags:                            args.Tags,
+		TaskDefinition:                  args.TaskDefinition,
+		Triggers:                        args.Triggers,
+		VolumeConfiguration:             args.ServiceVolumeConfiguration,
+		WaitForSteadyState:              args.WaitForSteadyState,
+	}, serviceOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new service: %v", err)
+	}
+
+	if args.BlueGreen != nil {
+		err = newBlueGreenDeployment(ctx, name, args.BlueGreen, routing.blueGreen, service.Cluster, service.Name, args.Tags, component)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		var serviceConnectServicesClientAliases ecs.ServiceServiceConnectConfigurationServiceClientAliasArray
-		for _, service := range config.ServiceConnectConfiguration.Services {
-			for _, clientAlias := range service.ClientAlias {
-				serviceConnectServicesClientAliases = append(serviceConnectServicesClientAliases, &ecs.ServiceServiceConnectConfigurationServiceClientAliasArgs{
-					DnsName: pulumi.String(clientAlias.DNSName),
-					Port:    pulumi.Int(clientAlias.Port),
-				})
-			}
+	if args.AutoScaling != nil {
+		err = newServiceAutoScaling(ctx, name, args.AutoScaling, service.Cluster, service.Name, args.Tags, component)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		var serviceConnectServices ecs.ServiceServiceConnectConfigurationServiceArray
-		for _, serviceConnectService := range config.ServiceConnectConfiguration.Services {
-			var timeout *ecs.ServiceServiceConnectConfigurationServiceTimeoutArgs
-			if serviceConnectService.Timeout != nil {
-				timeout = &ecs.ServiceServiceConnectConfigurationServiceTimeoutArgs{
-					IdleTimeoutSeconds:       pulumi.IntPtrFromPtr(serviceConnectService.Timeout.IdleTimeoutSeconds),
-					PerRequestTimeoutSeconds: pulumi.IntPtrFromPtr(serviceConnectService.Timeout.PerRequestTimeoutSeconds),
-				}
-			}
+	// The provider uses the ARN of a service as its ID.
+	component.Arn = service.ID().ToStringOutput()
+	component.ID = service.ID()
+	component.Name = service.Name
 
-			var tls *ecs.ServiceServiceConnectConfigurationServiceTlsArgs
-			if serviceConnectService.TLS != nil {
-				tls = &ecs.ServiceServiceConnectConfigurationServiceTlsArgs{
-					IssuerCertAuthority: &ecs.ServiceServiceConnectConfigurationServiceTlsIssuerCertAuthorityArgs{
-						AwsPcaAuthorityArn: pulumi.String(serviceConnectService.TLS.IssuerCertAuthority.AwsPcaAuthorityArn),
-					},
-					KmsKey:  pulumi.StringPtrFromPtr(serviceConnectService.TLS.KmsKey),
-					RoleArn: pulumi.StringPtrFromPtr(serviceConnectService.TLS.RoleArn),
-				}
-			}
+	err = ctx.RegisterResourceOutputs(component, component.outputs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+	}
 
-			serviceConnectServices = append(serviceConnectServices, &ecs.ServiceServiceConnectConfigurationServiceArgs{
-				ClientAlias:         serviceConnectServicesClientAliases,
-				DiscoveryName:       pulumi.StringPtrFromPtr(serviceConnectService.DiscoveryName),
-				IngressPortOverride: pulumi.IntPtrFromPtr(serviceConnectService.IngressPortOverride),
-				PortName:            pulumi.String(serviceConnectService.PortName),
-				Timeout:             timeout,
-				Tls:                 tls,
-			})
-		}
+	return component, nil
+}
 
-		serviceConnectConfiguration = &ecs.ServiceServiceConnectConfigurationArgs{
-			Enabled:          pulumi.Bool(config.ServiceConnectConfiguration.Enabled),
-			LogConfiguration: serviceConnectServicesLogConfiguration,
-			Namespace:        pulumi.StringPtrFromPtr(config.ServiceConnectConfiguration.Namespace),
-			Services:         serviceConnectServices,
+// outputs returns the outputs of the service component, including the ARNs of its target groups by target name.
+func (s *Service) outputs() pulumi.Map {
+	outputs := pulumi.Map{
+		"arn":  s.Arn,
+		"id":   s.ID,
+		"name": s.Name,
+	}
+	if len(s.TargetGroups) > 0 {
+		targetGroupArns := pulumi.StringMap{}
+		for targetName, targetGroup := range s.TargetGroups {
+			targetGroupArns[targetName] = targetGroup.Arn
 		}
+		outputs["targetGroupArns"] = targetGroupArns
 	}
+	return outputs
+}
 
-	return serviceConnectConfiguration
+// logWarnings logs each of the warnings on resource.
+func logWarnings(ctx *pulumi.Context, resource pulumi.Resource, warnings []string) error {
+	for _, warning := range warnings {
+		err := ctx.Log.Warn(warning, &pulumi.LogArgs{Resource: resource})
+		if err != nil {
+			return fmt.Errorf("failed to log warning: %v", err)
+		}
+	}
+	return nil
 }
 
-// NewService creates a new AWS ECS service.
-func NewService(ctx *pulumi.Context, config ServiceConfig, opts ...pulumi.ResourceOption) (*serviceOutput, error) {
-	component := &pulumi.ResourceState{}
-	err := ctx.RegisterComponentResource("aws:ecs:Service", config.Name, component, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to register component resource: %v", err)
+// withBlueGreenController returns args with the CODE_DEPLOY deployment controller if the service is deployed
+// blue/green, since blue/green deployments are run by CodeDeploy. Another deployment controller is rejected.
+func withBlueGreenController(name string, args *ServiceArgs) (*ServiceArgs, error) {
+	if args.BlueGreen == nil {
+		return args, nil
 	}
 
-	var alarms *ecs.ServiceAlarmsArgs
-	if config.Alarms != nil {
-		alarms = &ecs.ServiceAlarmsArgs{
-			AlarmNames: pulumi.ToStringArray(config.Alarms.AlarmNames),
-			Enable:     pulumi.Bool(config.Alarms.Enable),
-			Rollback:   pulumi.Bool(config.Alarms.Rollback),
+	if args.DeploymentController == nil {
+		withController := *args
+		withController.DeploymentController = &ecs.ServiceDeploymentControllerArgs{
+			Type: pulumi.String("CODE_DEPLOY"),
 		}
+		return &withController, nil
 	}
+	if controller, ok := plainString(args.DeploymentController.Type); ok && controller != "CODE_DEPLOY" {
+		return nil, fmt.Errorf("service %s requires the CODE_DEPLOY deployment controller for blue/green deployments, got %q", name, controller)
+	}
+	return args, nil
+}
 
-	var capacityProviderStrategies ecs.ServiceCapacityProviderStrategyArray
-	for _, capacityProviderStrategy := range config.CapacityProviderStrategies {
-		capacityProviderStrategies = append(capacityProviderStrategies, &ecs.ServiceCapacityProviderStrategyArgs{
-			CapacityProvider: pulumi.String(capacityProviderStrategy.CapacityProvider),
-			Base:             pulumi.IntPtrFromPtr(capacityProviderStrategy.Base),
-			Weight:           pulumi.IntPtrFromPtr(capacityProviderStrategy.Weight),
-		})
+// serviceRouting holds the load balancers of a service, the blue/green target groups and listener rules, and the
+// resources that have to be created before the service.
+type serviceRouting struct {
+	blueGreen                     *blueGreenTargets
+	dependencies                  []pulumi.Resource
+	healthCheckGracePeriodSeconds pulumi.IntPtrInput
+	loadBalancers                 ecs.ServiceLoadBalancerArray
+}
+
+// newServiceRouting creates the target groups and listener rules of the blue/green deployment, targets and ingress
+// of a service as children of component. With blue/green deployments, the service starts out behind the blue target
+// group, and with an ingress or targets behind target groups of its own. These have to be attached to the listeners
+// before the service is created.
+func newServiceRouting(ctx *pulumi.Context, name string, args *ServiceArgs, component *Service) (*serviceRouting, error) {
+	routing := &serviceRouting{
+		healthCheckGracePeriodSeconds: args.HealthCheckGracePeriodSeconds,
+		loadBalancers:                 args.LoadBalancers,
 	}
 
-	var deploymentCircuitBreaker *ecs.ServiceDeploymentCircuitBreakerArgs
-	if config.DeploymentCircuitBreaker != nil {
-		deploymentCircuitBreaker = &ecs.ServiceDeploymentCircuitBreakerArgs{
-			Enable:   pulumi.Bool(config.DeploymentCircuitBreaker.Enable),
-			Rollback: pulumi.Bool(config.DeploymentCircuitBreaker.Rollback),
+	var err error
+	if args.BlueGreen != nil {
+		routing.blueGreen, err = newBlueGreenTargets(ctx, name, args.BlueGreen, args.Tags, component)
+		if err != nil {
+			return nil, err
 		}
+		routing.loadBalancers = append(ecs.ServiceLoadBalancerArray{routing.blueGreen.loadBalancer(args.BlueGreen)}, routing.loadBalancers...)
+		routing.dependencies = append(routing.dependencies, routing.blueGreen.listenerRules...)
 	}
 
-	var deploymentController *ecs.ServiceDeploymentControllerArgs
-	if config.DeploymentController != nil {
-		deploymentController = &ecs.ServiceDeploymentControllerArgs{
-			Type: pulumi.StringPtrFromPtr(config.DeploymentController.Type),
+	if len(args.Targets) > 0 {
+		var loadBalancers ecs.ServiceLoadBalancerArray
+		var listeners []pulumi.Resource
+		component.TargetGroups, loadBalancers, listeners, err = newServiceTargets(ctx, name, args.Targets, args.Tags, component)
+		if err != nil {
+			return nil, err
 		}
+		routing.loadBalancers = append(loadBalancers, routing.loadBalancers...)
+		routing.dependencies = append(routing.dependencies, listeners...)
 	}
 
-	var loadBalancers ecs.ServiceLoadBalancerArray
-	for _, loadBalancer := range config.LoadBalancers {
-		loadBalancers = append(loadBalancers, &ecs.ServiceLoadBalancerArgs{
-			ContainerName:  pulumi.String(loadBalancer.ContainerName),
-			ContainerPort:  pulumi.Int(loadBalancer.ContainerPort),
-			ElbName:        pulumi.StringPtrFromPtr(loadBalancer.ElbName),
-			TargetGroupArn: pulumi.StringPtrFromPtr(loadBalancer.TargetGroupArn),
-		})
+	if args.Ingress != nil {
+		var securityGroups pulumi.StringArrayInput
+		if args.NetworkConfiguration != nil {
+			securityGroups = args.NetworkConfiguration.SecurityGroups
+		}
+		loadBalancer, listenerRule, err := newServiceIngress(ctx, name, args.Ingress, securityGroups, args.Tags, component)
+		if err != nil {
+			return nil, err
+		}
+		routing.loadBalancers = append(ecs.ServiceLoadBalancerArray{loadBalancer}, routing.loadBalancers...)
+		routing.dependencies = append(routing.dependencies, listenerRule)
+		if routing.healthCheckGracePeriodSeconds == nil {
+			routing.healthCheckGracePeriodSeconds = pulumi.Int(defaultIngressHealthCheckGracePeriodSeconds)
+		}
 	}
 
-	var networkConfiguration *ecs.ServiceNetworkConfigurationArgs
-	if config.NetworkConfiguration != nil {
-		networkConfiguration = &ecs.ServiceNetworkConfigurationArgs{
-			AssignPublicIp: pulumi.BoolPtrFromPtr(config.NetworkConfiguration.AssignPublicIP),
-			SecurityGroups: pulumi.ToStringArray(config.NetworkConfiguration.SecurityGroups),
-			Subnets:        pulumi.ToStringArray(config.NetworkConfiguration.Subnets),
+	return routing, nil
+}
+
+// serviceIgnoreChanges returns the properties of a service that are changed outside of Pulumi, so that updates do
+// not revert them, together with a warning for each of them that is still set in args:
+//   - the desired count, when the service is scaled by Application Auto Scaling;
+//   - the task definition and load balancers, when CodeDeploy deploys the service;
+//   - all three, when the EXTERNAL deployment controller manages the service through task sets.
+//
+// A deployment controller type that is only known during the deployment cannot be acted on, which is warned about too.
+func serviceIgnoreChanges(args *ServiceArgs) ([]string, []string) {
+	var ignoreChanges, warnings []string
+
+	var controller string
+	if args.DeploymentController != nil && args.DeploymentController.Type != nil {
+		var ok bool
+		controller, ok = plainString(args.DeploymentController.Type)
+		if !ok {
+			warnings = append(warnings, "deploymentController.type is not known before the deployment, so the properties "+
+				"managed by the deployment controller are not ignored and `pulumi up` may revert them; set it to a plain string")
 		}
 	}
 
-	var orderedPlacementStrategies ecs.ServiceOrderedPlacementStrategyArray
-	for _, orderedPlacementStrategy := range config.OrderedPlacementStrategies {
-		orderedPlacementStrategies = append(orderedPlacementStrategies, &ecs.ServiceOrderedPlacementStrategyArgs{
-			Field: pulumi.StringPtrFromPtr(orderedPlacementStrategy.Field),
-			Type:  pulumi.String(orderedPlacementStrategy.Type),
-		})
+	ignore := func(property string, set bool, managedBy string) {
+		ignoreChanges = append(ignoreChanges, property)
+		if set {
+			warnings = append(warnings, fmt.Sprintf("%s is managed by %s; the value in the configuration is only used when the service is created", property, managedBy))
+		}
 	}
 
-	var placementConstraints ecs.ServicePlacementConstraintArray
-	for _, placementConstraint := range config.PlacementConstraints {
-		placementConstraints = append(placementConstraints, &ecs.ServicePlacementConstraintArgs{
-			Expression: pulumi.StringPtrFromPtr(placementConstraint.Expression),
-			Type:       pulumi.String(placementConstraint.Type),
-		})
+	switch controller {
+	case "EXTERNAL":
+		ignore("desiredCount", args.DesiredCount != nil, "the task sets of the EXTERNAL deployment controller")
+		ignore("taskDefinition", args.TaskDefinition != nil, "the task sets of the EXTERNAL deployment controller")
+		ignore("loadBalancers", len(args.LoadBalancers) > 0, "the task sets of the EXTERNAL deployment controller")
+		return ignoreChanges, warnings
+	case "CODE_DEPLOY":
+		ignore("taskDefinition", args.TaskDefinition != nil, "CodeDeploy")
+		ignore("loadBalancers", len(args.LoadBalancers) > 0, "CodeDeploy")
+	}
+	if args.AutoScaling != nil {
+		ignore("desiredCount", args.DesiredCount != nil, "auto scaling")
 	}
 
-	var serviceRegistries *ecs.ServiceServiceRegistriesArgs
-	if config.ServiceRegistry != nil {
-		serviceRegistries = &ecs.ServiceServiceRegistriesArgs{
-			ContainerName: pulumi.StringPtrFromPtr(config.ServiceRegistry.ContainerName),
-			ContainerPort: pulumi.IntPtrFromPtr(config.ServiceRegistry.ContainerPort),
-			Port:          pulumi.IntPtrFromPtr(config.ServiceRegistry.Port),
-			RegistryArn:   pulumi.String(config.ServiceRegistry.RegistryArn),
-		}
-	}
-
-	serviceConnectConfiguration := createServiceConnectConfiguration(config)
-
-	var serviceVolumeConfiguration *ecs.ServiceVolumeConfigurationArgs
-	if config.ServiceVolumeConfiguration != nil {
-		serviceVolumeConfiguration = &ecs.ServiceVolumeConfigurationArgs{
-			ManagedEbsVolume: &ecs.ServiceVolumeConfigurationManagedEbsVolumeArgs{
-				Encrypted:      pulumi.BoolPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.Encrypted),
-				FileSystemType: pulumi.StringPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.FileSystemType),
-				Iops:           pulumi.IntPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.Iops),
-				KmsKeyId:       pulumi.StringPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.KmsKeyID),
-				RoleArn:        pulumi.String(config.ServiceVolumeConfiguration.ManagedEBSVolume.RoleArn),
-				SizeInGb:       pulumi.IntPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.SizeInGB),
-				SnapshotId:     pulumi.StringPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.SnapshotID),
-				Throughput:     pulumi.StringPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.Throughput),
-				VolumeType:     pulumi.StringPtrFromPtr(config.ServiceVolumeConfiguration.ManagedEBSVolume.VolumeType),
-			},
-			Name: pulumi.String(config.ServiceVolumeConfiguration.Name),
-		}
-	}
-
-	service, err := ecs.NewService(ctx, "service", &ecs.ServiceArgs{
-		Alarms:                          alarms,
-		CapacityProviderStrategies:      capacityProviderStrategies,
-		Cluster:                         pulumi.String(config.ClusterArn),
-		DeploymentCircuitBreaker:        deploymentCircuitBreaker,
-		DeploymentController:            deploymentController,
-		DeploymentMaximumPercent:        pulumi.IntPtrFromPtr(config.DeploymentMaximumPercent),
-		DeploymentMinimumHealthyPercent: pulumi.IntPtrFromPtr(config.DeploymentMinimumHealthyPercent),
-		DesiredCount:                    pulumi.IntPtrFromPtr(config.DesiredCount),
-		EnableEcsManagedTags:            pulumi.BoolPtrFromPtr(config.EnableEcsManagedTags),
-		EnableExecuteCommand:            pulumi.BoolPtrFromPtr(config.EnableExecuteCommand),
-		ForceNewDeployment:              pulumi.BoolPtrFromPtr(config.ForceNewDeployment),
-		HealthCheckGracePeriodSeconds:   pulumi.IntPtrFromPtr(config.HealthCheckGracePeriodSeconds),
-		IamRole:                         pulumi.StringPtrFromPtr(config.IamRole),
-		LaunchType:                      pulumi.StringPtrFromPtr(config.LaunchType),
-		LoadBalancers:                   loadBalancers,
-		Name:                            pulumi.String(config.Name),
-		NetworkConfiguration:            networkConfiguration,
-		OrderedPlacementStrategies:      orderedPlacementStrategies,
-		PlacementConstraints:            placementConstraints,
-		PlatformVersion:                 pulumi.StringPtrFromPtr(config.PlatformVersion),
-		PropagateTags:                   pulumi.StringPtrFromPtr(config.PropagateTags),
-		SchedulingStrategy:              pulumi.StringPtrFromPtr(config.SchedulingStrategy),
-		ServiceConnectConfiguration:     serviceConnectConfiguration,
-		ServiceRegistries:               serviceRegistries,
-		Tags:                            pulumi.ToStringMap(config.Tags),
-		TaskDefinition:                  pulumi.StringPtrFromPtr(config.TaskDefinition),
-		Triggers:                        pulumi.ToStringMap(config.Triggers),
-		VolumeConfiguration:             serviceVolumeConfiguration,
-		WaitForSteadyState:              pulumi.BoolPtrFromPtr(config.WaitForSteadyState),
-	}, pulumi.Parent(component))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new service: %v", err)
+	return ignoreChanges, warnings
+}
+
+// plainString returns the value of a string input if it is known before the deployment, such as pulumi.String or
+// pulumi.StringPtr, and reports whether it is.
+func plainString(input interface{}) (string, bool) {
+	v := reflect.ValueOf(input)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return "", false
 	}
+	return v.String(), true
+}
 
-	return &serviceOutput{
-		id: service.ID(),
-	}, nil
+// clusterNameOf returns the name of the cluster of a service. The cluster may be given by name or ARN; the last
+// segment is the name in both cases.
+func clusterNameOf(cluster pulumi.StringOutput) pulumi.StringOutput {
+	return cluster.ApplyT(func(cluster string) string {
+		return cluster[strings.LastIndex(cluster, "/")+1:]
+	}).(pulumi.StringOutput)
 }
 
 // NewTaskDefinition creates a new AWS ECS task definition.
-func NewTaskDefinition(ctx *pulumi.Context, config TaskDefinitionConfig, opts ...pulumi.ResourceOption) (*taskDefinitionOutput, error) {
-	component := &pulumi.ResourceState{}
-	err := ctx.RegisterComponentResource("aws:ecs:TaskDefinition", config.Name, component, opts...)
+func NewTaskDefinition(ctx *pulumi.Context, config TaskDefinitionConfig, opts ...pulumi.ResourceOption) (*TaskDefinition, error) {
+	config, err := interpolate(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to register component resource: %v", err)
+		return nil, err
 	}
 
-	containerDefinitions, err := json.Marshal(config.ContainerDefinitions)
+	err = config.Validate()
 	if err != nil {
-		return nil, fmt.Errorf("could not marshal container definitions json: %v", err)
+		return nil, err
 	}
 
-	var ephemeralStorage *ecs.TaskDefinitionEphemeralStorageArgs
-	if config.EphemeralStorage != nil {
-		ephemeralStorage = &ecs.TaskDefinitionEphemeralStorageArgs{
-			SizeInGib: pulumi.Int(config.EphemeralStorage.SizeInGB),
-		}
+	args, err := config.ToArgs()
+	if err != nil {
+		return nil, err
 	}
 
-	var inferenceAccelerators ecs.TaskDefinitionInferenceAcceleratorArray
-	for _, inferenceAccelerator := range config.InferenceAccelerators {
-		inferenceAccelerators = append(inferenceAccelerators, &ecs.TaskDefinitionInferenceAcceleratorArgs{
-			DeviceName: pulumi.String(inferenceAccelerator.DeviceName),
-			DeviceType: pulumi.String(inferenceAccelerator.DeviceType),
-		})
+	return NewTaskDefinitionFromArgs(ctx, config.Name, args, opts...)
+}
+
+// NewTaskDefinitionFromArgs creates a new AWS ECS task definition from inputs.
+func NewTaskDefinitionFromArgs(ctx *pulumi.Context, name string, args *TaskDefinitionArgs, opts ...pulumi.ResourceOption) (*TaskDefinition, error) {
+	if args == nil {
+		return nil, fmt.Errorf("task definition %s requires container definitions", name)
 	}
 
-	var placementConstraints ecs.TaskDefinitionPlacementConstraintArray
-	for _, placementConstraint := range config.PlacementConstraints {
-		placementConstraints = append(placementConstraints, &ecs.TaskDefinitionPlacementConstraintArgs{
-			Expression: pulumi.StringPtrFromPtr(placementConstraint.Expression),
-			Type:       pulumi.String(placementConstraint.Type),
-		})
+	err := escapeDocuments(ctx, args)
+	if err != nil {
+		return nil, err
 	}
 
-	var proxyConfiguration *ecs.TaskDefinitionProxyConfigurationArgs
-	if config.ProxyConfiguration != nil {
-		proxyConfiguration = &ecs.TaskDefinitionProxyConfigurationArgs{
-			ContainerName: pulumi.String(config.ProxyConfiguration.ContainerName),
-			Properties:    pulumi.ToStringMap(config.ProxyConfiguration.Properties),
-			Type:          pulumi.StringPtrFromPtr(config.ProxyConfiguration.Type),
-		}
+	err = resolveReferences(ctx, args)
+	if err != nil {
+		return nil, err
 	}
 
-	var runtimePlatform *ecs.TaskDefinitionRuntimePlatformArgs
-	if config.RuntimePlatform != nil {
-		runtimePlatform = &ecs.TaskDefinitionRuntimePlatformArgs{
-			CpuArchitecture:       pulumi.StringPtrFromPtr(config.RuntimePlatform.CPUArchitecture),
-			OperatingSystemFamily: pulumi.StringPtrFromPtr(config.RuntimePlatform.OperatingSystemFamily),
-		}
+	opts, childAlias := legacyAliases(TaskDefinitionType, opts)
+	component := &TaskDefinition{}
+	err = ctx.RegisterComponentResource(TaskDefinitionType, name, component, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource: %v", err)
 	}
 
-	var volumes ecs.TaskDefinitionVolumeArray
-	for _, volume := range config.Volumes {
-		var dockerVolumeConfiguration *ecs.TaskDefinitionVolumeDockerVolumeConfigurationArgs
-		if volume.DockerVolumeConfiguration != nil {
-			dockerVolumeConfiguration = &ecs.TaskDefinitionVolumeDockerVolumeConfigurationArgs{
-				Autoprovision: pulumi.BoolPtrFromPtr(volume.DockerVolumeConfiguration.Autoprovision),
-				Driver:        pulumi.StringPtrFromPtr(volume.DockerVolumeConfiguration.Driver),
-				DriverOpts:    pulumi.ToStringMap(volume.DockerVolumeConfiguration.DriverOpts),
-				Labels:        pulumi.ToStringMap(volume.DockerVolumeConfiguration.Labels),
-				Scope:         pulumi.StringPtrFromPtr(volume.DockerVolumeConfiguration.Scope),
-			}
-		}
+	err = logWarnings(ctx, component, containerDefinitionWarnings(args.ContainerDefinitions))
+	if err != nil {
+		return nil, err
+	}
 
-		var efsVolumeConfiguration *ecs.TaskDefinitionVolumeEfsVolumeConfigurationArgs
-		if volume.EfsVolumeConfiguration != nil {
-			var authorizationConfig *ecs.TaskDefinitionVolumeEfsVolumeConfigurationAuthorizationConfigArgs
-			if volume.EfsVolumeConfiguration.AuthorizationConfig != nil {
-				authorizationConfig = &ecs.TaskDefinitionVolumeEfsVolumeConfigurationAuthorizationConfigArgs{
-					AccessPointId: pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.AuthorizationConfig.AccessPointID),
-					Iam:           pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.AuthorizationConfig.Iam),
-				}
-			}
+	family := args.Name
+	if family == nil {
+		family = pulumi.String(name)
+	}
 
-			efsVolumeConfiguration = &ecs.TaskDefinitionVolumeEfsVolumeConfigurationArgs{
-				AuthorizationConfig:   authorizationConfig,
-				FileSystemId:          pulumi.String(volume.EfsVolumeConfiguration.FileSystemID),
-				RootDirectory:         pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.RootDirectory),
-				TransitEncryption:     pulumi.StringPtrFromPtr(volume.EfsVolumeConfiguration.TransitEncryption),
-				TransitEncryptionPort: pulumi.IntPtrFromPtr(volume.EfsVolumeConfiguration.TransitEncryptionPort),
-			}
-		}
+	extensions, err := component.newExtensions(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
 
-		var fsxVolumeConfiguration *ecs.TaskDefinitionVolumeFsxWindowsFileServerVolumeConfigurationArgs
-		if volume.FsxWindowsFileServerVolumeConfiguration != nil {
-			fsxVolumeConfiguration = &ecs.TaskDefinitionVolumeFsxWindowsFileServerVolumeConfigurationArgs{
-				AuthorizationConfig: &ecs.TaskDefinitionVolumeFsxWindowsFileServerVolumeConfigurationAuthorizationConfigArgs{
-					CredentialsParameter: pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.AuthorizationConfig.CredentialsParameter),
-					Domain:               pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.AuthorizationConfig.Domain),
-				},
-				FileSystemId:  pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.FileSystemID),
-				RootDirectory: pulumi.String(volume.FsxWindowsFileServerVolumeConfiguration.RootDirectory),
+	roles, err := component.newRoles(ctx, name, args, extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	taskDefinition, err := ecs.NewTaskDefinition(ctx, name, &ecs.TaskDefinitionArgs{
+		ContainerDefinitions:    extensions.containerDefinitions,
+		Cpu:                     args.CPU,
+		EphemeralStorage:        args.EphemeralStorage,
+		ExecutionRoleArn:        roles.executionRoleArn,
+		Family:                  family,
+		IpcMode:                 args.IpcMode,
+		InferenceAccelerators:   args.InferenceAccelerators,
+		Memory:                  args.Memory,
+		NetworkMode:             args.NetworkMode,
+		PidMode:                 args.PidMode,
+		PlacementConstraints:    args.PlacementConstraints,
+		ProxyConfiguration:      args.ProxyConfiguration,
+		RequiresCompatibilities: args.RequiresCompatibilities,
+		RuntimePlatform:         args.RuntimePlatform,
+		SkipDestroy:             args.SkipDestroy,
+		Tags:                    args.Tags,
+		TaskRoleArn:             roles.taskRoleArn,
+		TrackLatest:             args.TrackLatest,
+		Volumes:                 args.Volumes,
+	}, pulumi.Parent(component), childAlias("taskDefinition"), pulumi.DependsOn(roles.policies))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new task definition: %v", err)
+	}
+
+	component.Arn = taskDefinition.Arn
+	component.Family = taskDefinition.Family
+	component.Revision = taskDefinition.Revision
+
+	err = ctx.RegisterResourceOutputs(component, component.outputs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+	}
+
+	return component, nil
+}
+
+// escapeDocuments resolves the references in the container definitions and role policies of args that are known
+// before the deployment. Values in these JSON documents are escaped, so that they cannot break out of their strings.
+func escapeDocuments(ctx *pulumi.Context, args *TaskDefinitionArgs) error {
+	documents := []*pulumi.StringInput{&args.ContainerDefinitions}
+	for _, role := range []*TaskDefinitionRoleArgs{args.ExecutionRole, args.TaskRole} {
+		if role != nil {
+			documents = append(documents, &role.Policy)
+		}
+	}
+	for _, document := range documents {
+		if plain, ok := (*document).(pulumi.String); ok {
+			resolved, err := resolveString(ctx, string(plain), jsonEscape)
+			if err != nil {
+				return err
 			}
+			*document = resolved
 		}
+	}
+	return nil
+}
 
-		volumes = append(volumes, &ecs.TaskDefinitionVolumeArgs{
-			DockerVolumeConfiguration:               dockerVolumeConfiguration,
-			EfsVolumeConfiguration:                  efsVolumeConfiguration,
-			FsxWindowsFileServerVolumeConfiguration: fsxVolumeConfiguration,
-			HostPath:                                pulumi.StringPtrFromPtr(volume.HostPath),
-			Name:                                    pulumi.String(volume.Name),
-		})
+// containerDefinitionWarnings returns the warnings for the container definitions. The container definitions can only
+// be checked if they are known before the deployment.
+func containerDefinitionWarnings(containerDefinitions pulumi.StringInput) []string {
+	definitions, ok := plainString(containerDefinitions)
+	if !ok {
+		return nil
+	}
+	var containers []ContainerDefinition
+	if json.Unmarshal([]byte(definitions), &containers) != nil {
+		return nil
 	}
+	return containerWarnings(containers)
+}
 
-	taskDefinition, err := ecs.NewTaskDefinition(ctx, "taskDefinition", &ecs.TaskDefinitionArgs{
-		ContainerDefinitions:    pulumi.String(containerDefinitions),
-		Cpu:                     pulumi.StringPtrFromPtr(config.CPU),
-		EphemeralStorage:        ephemeralStorage,
-		ExecutionRoleArn:        pulumi.StringPtrFromPtr(config.ExecutionRoleArn),
-		Family:                  pulumi.String(config.Name),
-		IpcMode:                 pulumi.StringPtrFromPtr(config.IpcMode),
-		InferenceAccelerators:   inferenceAccelerators,
-		Memory:                  pulumi.StringPtrFromPtr(config.Memory),
-		NetworkMode:             pulumi.StringPtrFromPtr(config.NetworkMode),
-		PidMode:                 pulumi.StringPtrFromPtr(config.PidMode),
-		PlacementConstraints:    placementConstraints,
-		ProxyConfiguration:      proxyConfiguration,
-		RequiresCompatibilities: pulumi.ToStringArray(config.RequiresCompatibilities),
-		RuntimePlatform:         runtimePlatform,
-		SkipDestroy:             pulumi.BoolPtrFromPtr(config.SkipDestroy),
-		Tags:                    pulumi.ToStringMap(config.Tags),
-		TaskRoleArn:             pulumi.StringPtrFromPtr(config.TaskRoleArn),
-		TrackLatest:             pulumi.BoolPtrFromPtr(config.TrackLatest),
-		Volumes:                 volumes,
-	}, pulumi.Parent(component))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new task definition: %v", err)
+// taskExtensions are the secrets and log groups created for a task definition, and its container definitions with
+// them injected.
+type taskExtensions struct {
+	containerDefinitions pulumi.StringInput
+	logGroups            *taskLogGroups
+	secrets              *taskSecrets
+}
+
+// newExtensions creates the secrets and log groups of the task definition and injects them into its container
+// definitions.
+func (t *TaskDefinition) newExtensions(ctx *pulumi.Context, name string, args *TaskDefinitionArgs) (*taskExtensions, error) {
+	extensions := &taskExtensions{containerDefinitions: args.ContainerDefinitions}
+	var err error
+	if len(args.Secrets) > 0 {
+		extensions.secrets, err = newTaskSecrets(ctx, name, args.Secrets, args.Tags, t)
+		if err != nil {
+			return nil, err
+		}
+		extensions.containerDefinitions = extensions.secrets.inject(extensions.containerDefinitions)
 	}
 
-	return &taskDefinitionOutput{
-		arn: taskDefinition.Arn,
-	}, nil
+	if args.Logging != nil && len(args.Logging.Containers) > 0 {
+		extensions.logGroups, err = newTaskLogGroups(ctx, name, args.Logging, args.Tags, t)
+		if err != nil {
+			return nil, err
+		}
+		t.LogGroups = extensions.logGroups.groups
+		extensions.containerDefinitions = extensions.logGroups.inject(extensions.containerDefinitions)
+	}
+	return extensions, nil
 }
 
-// NewTaskSets creates new AWS ECS task sets.
-func NewTaskSets(ctx *pulumi.Context, taskSets []TaskSetConfig, opts ...pulumi.ResourceOption) ([]*taskSetOutput, error) {
-	component := &pulumi.ResourceState{}
-	var taskSetOutputs []*taskSetOutput
+// taskRoles are the ARNs of the roles of a task definition and the policies that must be attached before it is used.
+type taskRoles struct {
+	executionRoleArn pulumi.StringPtrInput
+	policies         []pulumi.Resource
+	taskRoleArn      pulumi.StringPtrInput
+}
 
-	for i, taskSet := range taskSets {
-		err := ctx.RegisterComponentResource("aws:ecs:TaskSet", taskSet.Name, component, opts...)
+// newRoles creates the execution and task roles of the task definition, or passes on the ARNs of existing ones.
+func (t *TaskDefinition) newRoles(ctx *pulumi.Context, name string, args *TaskDefinitionArgs, extensions *taskExtensions) (*taskRoles, error) {
+	roles := &taskRoles{executionRoleArn: args.ExecutionRoleArn, taskRoleArn: args.TaskRoleArn}
+	if args.ExecutionRole != nil {
+		if roles.executionRoleArn != nil {
+			return nil, fmt.Errorf("task definition %s must not set both ExecutionRole and ExecutionRoleArn", name)
+		}
+		var policies []pulumi.Resource
+		var err error
+		t.ExecutionRole, policies, err = newTaskDefinitionRole(ctx, name+"-execution", args.ExecutionRole, args.Tags, t)
 		if err != nil {
-			return nil, fmt.Errorf("failed to register component resource: %v", err)
+			return nil, err
 		}
+		roles.executionRoleArn = t.ExecutionRole.Arn
+		roles.policies = append(roles.policies, policies...)
 
-		var capacityProviderStrategies ecs.TaskSetCapacityProviderStrategyArray
-		for _, capacityProviderStrategy := range taskSet.CapacityProviderStrategies {
-			capacityProviderStrategies = append(capacityProviderStrategies, &ecs.TaskSetCapacityProviderStrategyArgs{
-				Base:             pulumi.IntPtrFromPtr(capacityProviderStrategy.Base),
-				CapacityProvider: pulumi.String(capacityProviderStrategy.CapacityProvider),
-				Weight:           pulumi.Int(capacityProviderStrategy.Weight),
-			})
+		policies, err = t.newExtensionPolicies(ctx, name, extensions)
+		if err != nil {
+			return nil, err
+		}
+		roles.policies = append(roles.policies, policies...)
+	}
+	if args.TaskRole != nil {
+		if roles.taskRoleArn != nil {
+			return nil, fmt.Errorf("task definition %s must not set both TaskRole and TaskRoleArn", name)
 		}
+		var policies []pulumi.Resource
+		var err error
+		t.TaskRole, policies, err = newTaskDefinitionRole(ctx, name+"-task", args.TaskRole, args.Tags, t)
+		if err != nil {
+			return nil, err
+		}
+		roles.taskRoleArn = t.TaskRole.Arn
+		roles.policies = append(roles.policies, policies...)
+	}
+	return roles, nil
+}
 
-		var loadBalancers ecs.TaskSetLoadBalancerArray
-		for _, loadBalancer := range taskSet.LoadBalancers {
-			loadBalancers = append(loadBalancers, &ecs.TaskSetLoadBalancerArgs{
-				ContainerName:    pulumi.String(loadBalancer.ContainerName),
-				ContainerPort:    pulumi.IntPtrFromPtr(loadBalancer.ContainerPort),
-				LoadBalancerName: pulumi.StringPtrFromPtr(loadBalancer.LoadBalancerName),
-				TargetGroupArn:   pulumi.StringPtrFromPtr(loadBalancer.TargetGroupArn),
-			})
+// newExtensionPolicies attaches the policies to the created execution role that allow it to read the secrets and
+// write to the log groups of the task definition.
+func (t *TaskDefinition) newExtensionPolicies(ctx *pulumi.Context, name string, extensions *taskExtensions) ([]pulumi.Resource, error) {
+	var policies []pulumi.Resource
+	if extensions.secrets != nil {
+		policy, err := iam.NewRolePolicy(ctx, name+"-execution-secrets", &iam.RolePolicyArgs{
+			Policy: extensions.secrets.policy(),
+			Role:   t.ExecutionRole.Name,
+		}, pulumi.Parent(t))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new role policy: %v", err)
+		}
+		policies = append(policies, policy)
+	}
+	if extensions.logGroups != nil {
+		policy, err := extensions.logGroups.newPolicy(ctx, name+"-execution-logs", t.ExecutionRole, t)
+		if err != nil {
+			return nil, err
 		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
 
-		var networkConfiguration *ecs.TaskSetNetworkConfigurationArgs
-		if taskSet.NetworkConfiguration != nil {
-			networkConfiguration = &ecs.TaskSetNetworkConfigurationArgs{
-				AssignPublicIp: pulumi.BoolPtrFromPtr(taskSet.NetworkConfiguration.AssignPublicIP),
-				SecurityGroups: pulumi.ToStringArray(taskSet.NetworkConfiguration.SecurityGroups),
-				Subnets:        pulumi.ToStringArray(taskSet.NetworkConfiguration.Subnets),
-			}
+// outputs returns the outputs of the task definition component, including the ARNs of the roles and log groups it
+// created.
+func (t *TaskDefinition) outputs() pulumi.Map {
+	outputs := pulumi.Map{
+		"arn":      t.Arn,
+		"family":   t.Family,
+		"revision": t.Revision,
+	}
+	if t.ExecutionRole != nil {
+		outputs["executionRoleArn"] = t.ExecutionRole.Arn
+	}
+	if t.TaskRole != nil {
+		outputs["taskRoleArn"] = t.TaskRole.Arn
+	}
+	if len(t.LogGroups) > 0 {
+		logGroupArns := pulumi.StringMap{}
+		for groupName, logGroup := range t.LogGroups {
+			logGroupArns[groupName] = logGroup.Arn
 		}
+		outputs["logGroupArns"] = logGroupArns
+	}
+	return outputs
+}
 
-		var scale *ecs.TaskSetScaleArgs
-		if taskSet.Scale != nil {
-			scale = &ecs.TaskSetScaleArgs{
-				Unit:  pulumi.StringPtrFromPtr(taskSet.Scale.Unit),
-				Value: pulumi.Float64PtrFromPtr(taskSet.Scale.Value),
-			}
+// NewTaskSets creates new AWS ECS task sets.
+func NewTaskSets(ctx *pulumi.Context, taskSets []TaskSetConfig, opts ...pulumi.ResourceOption) ([]*TaskSet, error) {
+	taskSets, err := interpolate(ctx, taskSets)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &validator{}
+	for i, taskSet := range taskSets {
+		taskSet.validate(v, fmt.Sprintf("taskSets[%d]", i))
+	}
+	err = v.err()
+	if err != nil {
+		return nil, err
+	}
+
+	var args []*TaskSetArgs
+	for _, taskSet := range taskSets {
+		args = append(args, taskSet.ToArgs())
+	}
+
+	return NewTaskSetsFromArgs(ctx, args, opts...)
+}
+
+// NewTaskSetsFromArgs creates new AWS ECS task sets from inputs.
+// Each task set is registered as its own component resource, named after the task set.
+func NewTaskSetsFromArgs(ctx *pulumi.Context, taskSets []*TaskSetArgs, opts ...pulumi.ResourceOption) ([]*TaskSet, error) {
+	var components []*TaskSet
+	opts, childAlias := legacyAliases(TaskSetType, opts)
+	for i, taskSet := range taskSets {
+		err := resolveReferences(ctx, taskSet)
+		if err != nil {
+			return nil, err
 		}
 
-		var serviceRegistries *ecs.TaskSetServiceRegistriesArgs
-		if taskSet.ServiceRegistries != nil {
-			serviceRegistries = &ecs.TaskSetServiceRegistriesArgs{
-				ContainerName: pulumi.StringPtrFromPtr(taskSet.ServiceRegistries.ContainerName),
-				ContainerPort: pulumi.IntPtrFromPtr(taskSet.ServiceRegistries.ContainerPort),
-				Port:          pulumi.IntPtrFromPtr(taskSet.ServiceRegistries.Port),
-				RegistryArn:   pulumi.String(taskSet.ServiceRegistries.RegistryArn),
-			}
+		component := &TaskSet{}
+		err = ctx.RegisterComponentResource(TaskSetType, taskSet.Name, component, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register component resource: %v", err)
 		}
 
-		output, err := ecs.NewTaskSet(ctx, fmt.Sprintf("taskSet-%d", i+1), &ecs.TaskSetArgs{
-			CapacityProviderStrategies: capacityProviderStrategies,
-			Cluster:                    pulumi.String(taskSet.Cluster),
-			ExternalId:                 pulumi.StringPtrFromPtr(taskSet.ExternalID),
-			ForceDelete:                pulumi.BoolPtrFromPtr(taskSet.ForceDelete),
-			LaunchType:                 pulumi.StringPtrFromPtr(taskSet.LaunchType),
-			LoadBalancers:              loadBalancers,
-			NetworkConfiguration:       networkConfiguration,
-			PlatformVersion:            pulumi.StringPtrFromPtr(taskSet.PlatformVersion),
-			Scale:                      scale,
-			Service:                    pulumi.String(taskSet.Service),
-			ServiceRegistries:          serviceRegistries,
-			Tags:                       pulumi.ToStringMap(taskSet.Tags),
-			TaskDefinition:             pulumi.String(taskSet.TaskDefinition),
-			WaitUntilStable:            pulumi.BoolPtrFromPtr(taskSet.WaitUntilStable),
-			WaitUntilStableTimeout:     pulumi.StringPtrFromPtr(taskSet.WaitUntilStableTimeout),
-		}, pulumi.Parent(component))
+		output, err := ecs.NewTaskSet(ctx, taskSet.Name, &ecs.TaskSetArgs{
+			CapacityProviderStrategies: taskSet.CapacityProviderStrategies,
+			Cluster:                    taskSet.Cluster,
+			ExternalId:                 taskSet.ExternalID,
+			ForceDelete:                taskSet.ForceDelete,
+			LaunchType:                 taskSet.LaunchType,
+			LoadBalancers:              taskSet.LoadBalancers,
+			NetworkConfiguration:       taskSet.NetworkConfiguration,
+			PlatformVersion:            taskSet.PlatformVersion,
+			Scale:                      taskSet.Scale,
+			Service:                    taskSet.Service,
+			ServiceRegistries:          taskSet.ServiceRegistries,
+			Tags:                       taskSet.Tags,
+			TaskDefinition:             taskSet.TaskDefinition,
+			WaitUntilStable:            taskSet.WaitUntilStable,
+			WaitUntilStableTimeout:     taskSet.WaitUntilStableTimeout,
+		}, pulumi.Parent(component), childAlias(fmt.Sprintf("taskSet-%d", i+1)))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create new task set: %v", err)
 		}
 
-		taskSetOutputs = append(taskSetOutputs, &taskSetOutput{
-			arn: output.Arn,
+		component.Arn = output.Arn
+		component.ID = output.TaskSetId
+		component.StabilityStatus = output.StabilityStatus
+
+		err = ctx.RegisterResourceOutputs(component, pulumi.Map{
+			"arn":             component.Arn,
+			"id":              component.ID,
+			"stabilityStatus": component.StabilityStatus,
 		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// legacyAliases prepends an alias for the legacy type token of a component resource to opts, so that stacks created
+// with the legacy token keep their resources when upgrading. Children inherit the alias of their parent. The returned
+// function gives the alias for the name a child resource had before child names were derived from the name of their
+// component. Components that are part of a FargateService, Ec2CapacityProvider or Rollout never existed under the
+// legacy names, so they get no aliases.
+func legacyAliases(t string, opts []pulumi.ResourceOption) ([]pulumi.ResourceOption, func(name string) pulumi.ResourceOption) {
+	if options, err := pulumi.NewResourceOptions(opts...); err == nil {
+		switch options.Parent.(type) {
+		case *FargateService, *Ec2CapacityProvider, *Rollout:
+			return opts, func(string) pulumi.ResourceOption { return pulumi.Aliases(nil) }
+		}
 	}
 
-	return taskSetOutputs, nil
+	alias := pulumi.Aliases([]pulumi.Alias{{Type: pulumi.String(legacyTypes[t])}})
+	return append([]pulumi.ResourceOption{alias}, opts...), func(name string) pulumi.ResourceOption {
+		return pulumi.Aliases([]pulumi.Alias{{Name: pulumi.String(name)}})
+	}
 }
@@ -1,725 +1,642 @@
 package ecs
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
+	"sync"
 	"testing"
 
-	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws"
-
-	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/autoscaling"
-	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ec2"
 	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
-
-	"github.com/pulumi/pulumi/sdk/v3/go/auto"
-	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
-	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
 
-const stackName = "dev"
-
-func manageResources(ctx context.Context, stack auto.Stack, sugar *zap.SugaredLogger, t *testing.T) {
-	sugar.Infof("Created/Selected stack: %s", stackName)
-
-	workspace := stack.Workspace()
-
-	sugar.Info("Installing the AWS plugin")
-	// for inline source programs, we must manage plugins ourselves
-	err := workspace.InstallPlugin(ctx, "aws", "v4.0.0")
-	assert.NoError(t, err)
-	sugar.Info("Successfully installed AWS plugin!")
-
-	sugar.Info("Setting the stack configuration to use region in AWS")
-	err = stack.SetConfig(ctx, "aws:region", auto.ConfigValue{Value: "us-west-2"})
-	assert.NoError(t, err)
-	sugar.Info("Successfully set config!")
-
-	sugar.Info("Starting refresh")
-	_, err = stack.Refresh(ctx)
-	assert.NoError(t, err)
-	sugar.Info("Refresh succeeded!")
-
-	sugar.Info("Starting update")
-	// wire up our update to stream progress to stdout
-	stdoutStreamerUp := optup.ProgressStreams(os.Stdout)
-
-	// run the equivalent of 'pulumi up'
-	result, err := stack.Up(ctx, stdoutStreamerUp)
-	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	sugar.Info("Update succeeded!")
-
-	sugar.Info("Destroying resources!")
-	// wire up our update to stream progress to stdout
-	stdoutStreamerDestroy := optdestroy.ProgressStreams(os.Stdout)
-
-	// run the equivalent of 'pulumi destroy'
-	_, err = stack.Destroy(ctx, stdoutStreamerDestroy)
-	assert.NoError(t, err)
-	sugar.Info("Destroy succeeded!")
-}
-
 func getAccountSettingsDefaultConfig(sugar *zap.SugaredLogger) ([]AccountSettingDefaultConfig, error) {
-	configData, err := os.ReadFile("examples/AccountSettingsDefault/config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	var tempConfig map[string]interface{}
-	err = json.Unmarshal(configData, &tempConfig)
+	var accountSettingsDefault []AccountSettingDefaultConfig
+	err := LoadConfigFile("examples/AccountSettingsDefault/config.json", "accountSettingsDefault", &accountSettingsDefault)
 	if err != nil {
 		sugar.Error(err)
 		return nil, err
 	}
 
-	var accountSettingsDefault []AccountSettingDefaultConfig
-	for _, accountSettingDefault := range tempConfig["accountSettingsDefault"].([]interface{}) {
-		accountSettingDefaultConfigJSON, err := json.Marshal(accountSettingDefault)
-		if err != nil {
-			sugar.Error(err)
-			return nil, err
-		}
-
-		var accountSettingDefaultConfig AccountSettingDefaultConfig
-		err = json.Unmarshal(accountSettingDefaultConfigJSON, &accountSettingDefaultConfig)
-		if err != nil {
-			sugar.Errorf("Error unmarshaling config file: %v", err)
-			return nil, err
-		}
-
-		accountSettingsDefault = append(accountSettingsDefault, accountSettingDefaultConfig)
-	}
-
 	return accountSettingsDefault, nil
 }
 
-// TestNewAccountSettingsDefault is an integration test that checks the correctness of the creation of default account settings for AWS ECS.
-// It simulates the process of creating default account settings with defined parameters, which can be found in examples/AccountSettingsDefault/config.json, and expected outcomes.
-// The test will pass if the account settings are created successfully.
-// Otherwise, it will fail providing information about what incidentally caused the failure.
-func TestNewAccountSettingsDefault(t *testing.T) {
-	logger, err := zap.NewProduction()
+func getCapacityProvidersConfig(sugar *zap.SugaredLogger) ([]CapacityProviderConfig, error) {
+	var capacityProviders []CapacityProviderConfig
+	err := LoadConfigFile("examples/CapacityProviders/config.json", "capacityProviders", &capacityProviders)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
-		os.Exit(1)
+		sugar.Error(err)
+		return nil, err
 	}
-	defer func() {
-		if err := logger.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
-		}
-	}()
-
-	sugar := logger.Sugar()
-
-	sugar.Info("Reading ECS default account settings configuration from examples/AccountSettingsDefault/config.json")
-	accountSettingsDefaultConfig, err := getAccountSettingsDefaultConfig(sugar)
-	assert.NoError(t, err)
-	sugar.Info("Successfully read configuration!")
 
-	ctx := context.Background()
-	projectName := "test_ecs_account_settings_default"
-
-	stack, err := auto.UpsertStackInlineSource(ctx, stackName, projectName, func(ctx *pulumi.Context) error {
-		_, err = NewAccountSettingsDefault(ctx, accountSettingsDefaultConfig)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-	assert.NoError(t, err)
-
-	// Set config, run 'pulumi up', and afterwards 'pulumi destroy'
-	manageResources(ctx, stack, sugar, t)
+	return capacityProviders, nil
 }
 
-func createCapacityProvidersTestDependencies(ctx *pulumi.Context) (*autoscaling.Group, error) {
-	launchTemplate, err := ec2.NewLaunchTemplate(ctx, "capacityProviderTestDependency", &ec2.LaunchTemplateArgs{
-		NamePrefix:   pulumi.String("capacityProvidersTestDependency"),
-		ImageId:      pulumi.String("ami-0eb9d67c52f5c80e5"),
-		InstanceType: pulumi.String("t2.micro"),
-	})
+func getClusterConfig(sugar *zap.SugaredLogger) (*ClusterConfig, error) {
+	var clusterConfig ClusterConfig
+	err := LoadConfigFile("examples/Cluster/config.json", "cluster", &clusterConfig)
 	if err != nil {
+		sugar.Error(err)
 		return nil, err
 	}
 
-	autoscalingGroup, err := autoscaling.NewGroup(ctx, "capacityProviderTestDependency", &autoscaling.GroupArgs{
-		AvailabilityZones: pulumi.StringArray{
-			pulumi.String("us-west-2a"),
-		},
-		DesiredCapacity: pulumi.Int(0),
-		MaxSize:         pulumi.Int(0),
-		MinSize:         pulumi.Int(0),
-		LaunchTemplate: &autoscaling.GroupLaunchTemplateArgs{
-			Id:      launchTemplate.ID(),
-			Version: pulumi.String("$Latest"),
-		},
-		ProtectFromScaleIn: pulumi.Bool(true),
-	})
-	if err != nil {
-		return nil, err
-	}
-	return autoscalingGroup, nil
+	return &clusterConfig, nil
 }
 
-func getCapacityProvidersConfig(sugar *zap.SugaredLogger) ([]CapacityProviderConfig, error) {
-	configData, err := os.ReadFile("examples/CapacityProviders/config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	var tempConfig map[string]interface{}
-	err = json.Unmarshal(configData, &tempConfig)
+func getClusterCapacityProviderConfig(sugar *zap.SugaredLogger) (*ClusterCapacityProviderConfig, error) {
+	var clusterCapacityProviderConfig ClusterCapacityProviderConfig
+	err := LoadConfigFile("examples/ClusterCapacityProvider/config.json", "clusterCapacityProvider", &clusterCapacityProviderConfig)
 	if err != nil {
 		sugar.Error(err)
 		return nil, err
 	}
 
-	var capacityProviders []CapacityProviderConfig
-	for _, capacityProvider := range tempConfig["capacityProviders"].([]interface{}) {
-		capacityProviderConfigJSON, err := json.Marshal(capacityProvider)
-		if err != nil {
-			sugar.Error(err)
-			return nil, err
-		}
-
-		var capacityProviderConfig CapacityProviderConfig
-		err = json.Unmarshal(capacityProviderConfigJSON, &capacityProviderConfig)
-		if err != nil {
-			sugar.Errorf("Error unmarshaling config file: %v", err)
-			return nil, err
-		}
-
-		capacityProviders = append(capacityProviders, capacityProviderConfig)
-	}
-
-	return capacityProviders, nil
+	return &clusterCapacityProviderConfig, nil
 }
 
-// TestNewCapacityProviders is an integration test that checks the correctness of the AWS ECS capacity providers creation.
-// It simulates the process of creating capacity providers with defined parameters, which can be found in examples/CapacityProviders/config.json, and expected outcomes.
-// The test will pass if the capacity providers are created successfully.
-// Otherwise, it will fail providing information about what incidentally caused the failure.
-func TestNewCapacityProviders(t *testing.T) {
-	logger, err := zap.NewProduction()
+func getServiceConfig(sugar *zap.SugaredLogger) (*ServiceConfig, error) {
+	var serviceConfig ServiceConfig
+	err := LoadConfigFile("examples/Service/config.json", "service", &serviceConfig)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
-		os.Exit(1)
+		sugar.Error(err)
+		return nil, err
 	}
-	defer func() {
-		if err := logger.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
-		}
-	}()
-
-	sugar := logger.Sugar()
-
-	sugar.Info("Reading ECS capacity providers configuration from examples/CapacityProviders/config.json")
-	capacityProvidersConfig, err := getCapacityProvidersConfig(sugar)
-	assert.NoError(t, err)
-	sugar.Info("Successfully read configuration!")
-
-	ctx := context.Background()
-	projectName := "test_ecs_capacity_providers_default"
-
-	stack, err := auto.UpsertStackInlineSource(ctx, stackName, projectName, func(ctx *pulumi.Context) error {
-		sugar.Info("Creating dependencies for test")
-		autoscalingGroup, err := createCapacityProvidersTestDependencies(ctx)
-		if err != nil {
-			return err
-		}
-		sugar.Info("Successfully created dependencies!")
-
-		_, err = autoscalingGroup.Arn.ApplyT(func(arn string) (string, error) {
-			for i := range capacityProvidersConfig {
-				capacityProvidersConfig[i].AutoscalingGroupProvider.AutoscalingGroupArn = arn
-			}
-			err = NewCapacityProviders(ctx, capacityProvidersConfig)
-			if err != nil {
-				return "", err
-			}
-			return arn, nil
-		}).(pulumi.StringOutput), nil
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-	assert.NoError(t, err)
 
-	// Set config, run 'pulumi up', and afterwards 'pulumi destroy'
-	manageResources(ctx, stack, sugar, t)
+	return &serviceConfig, nil
 }
 
-func getClusterConfig(sugar *zap.SugaredLogger) (*ClusterConfig, error) {
-	configData, err := os.ReadFile("examples/Cluster/config.json")
+func getTaskDefinitionConfig(sugar *zap.SugaredLogger) (*TaskDefinitionConfig, error) {
+	var taskDefinitionConfig TaskDefinitionConfig
+	err := LoadConfigFile("examples/TaskDefinition/config.json", "taskDefinition", &taskDefinitionConfig)
 	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
+		sugar.Error(err)
 		return nil, err
 	}
 
-	clusterConfigJSON := make(map[string]*ClusterConfig)
+	return &taskDefinitionConfig, nil
+}
 
-	err = json.Unmarshal(configData, &clusterConfigJSON)
+func getTaskSetsConfig(sugar *zap.SugaredLogger) ([]TaskSetConfig, error) {
+	var taskSets []TaskSetConfig
+	err := LoadConfigFile("examples/TaskSets/config.json", "taskSets", &taskSets)
 	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
-	}
-
-	clusterConfig, ok := clusterConfigJSON["cluster"]
-	if !ok {
-		err = fmt.Errorf("'cluster' key not found in JSON config")
 		sugar.Error(err)
 		return nil, err
 	}
 
-	return clusterConfig, nil
+	return taskSets, nil
 }
 
-// TestNewCluster is an integration test that checks the correctness of an AWS ECS cluster creation.
-// It simulates the process of creating a cluster with defined parameters, which can be found in examples/Cluster/config.json, and expected outcomes.
-// The test will pass if the cluster is created successfully.
-// Otherwise, it will fail providing information about what incidentally caused the failure.
-func TestNewCluster(t *testing.T) {
-	logger, err := zap.NewProduction()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		if err := logger.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
+// TestCreateServiceConnectConfiguration is a unit test that checks the mapping of a multi-port Service Connect configuration.
+// Each service must only receive its own client aliases, and a client alias without a DNS name must leave the DNS name unset.
+func TestCreateServiceConnectConfiguration(t *testing.T) {
+	var serviceConfig ServiceConfig
+	err := json.Unmarshal([]byte(`{
+		"name": "my-ecs-service",
+		"serviceConnectConfiguration": {
+			"enabled": true,
+			"namespace": "my-namespace",
+			"services": [
+				{
+					"portName": "http",
+					"clientAlias": [
+						{"port": 80, "dnsName": "web"},
+						{"port": 8080, "dnsName": "web-alt"}
+					]
+				},
+				{
+					"portName": "grpc",
+					"clientAlias": [
+						{"port": 9090}
+					]
+				}
+			]
 		}
-	}()
-
-	sugar := logger.Sugar()
-
-	sugar.Info("Reading ECS cluster configuration from examples/Cluster/config.json")
-	clusterConfig, err := getClusterConfig(sugar)
+	}`), &serviceConfig)
 	assert.NoError(t, err)
-	sugar.Info("Successfully read configuration!")
-
-	ctx := context.Background()
-	projectName := "test_ecs_cluster"
 
-	stack, err := auto.UpsertStackInlineSource(ctx, stackName, projectName, func(ctx *pulumi.Context) error {
-		_, err = NewCluster(ctx, *clusterConfig)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-	assert.NoError(t, err)
+	serviceConnectConfiguration := createServiceConnectConfiguration(serviceConfig)
+	services, ok := serviceConnectConfiguration.Services.(ecs.ServiceServiceConnectConfigurationServiceArray)
+	assert.True(t, ok)
+	assert.Len(t, services, 2)
+
+	clientAliases := func(i int) ecs.ServiceServiceConnectConfigurationServiceClientAliasArray {
+		service, ok := services[i].(*ecs.ServiceServiceConnectConfigurationServiceArgs)
+		assert.True(t, ok)
+		aliases, ok := service.ClientAlias.(ecs.ServiceServiceConnectConfigurationServiceClientAliasArray)
+		assert.True(t, ok)
+		return aliases
+	}
+
+	httpAliases := clientAliases(0)
+	assert.Len(t, httpAliases, 2)
+	for i, expected := range []struct {
+		port    int
+		dnsName string
+	}{{80, "web"}, {8080, "web-alt"}} {
+		alias := httpAliases[i].(*ecs.ServiceServiceConnectConfigurationServiceClientAliasArgs)
+		assert.Equal(t, pulumi.Int(expected.port), alias.Port)
+		assert.Equal(t, pulumi.StringPtr(expected.dnsName), alias.DnsName)
+	}
+
+	grpcAliases := clientAliases(1)
+	assert.Len(t, grpcAliases, 1)
+	grpcAlias := grpcAliases[0].(*ecs.ServiceServiceConnectConfigurationServiceClientAliasArgs)
+	assert.Equal(t, pulumi.Int(9090), grpcAlias.Port)
+	assert.Nil(t, grpcAlias.DnsName)
+}
 
-	// Set config, run 'pulumi up', and afterwards 'pulumi destroy'
-	manageResources(ctx, stack, sugar, t)
+// registeredResource holds what a test program passed when registering a resource.
+type registeredResource struct {
+	Parent        string
+	Inputs        resource.PropertyMap
+	IgnoreChanges []string
+	Aliases       []*pulumirpc.Alias_Spec
 }
 
-func createClusterCapacityProviderTestDependencies(ctx *pulumi.Context) error {
-	_, err := ecs.NewCluster(ctx, "clusterCapacityProviderTestDependency", &ecs.ClusterArgs{
-		Name: pulumi.String("my-cluster"),
-	})
-	if err != nil {
-		return err
-	}
-	return nil
+// mocks implements pulumi.MockResourceMonitor. It records every registered resource, keyed by type token and name,
+// and echoes the inputs of a resource back as its outputs, together with an ARN and, if the inputs have none, a name.
+type mocks struct {
+	mu        sync.Mutex
+	resources map[string]registeredResource
 }
 
-func getClusterCapacityProviderConfig(sugar *zap.SugaredLogger) (*ClusterCapacityProviderConfig, error) {
-	configData, err := os.ReadFile("examples/ClusterCapacityProvider/config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
+func newMocks() *mocks {
+	return &mocks{resources: map[string]registeredResource{}}
+}
 
-	clusterCapacityProviderConfigJSON := make(map[string]*ClusterCapacityProviderConfig)
+func (m *mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	err = json.Unmarshal(configData, &clusterCapacityProviderConfigJSON)
-	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
+	var aliases []*pulumirpc.Alias_Spec
+	for _, alias := range args.RegisterRPC.GetAliases() {
+		aliases = append(aliases, alias.GetSpec())
 	}
-
-	clusterCapacityProviderConfig, ok := clusterCapacityProviderConfigJSON["clusterCapacityProvider"]
-	if !ok {
-		err = fmt.Errorf("'clusterCapacityProvider' key not found in JSON config")
-		sugar.Error(err)
-		return nil, err
+	m.resources[args.TypeToken+"::"+args.Name] = registeredResource{
+		Parent:        args.RegisterRPC.GetParent(),
+		Inputs:        args.Inputs,
+		IgnoreChanges: args.RegisterRPC.GetIgnoreChanges(),
+		Aliases:       aliases,
 	}
 
-	return clusterCapacityProviderConfig, nil
+	outputs := args.Inputs.Copy()
+	if args.Custom {
+		outputs["arn"] = resource.NewStringProperty(fmt.Sprintf("arn:aws:mock:us-west-2:123456789012:%s", args.Name))
+		if _, ok := outputs["name"]; !ok {
+			outputs["name"] = resource.NewStringProperty(args.Name)
+		}
+	}
+	return args.Name + "_id", outputs, nil
 }
 
-// TestNewClusterCapacityProvider is an integration test that checks the correctness of an AWS ECS cluster capacity provider creation.
-// It simulates the process of creating a cluster capacity provider with defined parameters, which can be found in examples/ClusterCapacityProvider/config.json, and expected outcomes.
-// The test will pass if the cluster capacity provider is created successfully.
-// Otherwise, it will fail providing information about what incidentally caused the failure.
-func TestNewClusterCapacityProvider(t *testing.T) {
-	logger, err := zap.NewProduction()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
-		os.Exit(1)
+func (m *mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	if args.Token == "aws:index/getRegion:getRegion" {
+		return resource.NewPropertyMapFromMap(map[string]interface{}{
+			"name": "us-west-2",
+		}), nil
 	}
-	defer func() {
-		if err := logger.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
-		}
-	}()
+	if args.Token == "aws:index/getPartition:getPartition" {
+		return resource.NewPropertyMapFromMap(map[string]interface{}{
+			"partition": "aws",
+		}), nil
+	}
+	// Lookups by name, such as getSecret and getParameter, return the ARN of the resource they find.
+	outputs := args.Args.Copy()
+	if name, ok := outputs["name"]; ok && name.IsString() {
+		outputs["arn"] = resource.NewStringProperty(fmt.Sprintf("arn:aws:mock:us-west-2:123456789012:%s", name.StringValue()))
+	}
+	return outputs, nil
+}
 
-	sugar := logger.Sugar()
+// resource returns the resource registered with the given type token and name, and fails the test if there is none.
+func (m *mocks) resource(t *testing.T, typeToken, name string) registeredResource {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	sugar.Info("Reading ECS cluster capacity provider configuration from examples/ClusterCapacityProvider/config.json")
-	clusterCapacityProviderConfig, err := getClusterCapacityProviderConfig(sugar)
-	assert.NoError(t, err)
-	sugar.Info("Successfully read configuration!")
+	registered, ok := m.resources[typeToken+"::"+name]
+	if !ok {
+		t.Fatalf("resource %s of type %s was not registered", name, typeToken)
+	}
+	return registered
+}
 
-	ctx := context.Background()
-	projectName := "test_ecs_cluster_capacity_provider"
+// count returns the number of registered resources with the given type token.
+func (m *mocks) count(typeToken string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	stack, err := auto.UpsertStackInlineSource(ctx, stackName, projectName, func(ctx *pulumi.Context) error {
-		sugar.Info("Creating dependencies for test")
-		err = createClusterCapacityProviderTestDependencies(ctx)
-		if err != nil {
-			return err
+	var n int
+	for key := range m.resources {
+		if strings.HasPrefix(key, typeToken+"::") {
+			n++
 		}
-		sugar.Info("Successfully created dependencies!")
+	}
+	return n
+}
 
-		err = NewClusterCapacityProvider(ctx, *clusterCapacityProviderConfig)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+// runWithMocks runs program against m and fails the test if the program returns an error.
+func runWithMocks(t *testing.T, m *mocks, program pulumi.RunFunc) {
+	err := pulumi.RunErr(program, pulumi.WithMocks("project", "stack", m))
 	assert.NoError(t, err)
-
-	// Set config, run 'pulumi up', and afterwards 'pulumi destroy'
-	manageResources(ctx, stack, sugar, t)
 }
 
-func createServiceTestDependencies(ctx *pulumi.Context, accountID string) error {
-	containerDefinitions, err := json.Marshal([]interface{}{
-		map[string]interface{}{
-			"name":  "my-container",
-			"image": "nginx",
-			"portMappings": []map[string]interface{}{
-				{
-					"containerPort": 80,
-					"hostPort":      80,
-				},
-			},
-		},
-	})
-	if err != nil {
-		return err
-	}
-
-	_, err = ecs.NewTaskDefinition(ctx, "serviceTestDependency", &ecs.TaskDefinitionArgs{
-		Family:               pulumi.String("my-task-definition"),
-		ContainerDefinitions: pulumi.String(containerDefinitions),
-		Cpu:                  pulumi.String("256"),
-		Memory:               pulumi.String("512"),
-		NetworkMode:          pulumi.String("awsvpc"),
-		RequiresCompatibilities: pulumi.StringArray{
-			pulumi.String("FARGATE"),
-		},
-		TaskRoleArn: pulumi.String("arn:aws:iam::" + accountID + ":role/aws-service-role/ecs.amazonaws.com/AWSServiceRoleForECS"),
-	})
-	if err != nil {
-		return err
+// taskDefinitionFixture returns the task definition from examples/TaskDefinition/config.json for a test to change,
+// with an execution role that the task definition creates. The returned function creates the task definition against
+// mocks.
+func taskDefinitionFixture(t *testing.T) (*TaskDefinitionConfig, func() *mocks) {
+	t.Setenv("AWS_ACCOUNT_ID", "123456789012")
+	taskDefinitionConfig, err := getTaskDefinitionConfig(zap.NewNop().Sugar())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	taskDefinitionConfig.ExecutionRoleArn = nil
+	taskDefinitionConfig.ExecutionRole = &TaskDefinitionRoleConfig{}
+
+	return taskDefinitionConfig, func() *mocks {
+		m := newMocks()
+		runWithMocks(t, m, func(ctx *pulumi.Context) error {
+			_, err := NewTaskDefinition(ctx, *taskDefinitionConfig)
+			return err
+		})
+		return m
 	}
+}
 
-	_, err = ecs.NewCluster(ctx, "serviceTestDependency", &ecs.ClusterArgs{
-		Name: pulumi.String("my-cluster"),
-	})
-	if err != nil {
-		return err
+// createService creates the service of a fixture against mocks: with NewService if prepare is nil, or else with
+// NewServiceFromArgs, after prepare has changed the inputs.
+type createService func(prepare func(ctx *pulumi.Context, args *ServiceArgs) error) *mocks
+
+// serviceFixture returns the service from examples/Service/config.json for a test to change, and a function that
+// creates it.
+func serviceFixture(t *testing.T) (*ServiceConfig, createService) {
+	t.Setenv("AWS_ACCOUNT_ID", "123456789012")
+	serviceConfig, err := getServiceConfig(zap.NewNop().Sugar())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return serviceConfig, func(prepare func(ctx *pulumi.Context, args *ServiceArgs) error) *mocks {
+		m := newMocks()
+		runWithMocks(t, m, func(ctx *pulumi.Context) error {
+			if prepare == nil {
+				_, err := NewService(ctx, *serviceConfig)
+				return err
+			}
+			args := serviceConfig.ToArgs()
+			err := prepare(ctx, args)
+			if err != nil {
+				return err
+			}
+			_, err = NewServiceFromArgs(ctx, serviceConfig.Name, args)
+			return err
+		})
+		return m
 	}
-	return nil
 }
 
-func getServiceConfig(sugar *zap.SugaredLogger) (*ServiceConfig, error) {
-	configData, err := os.ReadFile("examples/Service/config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
+// componentURN returns the URN the mocks assign to a component resource registered at the root of the stack.
+func componentURN(typeToken, name string) string {
+	return fmt.Sprintf("urn:pulumi:stack::project::%s::%s", typeToken, name)
+}
 
-	serviceConfigJSON := make(map[string]*ServiceConfig)
+// TestNewAccountSettingsDefaultInputs is a unit test that checks the inputs of the default account settings created from examples/AccountSettingsDefault/config.json.
+func TestNewAccountSettingsDefaultInputs(t *testing.T) {
+	accountSettingsDefaultConfig, err := getAccountSettingsDefaultConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
 
-	err = json.Unmarshal(configData, &serviceConfigJSON)
-	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
-	}
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewAccountSettingsDefault(ctx, accountSettingsDefaultConfig)
+		return err
+	})
 
-	serviceConfig, ok := serviceConfigJSON["service"]
-	if !ok {
-		err = fmt.Errorf("'service' key not found in JSON config")
-		sugar.Error(err)
-		return nil, err
+	assert.Equal(t, 2, m.count("aws:ecs/accountSettingDefault:AccountSettingDefault"))
+	for _, name := range []string{"containerInsights", "containerInstanceLongArnFormat"} {
+		accountSettingDefault := m.resource(t, "aws:ecs/accountSettingDefault:AccountSettingDefault", name)
+		assert.Equal(t, componentURN(AccountSettingDefaultType, name), accountSettingDefault.Parent)
+		assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+			"name":  name,
+			"value": "enabled",
+		}), accountSettingDefault.Inputs)
 	}
-
-	return serviceConfig, nil
 }
 
-// TestNewService is an integration test that checks the correctness of an AWS ECS service creation.
-// It simulates the process of creating a service with defined parameters, which can be found in examples/Service/config.json, and expected outcomes.
-// The test will pass if the service is created successfully.
-// Otherwise, it will fail providing information about what incidentally caused the failure.
-func TestNewService(t *testing.T) {
-	logger, err := zap.NewProduction()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		if err := logger.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
-		}
-	}()
-
-	sugar := logger.Sugar()
-
-	sugar.Info("Reading ECS service configuration from examples/Service/config.json")
-	serviceConfig, err := getServiceConfig(sugar)
+// TestNewCapacityProvidersInputs is a unit test that checks the inputs of the capacity providers created from examples/CapacityProviders/config.json.
+func TestNewCapacityProvidersInputs(t *testing.T) {
+	capacityProvidersConfig, err := getCapacityProvidersConfig(zap.NewNop().Sugar())
 	assert.NoError(t, err)
-	sugar.Info("Successfully read configuration!")
 
-	ctx := context.Background()
-	projectName := "test_ecs_service"
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewCapacityProviders(ctx, capacityProvidersConfig)
+		return err
+	})
 
-	stack, err := auto.UpsertStackInlineSource(ctx, stackName, projectName, func(ctx *pulumi.Context) error {
-		current, err := aws.GetCallerIdentity(ctx, nil, nil)
-		assert.NoError(t, err)
-		serviceConfig.ClusterArn = strings.Replace(serviceConfig.ClusterArn, "$ACCOUNT_ID", current.AccountId, 1)
+	capacityProvider := m.resource(t, "aws:ecs/capacityProvider:CapacityProvider", "my-capacity-provider")
+	assert.Equal(t, componentURN(CapacityProviderType, "my-capacity-provider"), capacityProvider.Parent)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"autoScalingGroupProvider": map[string]interface{}{
+			"autoScalingGroupArn": "arn:aws:autoscaling:us-west-2:123456789012:autoScalingGroup:my-auto-scaling-group",
+			"managedDraining":     "ENABLED",
+			"managedScaling": map[string]interface{}{
+				"instanceWarmupPeriod":   300,
+				"maximumScalingStepSize": 10,
+				"minimumScalingStepSize": 1,
+				"status":                 "ENABLED",
+				"targetCapacity":         80,
+			},
+			"managedTerminationProtection": "ENABLED",
+		},
+		"name": "my-capacity-provider",
+		"tags": map[string]interface{}{
+			"environment": "production",
+			"owner":       "myteam",
+		},
+	}), capacityProvider.Inputs)
+}
 
-		err = createServiceTestDependencies(ctx, current.AccountId)
-		if err != nil {
-			return err
-		}
+// TestNewClusterInputs is a unit test that checks the inputs and outputs of the cluster created from examples/Cluster/config.json.
+func TestNewClusterInputs(t *testing.T) {
+	clusterConfig, err := getClusterConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
 
-		_, err = NewService(ctx, *serviceConfig)
+	var arn string
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		cluster, err := NewCluster(ctx, *clusterConfig)
 		if err != nil {
 			return err
 		}
+		cluster.Arn.ApplyT(func(v string) string {
+			arn = v
+			return v
+		})
 		return nil
 	})
-	assert.NoError(t, err)
 
-	// Set config, run 'pulumi up', and afterwards 'pulumi destroy'
-	manageResources(ctx, stack, sugar, t)
+	cluster := m.resource(t, "aws:ecs/cluster:Cluster", "my-cluster")
+	assert.Equal(t, componentURN(ClusterType, "my-cluster"), cluster.Parent)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"configuration": map[string]interface{}{
+			"executeCommandConfiguration": map[string]interface{}{
+				"kmsKeyId": "my-kms-key-id",
+				"logConfiguration": map[string]interface{}{
+					"cloudWatchEncryptionEnabled": true,
+					"cloudWatchLogGroupName":      "/ecs/my-cluster-logs",
+					"s3BucketEncryptionEnabled":   true,
+					"s3BucketName":                "my-s3-bucket",
+					"s3KeyPrefix":                 "logs/",
+				},
+				"logging": "OVERRIDE",
+			},
+		},
+		"name": "my-cluster",
+		"settings": []interface{}{
+			map[string]interface{}{"name": "containerInsights", "value": "enabled"},
+		},
+		"tags": map[string]interface{}{
+			"environment": "production",
+			"owner":       "myteam",
+		},
+	}), cluster.Inputs)
+	assert.Equal(t, "arn:aws:mock:us-west-2:123456789012:my-cluster", arn)
 }
 
-func getTaskDefinitionConfig(sugar *zap.SugaredLogger) (*TaskDefinitionConfig, error) {
-	configData, err := os.ReadFile("examples/TaskDefinition/config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
+// TestNewClusterInstances is a unit test that checks that several clusters can be created in the same stack.
+func TestNewClusterInstances(t *testing.T) {
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		for _, name := range []string{"blue", "green"} {
+			_, err := NewCluster(ctx, ClusterConfig{Name: name})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 
-	taskDefinitionConfigJSON := make(map[string]*TaskDefinitionConfig)
+	assert.Equal(t, 2, m.count("aws:ecs/cluster:Cluster"))
+	assert.Equal(t, componentURN(ClusterType, "green"), m.resource(t, "aws:ecs/cluster:Cluster", "green").Parent)
+}
 
-	err = json.Unmarshal(configData, &taskDefinitionConfigJSON)
-	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
-	}
+// TestComponentAliases is a unit test that checks that components and their children are registered with aliases for the type token and child names they had before, so that upgrading keeps the resources of existing stacks.
+func TestComponentAliases(t *testing.T) {
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewCluster(ctx, ClusterConfig{Name: "my-cluster"})
+		return err
+	})
 
-	taskDefinitionConfig, ok := taskDefinitionConfigJSON["taskDefinition"]
-	if !ok {
-		err = fmt.Errorf("'taskDefinition' key not found in JSON config")
-		sugar.Error(err)
-		return nil, err
-	}
+	component := m.resource(t, ClusterType, "my-cluster")
+	assert.Len(t, component.Aliases, 1)
+	assert.Equal(t, "aws:ecs:Cluster", component.Aliases[0].GetType())
+	assert.Empty(t, component.Aliases[0].GetName())
 
-	return taskDefinitionConfig, nil
+	cluster := m.resource(t, "aws:ecs/cluster:Cluster", "my-cluster")
+	assert.Len(t, cluster.Aliases, 1)
+	assert.Equal(t, "cluster", cluster.Aliases[0].GetName())
+	assert.Empty(t, cluster.Aliases[0].GetType())
 }
 
-// TestNewTaskDefinition is an integration test that checks the correctness of an AWS ECS task definition creation.
-// It simulates the process of creating a task definition with defined parameters, which can be found in examples/TaskDefinition/config.json, and expected outcomes.
-// The test will pass if the task definition is created successfully.
-// Otherwise, it will fail providing information about what incidentally caused the failure.
-func TestNewTaskDefinition(t *testing.T) {
-	logger, err := zap.NewProduction()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		if err := logger.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
-		}
-	}()
-
-	sugar := logger.Sugar()
-
-	sugar.Info("Reading ECS task definition configuration from examples/TaskDefinition/config.json")
-	taskDefinitionConfig, err := getTaskDefinitionConfig(sugar)
+// TestNewClusterCapacityProviderInputs is a unit test that checks the inputs of the cluster capacity providers created from examples/ClusterCapacityProvider/config.json.
+func TestNewClusterCapacityProviderInputs(t *testing.T) {
+	clusterCapacityProviderConfig, err := getClusterCapacityProviderConfig(zap.NewNop().Sugar())
 	assert.NoError(t, err)
-	sugar.Info("Successfully read configuration!")
-
-	ctx := context.Background()
-	projectName := "test_ecs_task_definition"
-
-	stack, err := auto.UpsertStackInlineSource(ctx, stackName, projectName, func(ctx *pulumi.Context) error {
-		current, err := aws.GetCallerIdentity(ctx, nil, nil)
-		assert.NoError(t, err)
-		executionRoleArn := strings.Replace(*taskDefinitionConfig.ExecutionRoleArn, "$ACCOUNT_ID", current.AccountId, 1)
-		taskDefinitionConfig.ExecutionRoleArn = &executionRoleArn
-
-		taskDefinitionRoleArn := strings.Replace(*taskDefinitionConfig.TaskRoleArn, "$ACCOUNT_ID", current.AccountId, 1)
-		taskDefinitionConfig.TaskRoleArn = &taskDefinitionRoleArn
 
-		_, err = NewTaskDefinition(ctx, *taskDefinitionConfig)
-		if err != nil {
-			return err
-		}
-		return nil
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewClusterCapacityProvider(ctx, *clusterCapacityProviderConfig)
+		return err
 	})
-	assert.NoError(t, err)
 
-	// Set config, run 'pulumi up', and afterwards 'pulumi destroy'
-	manageResources(ctx, stack, sugar, t)
+	clusterCapacityProviders := m.resource(t, "aws:ecs/clusterCapacityProviders:ClusterCapacityProviders", "my-cluster")
+	assert.Equal(t, componentURN(ClusterCapacityProviderType, "my-cluster"), clusterCapacityProviders.Parent)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"capacityProviders": []interface{}{"FARGATE", "FARGATE_SPOT"},
+		"clusterName":       "my-cluster",
+		"defaultCapacityProviderStrategies": []interface{}{
+			map[string]interface{}{"base": 1, "capacityProvider": "FARGATE", "weight": 60},
+			map[string]interface{}{"base": 0, "capacityProvider": "FARGATE_SPOT", "weight": 40},
+		},
+	}), clusterCapacityProviders.Inputs)
 }
 
-func createTaskSetsTestDependencies(ctx *pulumi.Context) error {
-	containerDefinitions, err := json.Marshal([]interface{}{
-		map[string]interface{}{
-			"name":  "my-container",
-			"image": "nginx",
-			"portMappings": []map[string]interface{}{
-				{
-					"containerPort": 80,
-					"hostPort":      80,
-				},
-			},
-		},
-	})
-	if err != nil {
-		return err
-	}
+// TestNewServiceInputs is a unit test that checks the inputs of the service created from examples/Service/config.json.
+func TestNewServiceInputs(t *testing.T) {
+	t.Setenv("AWS_ACCOUNT_ID", "123456789012")
 
-	current, err := aws.GetCallerIdentity(ctx, nil, nil)
-	if err != nil {
-		return err
-	}
+	serviceConfig, err := getServiceConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
 
-	_, err = ecs.NewTaskDefinition(ctx, "taskSetsTestDependency", &ecs.TaskDefinitionArgs{
-		Family:                  pulumi.String("my-task-definition"),
-		ContainerDefinitions:    pulumi.String(containerDefinitions),
-		Cpu:                     pulumi.String("256"),
-		ExecutionRoleArn:        pulumi.String("arn:aws:iam::" + current.AccountId + ":role/aws-service-role/ecs.amazonaws.com/AWSServiceRoleForECS"),
-		Memory:                  pulumi.String("512"),
-		NetworkMode:             pulumi.String("awsvpc"),
-		RequiresCompatibilities: pulumi.ToStringArray([]string{"FARGATE"}),
-	})
-	if err != nil {
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewService(ctx, *serviceConfig)
 		return err
-	}
-
-	_, err = ecs.NewCluster(ctx, "taskSetsTestDependency", &ecs.ClusterArgs{
-		Name: pulumi.String("my-cluster"),
 	})
-	if err != nil {
-		return err
-	}
 
-	_, err = ecs.NewService(ctx, "taskSetsTestDependency", &ecs.ServiceArgs{
-		Name:    pulumi.String("my-service"),
-		Cluster: pulumi.String("arn:aws:ecs:us-west-2:" + current.AccountId + ":cluster/my-cluster"),
-		DeploymentController: ecs.ServiceDeploymentControllerArgs{
-			Type: pulumi.String("EXTERNAL"),
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, componentURN(ServiceType, "my-ecs-service"), service.Parent)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"cluster": "arn:aws:ecs:us-west-2:123456789012:cluster/my-cluster",
+		"deploymentCircuitBreaker": map[string]interface{}{
+			"enable":   true,
+			"rollback": true,
 		},
-		DesiredCount: pulumi.Int(2),
-	})
-	if err != nil {
-		return err
-	}
-	return nil
+		"deploymentController":            map[string]interface{}{"type": "ECS"},
+		"deploymentMaximumPercent":        200,
+		"deploymentMinimumHealthyPercent": 50,
+		"desiredCount":                    0,
+		"enableEcsManagedTags":            true,
+		"enableExecuteCommand":            true,
+		"forceNewDeployment":              true,
+		"launchType":                      "FARGATE",
+		"name":                            "my-ecs-service",
+		"networkConfiguration": map[string]interface{}{
+			"assignPublicIp": true,
+			"securityGroups": []interface{}{"sg-05b0afb82b145e891"},
+			"subnets":        []interface{}{"subnet-018d8f50ed69b2301", "subnet-001eccafa60d95758"},
+		},
+		"platformVersion":    "1.4.0",
+		"propagateTags":      "SERVICE",
+		"schedulingStrategy": "REPLICA",
+		"tags": map[string]interface{}{
+			"Environment": "Production",
+			"Team":        "DevOps",
+		},
+		"taskDefinition":     "my-task-definition",
+		"triggers":           map[string]interface{}{},
+		"waitForSteadyState": true,
+	}), service.Inputs)
 }
 
-func getTaskSetsConfig(sugar *zap.SugaredLogger) ([]TaskSetConfig, error) {
-	configData, err := os.ReadFile("examples/TaskSets/config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	var tempConfig map[string]interface{}
-	err = json.Unmarshal(configData, &tempConfig)
-	if err != nil {
-		sugar.Error(err)
-		return nil, err
-	}
+// TestNewServiceIgnoreChanges is a unit test that checks that the properties managed by the deployment controller are ignored.
+func TestNewServiceIgnoreChanges(t *testing.T) {
+	t.Setenv("AWS_ACCOUNT_ID", "123456789012")
 
-	var taskSets []TaskSetConfig
-	for _, taskSet := range tempConfig["taskSets"].([]interface{}) {
-		taskSetConfigJSON, err := json.Marshal(taskSet)
-		if err != nil {
-			sugar.Error(err)
-			return nil, err
-		}
+	for controller, expected := range map[string][]string{
+		"ECS":         nil,
+		"CODE_DEPLOY": {"taskDefinition", "loadBalancers"},
+		"EXTERNAL":    {"desiredCount", "taskDefinition", "loadBalancers"},
+	} {
+		serviceConfig, err := getServiceConfig(zap.NewNop().Sugar())
+		assert.NoError(t, err)
+		serviceConfig.DeploymentController.Type = pulumi.StringRef(controller)
 
-		var taskSetConfig TaskSetConfig
-		err = json.Unmarshal(taskSetConfigJSON, &taskSetConfig)
-		if err != nil {
-			sugar.Errorf("Error unmarshaling config file: %v", err)
-			return nil, err
-		}
+		m := newMocks()
+		runWithMocks(t, m, func(ctx *pulumi.Context) error {
+			_, err := NewService(ctx, *serviceConfig)
+			return err
+		})
 
-		taskSets = append(taskSets, taskSetConfig)
+		assert.Equal(t, expected, m.resource(t, "aws:ecs/service:Service", "my-ecs-service").IgnoreChanges, controller)
 	}
+}
 
-	return taskSets, nil
+// TestServiceIgnoreChangesWarnings is a unit test that checks that a warning is given for each ignored property that the configuration sets.
+func TestServiceIgnoreChangesWarnings(t *testing.T) {
+	serviceConfig, err := getServiceConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	serviceConfig.AutoScaling = &ServiceAutoScalingConfig{MaxCapacity: 4}
+	serviceConfig.DeploymentController.Type = pulumi.StringRef("CODE_DEPLOY")
+
+	ignoreChanges, warnings := serviceIgnoreChanges(serviceConfig.ToArgs())
+	assert.Equal(t, []string{"taskDefinition", "loadBalancers", "desiredCount"}, ignoreChanges)
+	assert.Equal(t, []string{
+		"taskDefinition is managed by CodeDeploy; the value in the configuration is only used when the service is created",
+		"desiredCount is managed by auto scaling; the value in the configuration is only used when the service is created",
+	}, warnings)
 }
 
-// TestNewTaskSets is an integration test that checks the correctness of the creation of AWS ECS task sets.
-// It simulates the process of creating task sets with defined parameters, which can be found in examples/TaskSets/config.json, and expected outcomes.
-// The test will pass if the task sets are created successfully.
-// Otherwise, it will fail providing information about what incidentally caused the failure.
-func TestNewTaskSets(t *testing.T) {
-	logger, err := zap.NewProduction()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		if err := logger.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
-		}
-	}()
+// TestServiceIgnoreChangesUnknownController is a unit test that checks that a deployment controller type that is not a plain string is warned about.
+func TestServiceIgnoreChangesUnknownController(t *testing.T) {
+	serviceConfig, err := getServiceConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	args := serviceConfig.ToArgs()
+	args.DeploymentController.Type = pulumi.String("EXTERNAL").ToStringOutput()
+
+	ignoreChanges, warnings := serviceIgnoreChanges(args)
+	assert.Empty(t, ignoreChanges)
+	assert.Equal(t, []string{
+		"deploymentController.type is not known before the deployment, so the properties managed by the deployment controller are not ignored and `pulumi up` may revert them; set it to a plain string",
+	}, warnings)
+}
 
-	sugar := logger.Sugar()
+// TestNewTaskDefinitionInputs is a unit test that checks the inputs of the task definition created from examples/TaskDefinition/config.json.
+func TestNewTaskDefinitionInputs(t *testing.T) {
+	t.Setenv("AWS_ACCOUNT_ID", "123456789012")
 
-	sugar.Info("Reading ECS task set configuration from examples/TaskSets/config.json")
-	taskSetsConfig, err := getTaskSetsConfig(sugar)
+	taskDefinitionConfig, err := getTaskDefinitionConfig(zap.NewNop().Sugar())
 	assert.NoError(t, err)
-	sugar.Info("Successfully read configuration!")
 
-	ctx := context.Background()
-	projectName := "test_ecs_task_sets"
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewTaskDefinition(ctx, *taskDefinitionConfig)
+		return err
+	})
 
-	stack, err := auto.UpsertStackInlineSource(ctx, stackName, projectName, func(ctx *pulumi.Context) error {
-		err = createTaskSetsTestDependencies(ctx)
-		if err != nil {
-			return err
-		}
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-task-definition")
+	assert.Equal(t, componentURN(TaskDefinitionType, "my-task-definition"), taskDefinition.Parent)
+	assert.JSONEq(t, `[{"name":"my-container","image":"nginx","portMappings":[{"containerPort":80,"hostPort":80}]}]`,
+		taskDefinition.Inputs["containerDefinitions"].StringValue())
+	delete(taskDefinition.Inputs, "containerDefinitions")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"cpu":                     "256",
+		"ephemeralStorage":        map[string]interface{}{"sizeInGib": 30},
+		"executionRoleArn":        "arn:aws:iam::123456789012:role/aws-service-role/ecs.amazonaws.com/AWSServiceRoleForECS",
+		"family":                  "my-task-definition",
+		"memory":                  "512",
+		"networkMode":             "awsvpc",
+		"pidMode":                 "task",
+		"requiresCompatibilities": []interface{}{"FARGATE"},
+		"runtimePlatform": map[string]interface{}{
+			"cpuArchitecture":       "X86_64",
+			"operatingSystemFamily": "LINUX",
+		},
+		"skipDestroy": false,
+		"tags": map[string]interface{}{
+			"environment": "production",
+			"owner":       "myteam",
+		},
+		"taskRoleArn": "arn:aws:iam::123456789012:role/aws-service-role/ecs.amazonaws.com/AWSServiceRoleForECS",
+		"trackLatest": true,
+	}), taskDefinition.Inputs)
+}
 
-		_, err = NewTaskSets(ctx, taskSetsConfig)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+// TestNewTaskSetsInputs is a unit test that checks the inputs of the task sets created from examples/TaskSets/config.json.
+func TestNewTaskSetsInputs(t *testing.T) {
+	taskSetsConfig, err := getTaskSetsConfig(zap.NewNop().Sugar())
 	assert.NoError(t, err)
 
-	// Set config, run 'pulumi up', and afterwards 'pulumi destroy'
-	manageResources(ctx, stack, sugar, t)
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewTaskSets(ctx, taskSetsConfig)
+		return err
+	})
+
+	taskSet := m.resource(t, "aws:ecs/taskSet:TaskSet", "my-service-task-set")
+	assert.Equal(t, componentURN(TaskSetType, "my-service-task-set"), taskSet.Parent)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"cluster":    "my-cluster",
+		"launchType": "FARGATE",
+		"networkConfiguration": map[string]interface{}{
+			"assignPublicIp": true,
+			"securityGroups": []interface{}{"sg-05b0afb82b145e891"},
+			"subnets":        []interface{}{"subnet-018d8f50ed69b2301", "subnet-001eccafa60d95758"},
+		},
+		"platformVersion": "1.4.0",
+		"scale": map[string]interface{}{
+			"unit":  "PERCENT",
+			"value": 50,
+		},
+		"service":         "my-service",
+		"tags":            map[string]interface{}{},
+		"taskDefinition":  "my-task-definition",
+		"waitUntilStable": false,
+	}), taskSet.Inputs)
 }
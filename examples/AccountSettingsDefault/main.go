@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -40,36 +39,12 @@ func main() {
 }
 
 func getAccountSettingsDefaultConfig(sugar *zap.SugaredLogger) ([]ecs.AccountSettingDefaultConfig, error) {
-	configData, err := os.ReadFile("config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	var tempConfig map[string]interface{}
-	err = json.Unmarshal(configData, &tempConfig)
+	var accountSettingsDefault []ecs.AccountSettingDefaultConfig
+	err := ecs.LoadConfigFile("config.json", "accountSettingsDefault", &accountSettingsDefault)
 	if err != nil {
 		sugar.Error(err)
 		return nil, err
 	}
 
-	var accountSettingsDefault []ecs.AccountSettingDefaultConfig
-	for _, accountSettingDefault := range tempConfig["accountSettingsDefault"].([]interface{}) {
-		accountSettingDefaultConfigJSON, err := json.Marshal(accountSettingDefault)
-		if err != nil {
-			sugar.Error(err)
-			return nil, err
-		}
-
-		var accountSettingDefaultConfig ecs.AccountSettingDefaultConfig
-		err = json.Unmarshal(accountSettingDefaultConfigJSON, &accountSettingDefaultConfig)
-		if err != nil {
-			sugar.Errorf("Error unmarshaling config file: %v", err)
-			return nil, err
-		}
-
-		accountSettingsDefault = append(accountSettingsDefault, accountSettingDefaultConfig)
-	}
-
 	return accountSettingsDefault, nil
 }
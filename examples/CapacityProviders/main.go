@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -30,46 +29,26 @@ func main() {
 	}
 
 	pulumi.Run(func(ctx *pulumi.Context) error {
-		err = ecs.NewCapacityProviders(ctx, capacityProvidersConfig)
+		capacityProviders, err := ecs.NewCapacityProviders(ctx, capacityProvidersConfig)
 		if err != nil {
 			sugar.Error(err)
 			return err
 		}
+
+		for i, capacityProvider := range capacityProviders {
+			ctx.Export(fmt.Sprintf("capacityProviderArn-%d", i+1), capacityProvider.Arn)
+		}
 		return nil
 	})
 }
 
 func getCapacityProvidersConfig(sugar *zap.SugaredLogger) ([]ecs.CapacityProviderConfig, error) {
-	configData, err := os.ReadFile("config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	var tempConfig map[string]interface{}
-	err = json.Unmarshal(configData, &tempConfig)
+	var capacityProviders []ecs.CapacityProviderConfig
+	err := ecs.LoadConfigFile("config.json", "capacityProviders", &capacityProviders)
 	if err != nil {
 		sugar.Error(err)
 		return nil, err
 	}
 
-	var capacityProviders []ecs.CapacityProviderConfig
-	for _, capacityProvider := range tempConfig["capacityProviders"].([]interface{}) {
-		capacityProviderConfigJSON, err := json.Marshal(capacityProvider)
-		if err != nil {
-			sugar.Error(err)
-			return nil, err
-		}
-
-		var capacityProviderConfig ecs.CapacityProviderConfig
-		err = json.Unmarshal(capacityProviderConfigJSON, &capacityProviderConfig)
-		if err != nil {
-			sugar.Errorf("Error unmarshaling config file: %v", err)
-			return nil, err
-		}
-
-		capacityProviders = append(capacityProviders, capacityProviderConfig)
-	}
-
 	return capacityProviders, nil
 }
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -30,36 +29,25 @@ func main() {
 	}
 
 	pulumi.Run(func(ctx *pulumi.Context) error {
-		_, err = ecs.NewCluster(ctx, *clusterConfig)
+		cluster, err := ecs.NewCluster(ctx, *clusterConfig)
 		if err != nil {
 			sugar.Error(err)
 			return err
 		}
+
+		ctx.Export("clusterArn", cluster.Arn)
+		ctx.Export("clusterName", cluster.Name)
 		return nil
 	})
 }
 
 func getClusterConfig(sugar *zap.SugaredLogger) (*ecs.ClusterConfig, error) {
-	configData, err := os.ReadFile("config.json")
+	var clusterConfig ecs.ClusterConfig
+	err := ecs.LoadConfigFile("config.json", "cluster", &clusterConfig)
 	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	clusterConfigJSON := make(map[string]*ecs.ClusterConfig)
-
-	err = json.Unmarshal(configData, &clusterConfigJSON)
-	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
-	}
-
-	clusterConfig, ok := clusterConfigJSON["cluster"]
-	if !ok {
-		err = fmt.Errorf("'cluster' key not found in JSON config")
 		sugar.Error(err)
 		return nil, err
 	}
 
-	return clusterConfig, nil
+	return &clusterConfig, nil
 }
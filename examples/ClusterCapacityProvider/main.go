@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -30,7 +29,7 @@ func main() {
 	}
 
 	pulumi.Run(func(ctx *pulumi.Context) error {
-		err = ecs.NewClusterCapacityProvider(ctx, *clusterCapacityProviderConfig)
+		_, err = ecs.NewClusterCapacityProvider(ctx, *clusterCapacityProviderConfig)
 		if err != nil {
 			sugar.Error(err)
 			return err
@@ -40,26 +39,12 @@ func main() {
 }
 
 func getClusterCapacityProviderConfig(sugar *zap.SugaredLogger) (*ecs.ClusterCapacityProviderConfig, error) {
-	configData, err := os.ReadFile("config.json")
+	var clusterCapacityProviderConfig ecs.ClusterCapacityProviderConfig
+	err := ecs.LoadConfigFile("config.json", "clusterCapacityProvider", &clusterCapacityProviderConfig)
 	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	clusterCapacityProviderConfigJSON := make(map[string]*ecs.ClusterCapacityProviderConfig)
-
-	err = json.Unmarshal(configData, &clusterCapacityProviderConfigJSON)
-	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
-	}
-
-	clusterCapacityProviderConfig, ok := clusterCapacityProviderConfigJSON["clusterCapacityProvider"]
-	if !ok {
-		err = fmt.Errorf("'clusterCapacityProvider' key not found in JSON config")
 		sugar.Error(err)
 		return nil, err
 	}
 
-	return clusterCapacityProviderConfig, nil
+	return &clusterCapacityProviderConfig, nil
 }
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -30,36 +29,25 @@ func main() {
 	}
 
 	pulumi.Run(func(ctx *pulumi.Context) error {
-		_, err = ecs.NewService(ctx, *serviceConfig)
+		service, err := ecs.NewService(ctx, *serviceConfig)
 		if err != nil {
 			sugar.Error(err)
 			return err
 		}
+
+		ctx.Export("serviceArn", service.Arn)
+		ctx.Export("serviceName", service.Name)
 		return nil
 	})
 }
 
 func getServiceConfig(sugar *zap.SugaredLogger) (*ecs.ServiceConfig, error) {
-	configData, err := os.ReadFile("config.json")
+	var serviceConfig ecs.ServiceConfig
+	err := ecs.LoadConfigFile("config.json", "service", &serviceConfig)
 	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	serviceConfigJSON := make(map[string]*ecs.ServiceConfig)
-
-	err = json.Unmarshal(configData, &serviceConfigJSON)
-	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
-	}
-
-	serviceConfig, ok := serviceConfigJSON["service"]
-	if !ok {
-		err = fmt.Errorf("'service' key not found in JSON config")
 		sugar.Error(err)
 		return nil, err
 	}
 
-	return serviceConfig, nil
+	return &serviceConfig, nil
 }
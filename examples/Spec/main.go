@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	ecs "github.com/janduursma/pulumi-component-aws-ecs"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"go.uber.org/zap"
+)
+
+func main() {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := logger.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to sync logger: %v", err)
+		}
+	}()
+
+	sugar := logger.Sugar()
+
+	spec, err := ecs.LoadSpec("spec.json")
+	if err != nil {
+		sugar.Fatal(err)
+	}
+
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		topology, err := ecs.Apply(ctx, spec)
+		if err != nil {
+			sugar.Error(err)
+			return err
+		}
+
+		ctx.Export("clusterArn", topology.Clusters["main"].Arn)
+		ctx.Export("taskDefinitionArn", topology.TaskDefinitions["api"].Arn)
+		ctx.Export("serviceArn", topology.Services["api"].Arn)
+		return nil
+	})
+}
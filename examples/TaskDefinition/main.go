@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -30,36 +29,25 @@ func main() {
 	}
 
 	pulumi.Run(func(ctx *pulumi.Context) error {
-		_, err = ecs.NewTaskDefinition(ctx, *taskDefinitionConfig)
+		taskDefinition, err := ecs.NewTaskDefinition(ctx, *taskDefinitionConfig)
 		if err != nil {
 			sugar.Error(err)
 			return err
 		}
+
+		ctx.Export("taskDefinitionArn", taskDefinition.Arn)
+		ctx.Export("taskDefinitionRevision", taskDefinition.Revision)
 		return nil
 	})
 }
 
 func getTaskDefinitionConfig(sugar *zap.SugaredLogger) (*ecs.TaskDefinitionConfig, error) {
-	configData, err := os.ReadFile("config.json")
+	var taskDefinitionConfig ecs.TaskDefinitionConfig
+	err := ecs.LoadConfigFile("config.json", "taskDefinition", &taskDefinitionConfig)
 	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	taskDefinitionConfigJSON := make(map[string]*ecs.TaskDefinitionConfig)
-
-	err = json.Unmarshal(configData, &taskDefinitionConfigJSON)
-	if err != nil {
-		sugar.Errorf("Error unmarshaling config file: %v", err)
-		return nil, err
-	}
-
-	taskDefinitionConfig, ok := taskDefinitionConfigJSON["taskDefinition"]
-	if !ok {
-		err = fmt.Errorf("'taskDefinition' key not found in JSON config")
 		sugar.Error(err)
 		return nil, err
 	}
 
-	return taskDefinitionConfig, nil
+	return &taskDefinitionConfig, nil
 }
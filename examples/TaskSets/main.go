@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -30,46 +29,26 @@ func main() {
 	}
 
 	pulumi.Run(func(ctx *pulumi.Context) error {
-		_, err = ecs.NewTaskSets(ctx, taskSetsConfig)
+		taskSets, err := ecs.NewTaskSets(ctx, taskSetsConfig)
 		if err != nil {
 			sugar.Error(err)
 			return err
 		}
+
+		for i, taskSet := range taskSets {
+			ctx.Export(fmt.Sprintf("taskSetArn-%d", i+1), taskSet.Arn)
+		}
 		return nil
 	})
 }
 
 func getTaskSetsConfig(sugar *zap.SugaredLogger) ([]ecs.TaskSetConfig, error) {
-	configData, err := os.ReadFile("config.json")
-	if err != nil {
-		sugar.Errorf("Error reading config file: %v", err)
-		return nil, err
-	}
-
-	var tempConfig map[string]interface{}
-	err = json.Unmarshal(configData, &tempConfig)
+	var taskSets []ecs.TaskSetConfig
+	err := ecs.LoadConfigFile("config.json", "taskSets", &taskSets)
 	if err != nil {
 		sugar.Error(err)
 		return nil, err
 	}
 
-	var taskSets []ecs.TaskSetConfig
-	for _, taskSet := range tempConfig["taskSets"].([]interface{}) {
-		taskSetConfigJSON, err := json.Marshal(taskSet)
-		if err != nil {
-			sugar.Error(err)
-			return nil, err
-		}
-
-		var taskSetConfig ecs.TaskSetConfig
-		err = json.Unmarshal(taskSetConfigJSON, &taskSetConfig)
-		if err != nil {
-			sugar.Errorf("Error unmarshaling config file: %v", err)
-			return nil, err
-		}
-
-		taskSets = append(taskSets, taskSetConfig)
-	}
-
 	return taskSets, nil
 }
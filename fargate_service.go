@@ -0,0 +1,440 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ec2"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/vpc"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// FargateServiceType is the type token of the FargateService component resource.
+const FargateServiceType = "ecscomponent:index:FargateService"
+
+// ecsTasksAssumeRolePolicy allows ECS tasks to assume a role.
+const ecsTasksAssumeRolePolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [{
+		"Effect": "Allow",
+		"Principal": {"Service": "ecs-tasks.amazonaws.com"},
+		"Action": "sts:AssumeRole"
+	}]
+}`
+
+// taskExecutionRolePolicy is the path of the AWS managed policy that allows ECS to pull images and write logs for a
+// task.
+const taskExecutionRolePolicy = "service-role/AmazonECSTaskExecutionRolePolicy"
+
+// logRetentionDays lists the retention periods, in days, that CloudWatch Logs supports. 0 keeps logs forever.
+var logRetentionDays = []int{0, 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// FargateServiceConfig defines arguments for running a single container as an AWS ECS service on Fargate.
+// Unset fields default to 256 CPU units, 512 MiB of memory, one task and a log retention of 30 days. When
+// clusterArn is unset, a cluster is created for the service, and when executionRoleArn is unset, an execution role
+// is created with the AmazonECSTaskExecutionRolePolicy managed policy.
+type FargateServiceConfig struct {
+	AssignPublicIP     *bool             `json:"assignPublicIp,omitempty"`
+	ClusterArn         *string           `json:"clusterArn,omitempty"`
+	Command            []string          `json:"command,omitempty"`
+	CPU                *string           `json:"cpu,omitempty"`
+	DesiredCount       *int              `json:"desiredCount,omitempty"`
+	Environment        map[string]string `json:"environment,omitempty"`
+	ExecutionRoleArn   *string           `json:"executionRoleArn,omitempty"`
+	Image              string            `json:"image"`
+	IngressCidrBlocks  []string          `json:"ingressCidrBlocks,omitempty"`
+	LogRetentionInDays *int              `json:"logRetentionInDays,omitempty"`
+	Memory             *string           `json:"memory,omitempty"`
+	Name               string            `json:"name"`
+	Ports              []int             `json:"ports,omitempty"`
+	Subnets            []string          `json:"subnets"`
+	Tags               map[string]string `json:"tags,omitempty"`
+	TaskRoleArn        *string           `json:"taskRoleArn,omitempty"`
+	VpcID              string            `json:"vpcId"`
+}
+
+// FargateServiceArgs defines inputs for running a single container as an AWS ECS service on Fargate.
+// The container settings and ingress CIDR blocks are plain values, because they determine which resources are
+// created.
+type FargateServiceArgs struct {
+	AssignPublicIP     pulumi.BoolPtrInput
+	ClusterArn         pulumi.StringInput
+	Command            []string
+	CPU                string
+	DesiredCount       pulumi.IntPtrInput
+	Environment        map[string]string
+	ExecutionRoleArn   pulumi.StringInput
+	Image              string
+	IngressCidrBlocks  []string
+	LogRetentionInDays pulumi.IntPtrInput
+	Memory             string
+	Ports              []int
+	Subnets            pulumi.StringArrayInput
+	Tags               pulumi.StringMapInput
+	TaskRoleArn        pulumi.StringPtrInput
+	VpcID              pulumi.StringInput
+}
+
+// FargateService is a component resource that runs a single container as an AWS ECS service on Fargate, together
+// with its task definition, security group, log group and, if needed, its cluster and execution role.
+type FargateService struct {
+	pulumi.ResourceState
+
+	// Cluster is the cluster created for the service, or nil if the service runs in an existing cluster.
+	Cluster *Cluster
+	// ExecutionRole is the execution role created for the task, or nil if an existing role is used.
+	ExecutionRole *iam.Role
+	// LogGroup is the log group the container logs to.
+	LogGroup *cloudwatch.LogGroup
+	// SecurityGroup is the security group of the tasks.
+	SecurityGroup *ec2.SecurityGroup
+	// Service is the service.
+	Service *Service
+	// TaskDefinition is the task definition of the service.
+	TaskDefinition *TaskDefinition
+}
+
+// ToArgs converts the Fargate service configuration into inputs. Unset fields are left unset, and get their defaults
+// in NewFargateServiceFromArgs.
+func (c FargateServiceConfig) ToArgs() *FargateServiceArgs {
+	args := &FargateServiceArgs{
+		AssignPublicIP:    pulumi.BoolPtrFromPtr(c.AssignPublicIP),
+		Command:           c.Command,
+		Environment:       c.Environment,
+		Image:             c.Image,
+		IngressCidrBlocks: c.IngressCidrBlocks,
+		Ports:             c.Ports,
+		Subnets:           pulumi.ToStringArray(c.Subnets),
+		Tags:              pulumi.ToStringMap(c.Tags),
+		TaskRoleArn:       pulumi.StringPtrFromPtr(c.TaskRoleArn),
+		VpcID:             pulumi.String(c.VpcID),
+	}
+
+	if c.ClusterArn != nil {
+		args.ClusterArn = pulumi.String(*c.ClusterArn)
+	}
+	if c.CPU != nil {
+		args.CPU = *c.CPU
+	}
+	if c.DesiredCount != nil {
+		args.DesiredCount = pulumi.Int(*c.DesiredCount)
+	}
+	if c.ExecutionRoleArn != nil {
+		args.ExecutionRoleArn = pulumi.String(*c.ExecutionRoleArn)
+	}
+	if c.LogRetentionInDays != nil {
+		args.LogRetentionInDays = pulumi.Int(*c.LogRetentionInDays)
+	}
+	if c.Memory != nil {
+		args.Memory = *c.Memory
+	}
+
+	return args
+}
+
+// withDefaults returns a copy of args in which unset fields have their defaults: 256 CPU units, 512 MiB of memory,
+// one task and a log retention of 30 days.
+func (a FargateServiceArgs) withDefaults() *FargateServiceArgs {
+	if a.CPU == "" {
+		a.CPU = "256"
+	}
+	if a.Memory == "" {
+		a.Memory = "512"
+	}
+	if a.DesiredCount == nil {
+		a.DesiredCount = pulumi.Int(1)
+	}
+	if a.LogRetentionInDays == nil {
+		a.LogRetentionInDays = pulumi.Int(30)
+	}
+	return &a
+}
+
+// Validate checks the Fargate service configuration and returns a *ValidationError listing every problem found.
+func (c FargateServiceConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "fargateService")
+	return v.err()
+}
+
+func (c FargateServiceConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	v.required(path+".image", c.Image)
+	v.required(path+".vpcId", c.VpcID)
+	if len(c.Subnets) == 0 {
+		v.errorf(path+".subnets", "must contain at least one subnet")
+	}
+	if c.DesiredCount != nil && *c.DesiredCount < 0 {
+		v.errorf(path+".desiredCount", "must not be negative, got %d", *c.DesiredCount)
+	}
+	if c.LogRetentionInDays != nil && !slices.Contains(logRetentionDays, *c.LogRetentionInDays) {
+		v.errorf(path+".logRetentionInDays", "must be a retention period supported by CloudWatch Logs, e.g. 30, got %d", *c.LogRetentionInDays)
+	}
+
+	args := c.ToArgs().withDefaults()
+	for i, port := range c.Ports {
+		v.between(fmt.Sprintf("%s.ports[%d]", path, i), &port, 1, 65535)
+	}
+	args.taskDefinitionConfig(c.Name, "").validateFargateSize(v, path)
+}
+
+// taskDefinitionConfig returns the task definition that runs the container of the service, logging to logGroup
+// in region.
+func (a *FargateServiceArgs) taskDefinitionConfig(name, region string) TaskDefinitionConfig {
+	essential := true
+	container := ContainerDefinition{
+		Command:   a.Command,
+		Essential: &essential,
+		Image:     a.Image,
+		LogConfiguration: &ContainerLogConfiguration{
+			LogDriver: "awslogs",
+			Options: map[string]string{
+				"awslogs-group":         "/ecs/" + name,
+				"awslogs-region":        region,
+				"awslogs-stream-prefix": name,
+			},
+		},
+		Name: name,
+	}
+	for _, key := range sortedKeys(a.Environment) {
+		container.Environment = append(container.Environment, ContainerKeyValuePair{Name: key, Value: a.Environment[key]})
+	}
+	for _, port := range a.Ports {
+		containerPort, protocol := port, "tcp"
+		container.PortMappings = append(container.PortMappings, ContainerPortMapping{
+			ContainerPort: &containerPort,
+			Protocol:      &protocol,
+		})
+	}
+
+	networkMode := "awsvpc"
+	return TaskDefinitionConfig{
+		ContainerDefinitions:    []ContainerDefinition{container},
+		CPU:                     &a.CPU,
+		Memory:                  &a.Memory,
+		Name:                    name,
+		NetworkMode:             &networkMode,
+		RequiresCompatibilities: []string{"FARGATE"},
+	}
+}
+
+// NewFargateService creates a new AWS ECS service on Fargate that runs a single container.
+func NewFargateService(ctx *pulumi.Context, config FargateServiceConfig, opts ...pulumi.ResourceOption) (*FargateService, error) {
+	config, err := interpolate(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFargateServiceFromArgs(ctx, config.Name, config.ToArgs(), opts...)
+}
+
+// NewFargateServiceFromArgs creates a new AWS ECS service on Fargate that runs a single container from inputs.
+// The task definition and service are created from a TaskDefinitionConfig and a ServiceConfig, like any other.
+func NewFargateServiceFromArgs(ctx *pulumi.Context, name string, args *FargateServiceArgs, opts ...pulumi.ResourceOption) (*FargateService, error) {
+	if args == nil {
+		return nil, fmt.Errorf("fargate service %s requires an image", name)
+	}
+	args = args.withDefaults()
+
+	err := resolveReferences(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &FargateService{}
+	err = ctx.RegisterComponentResource(FargateServiceType, name, component, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource: %v", err)
+	}
+
+	region, err := aws.GetRegion(ctx, nil, pulumi.Parent(component))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get region: %v", err)
+	}
+
+	taskDefinitionConfig := args.taskDefinitionConfig(name, region.Name)
+	err = taskDefinitionConfig.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	component.LogGroup, err = cloudwatch.NewLogGroup(ctx, name, &cloudwatch.LogGroupArgs{
+		Name:            pulumi.String("/ecs/" + name),
+		RetentionInDays: args.LogRetentionInDays,
+		Tags:            args.Tags,
+	}, pulumi.Parent(component))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new log group: %v", err)
+	}
+
+	executionRoleArn := args.ExecutionRoleArn
+	if executionRoleArn == nil {
+		err = component.newExecutionRole(ctx, name, args)
+		if err != nil {
+			return nil, err
+		}
+		executionRoleArn = component.ExecutionRole.Arn
+	}
+
+	err = component.newSecurityGroup(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	taskDefinitionArgs, err := taskDefinitionConfig.ToArgs()
+	if err != nil {
+		return nil, err
+	}
+	taskDefinitionArgs.ExecutionRoleArn = executionRoleArn
+	taskDefinitionArgs.Tags = args.Tags
+	taskDefinitionArgs.TaskRoleArn = args.TaskRoleArn
+
+	component.TaskDefinition, err = NewTaskDefinitionFromArgs(ctx, name, taskDefinitionArgs, pulumi.Parent(component), pulumi.DependsOn([]pulumi.Resource{component.LogGroup}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = component.newService(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ctx.RegisterResourceOutputs(component, component.outputs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+	}
+
+	return component, nil
+}
+
+// newExecutionRole creates the execution role of the tasks, with the AmazonECSTaskExecutionRolePolicy managed policy.
+func (s *FargateService) newExecutionRole(ctx *pulumi.Context, name string, args *FargateServiceArgs) error {
+	var err error
+	s.ExecutionRole, err = iam.NewRole(ctx, name+"-execution", &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(ecsTasksAssumeRolePolicy),
+		Tags:             args.Tags,
+	}, pulumi.Parent(s))
+	if err != nil {
+		return fmt.Errorf("failed to create new execution role: %v", err)
+	}
+
+	policyArn, err := managedPolicyArn(ctx, taskExecutionRolePolicy, s)
+	if err != nil {
+		return err
+	}
+	_, err = iam.NewRolePolicyAttachment(ctx, name+"-execution", &iam.RolePolicyAttachmentArgs{
+		PolicyArn: policyArn,
+		Role:      s.ExecutionRole.Name,
+	}, pulumi.Parent(s))
+	if err != nil {
+		return fmt.Errorf("failed to attach execution role policy: %v", err)
+	}
+	return nil
+}
+
+// managedPolicyArn returns the ARN of the AWS managed policy at path in the partition of the provider, so that roles
+// also work in the China and GovCloud regions.
+func managedPolicyArn(ctx *pulumi.Context, path string, parent pulumi.Resource) (pulumi.StringOutput, error) {
+	partition, err := aws.GetPartition(ctx, nil, pulumi.Parent(parent))
+	if err != nil {
+		return pulumi.StringOutput{}, fmt.Errorf("failed to get partition: %v", err)
+	}
+	return pulumi.Sprintf("arn:%s:iam::aws:policy/%s", partition.Partition, path), nil
+}
+
+// newSecurityGroup creates the security group of the tasks, which allows all outbound traffic and inbound traffic on
+// the ports of the container from the ingress CIDR blocks.
+func (s *FargateService) newSecurityGroup(ctx *pulumi.Context, name string, args *FargateServiceArgs) error {
+	var err error
+	s.SecurityGroup, err = ec2.NewSecurityGroup(ctx, name, &ec2.SecurityGroupArgs{
+		Description: pulumi.Sprintf("Tasks of the %s service", name),
+		Tags:        args.Tags,
+		VpcId:       args.VpcID,
+	}, pulumi.Parent(s))
+	if err != nil {
+		return fmt.Errorf("failed to create new security group: %v", err)
+	}
+
+	_, err = vpc.NewSecurityGroupEgressRule(ctx, name+"-egress", &vpc.SecurityGroupEgressRuleArgs{
+		CidrIpv4:        pulumi.String("0.0.0.0/0"),
+		IpProtocol:      pulumi.String("-1"),
+		SecurityGroupId: s.SecurityGroup.ID(),
+	}, pulumi.Parent(s))
+	if err != nil {
+		return fmt.Errorf("failed to create new security group egress rule: %v", err)
+	}
+
+	for _, port := range args.Ports {
+		for i, cidrBlock := range args.IngressCidrBlocks {
+			_, err = vpc.NewSecurityGroupIngressRule(ctx, fmt.Sprintf("%s-ingress-%d-%d", name, port, i), &vpc.SecurityGroupIngressRuleArgs{
+				CidrIpv4:        pulumi.String(cidrBlock),
+				FromPort:        pulumi.Int(port),
+				IpProtocol:      pulumi.String("tcp"),
+				SecurityGroupId: s.SecurityGroup.ID(),
+				ToPort:          pulumi.Int(port),
+			}, pulumi.Parent(s))
+			if err != nil {
+				return fmt.Errorf("failed to create new security group ingress rule: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// newService creates the service that runs the task definition, in a new cluster unless args name one.
+func (s *FargateService) newService(ctx *pulumi.Context, name string, args *FargateServiceArgs) error {
+	clusterArn := args.ClusterArn
+	if clusterArn == nil {
+		cluster, err := NewClusterFromArgs(ctx, name, &ClusterArgs{Name: pulumi.String(name), Tags: args.Tags}, pulumi.Parent(s))
+		if err != nil {
+			return err
+		}
+		s.Cluster = cluster
+		clusterArn = cluster.Arn
+	}
+
+	launchType := "FARGATE"
+	serviceArgs := ServiceConfig{LaunchType: &launchType, Name: name}.ToArgs()
+	serviceArgs.ClusterArn = clusterArn
+	serviceArgs.DesiredCount = args.DesiredCount
+	serviceArgs.NetworkConfiguration = &ecs.ServiceNetworkConfigurationArgs{
+		AssignPublicIp: args.AssignPublicIP,
+		SecurityGroups: pulumi.StringArray{s.SecurityGroup.ID()},
+		Subnets:        args.Subnets,
+	}
+	serviceArgs.Tags = args.Tags
+	serviceArgs.TaskDefinition = s.TaskDefinition.Arn
+
+	service, err := NewServiceFromArgs(ctx, name, serviceArgs, pulumi.Parent(s))
+	if err != nil {
+		return err
+	}
+	s.Service = service
+	return nil
+}
+
+// outputs returns the outputs of the component, including the cluster and execution role if they were created.
+func (s *FargateService) outputs() pulumi.Map {
+	outputs := pulumi.Map{
+		"logGroupName":      s.LogGroup.Name,
+		"securityGroupId":   s.SecurityGroup.ID(),
+		"serviceArn":        s.Service.Arn,
+		"taskDefinitionArn": s.TaskDefinition.Arn,
+	}
+	if s.Cluster != nil {
+		outputs["clusterArn"] = s.Cluster.Arn
+	}
+	if s.ExecutionRole != nil {
+		outputs["executionRoleArn"] = s.ExecutionRole.Arn
+	}
+	return outputs
+}
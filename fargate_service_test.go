@@ -0,0 +1,130 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+func getFargateServiceConfig() FargateServiceConfig {
+	return FargateServiceConfig{
+		Environment:       map[string]string{"PORT": "8080", "LOG_LEVEL": "info"},
+		Image:             "nginx",
+		IngressCidrBlocks: []string{"10.0.0.0/16"},
+		Name:              "my-fargate-service",
+		Ports:             []int{8080},
+		Subnets:           []string{"subnet-018d8f50ed69b2301"},
+		VpcID:             "vpc-0123456789abcdef0",
+	}
+}
+
+// TestNewFargateServiceDefaults is a unit test that checks the resources created for a Fargate service with default settings.
+func TestNewFargateServiceDefaults(t *testing.T) {
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewFargateService(ctx, getFargateServiceConfig())
+		return err
+	})
+
+	assert.Equal(t, 1, m.count(ClusterType))
+	assert.Equal(t, 1, m.count("aws:iam/role:Role"))
+	assert.Equal(t, 1, m.count("aws:vpc/securityGroupIngressRule:SecurityGroupIngressRule"))
+
+	// The components of a FargateService never existed under the legacy type tokens.
+	for _, typeToken := range []string{ClusterType, TaskDefinitionType, ServiceType} {
+		assert.Empty(t, m.resource(t, typeToken, "my-fargate-service").Aliases)
+	}
+
+	attachment := m.resource(t, "aws:iam/rolePolicyAttachment:RolePolicyAttachment", "my-fargate-service-execution")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"), attachment.Inputs["policyArn"])
+
+	logGroup := m.resource(t, "aws:cloudwatch/logGroup:LogGroup", "my-fargate-service")
+	assert.Equal(t, componentURN(FargateServiceType, "my-fargate-service"), logGroup.Parent)
+	assert.Equal(t, resource.NewStringProperty("/ecs/my-fargate-service"), logGroup.Inputs["name"])
+	assert.Equal(t, resource.NewNumberProperty(30), logGroup.Inputs["retentionInDays"])
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-fargate-service")
+	assert.Empty(t, taskDefinition.Aliases)
+	assert.Equal(t, resource.NewStringProperty("256"), taskDefinition.Inputs["cpu"])
+	assert.Equal(t, resource.NewStringProperty("512"), taskDefinition.Inputs["memory"])
+	assert.Equal(t, resource.NewStringProperty("awsvpc"), taskDefinition.Inputs["networkMode"])
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-fargate-service-execution"), taskDefinition.Inputs["executionRoleArn"])
+	assert.JSONEq(t, `[{
+		"name": "my-fargate-service",
+		"image": "nginx",
+		"essential": true,
+		"environment": [{"name": "LOG_LEVEL", "value": "info"}, {"name": "PORT", "value": "8080"}],
+		"logConfiguration": {
+			"logDriver": "awslogs",
+			"options": {"awslogs-group": "/ecs/my-fargate-service", "awslogs-region": "us-west-2", "awslogs-stream-prefix": "my-fargate-service"}
+		},
+		"portMappings": [{"containerPort": 8080, "protocol": "tcp"}]
+	}]`, taskDefinition.Inputs["containerDefinitions"].StringValue())
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-fargate-service")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-fargate-service"), service.Inputs["cluster"])
+	assert.Equal(t, resource.NewNumberProperty(1), service.Inputs["desiredCount"])
+	assert.Equal(t, resource.NewStringProperty("FARGATE"), service.Inputs["launchType"])
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-fargate-service"), service.Inputs["taskDefinition"])
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"securityGroups": []interface{}{"my-fargate-service_id"},
+		"subnets":        []interface{}{"subnet-018d8f50ed69b2301"},
+	}), service.Inputs["networkConfiguration"].ObjectValue())
+}
+
+// TestNewFargateServiceExistingResources is a unit test that checks that no cluster or execution role is created when their ARNs are given.
+func TestNewFargateServiceExistingResources(t *testing.T) {
+	config := getFargateServiceConfig()
+	config.ClusterArn = pulumi.StringRef("arn:aws:ecs:us-west-2:123456789012:cluster/my-cluster")
+	config.ExecutionRoleArn = pulumi.StringRef("arn:aws:iam::123456789012:role/my-execution-role")
+
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewFargateService(ctx, config)
+		return err
+	})
+
+	assert.Equal(t, 0, m.count(ClusterType))
+	assert.Equal(t, 0, m.count("aws:iam/role:Role"))
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-fargate-service")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:ecs:us-west-2:123456789012:cluster/my-cluster"), service.Inputs["cluster"])
+}
+
+// TestFargateServiceConfigValidate is a unit test that checks that an invalid Fargate service configuration is rejected with every problem listed.
+func TestFargateServiceConfigValidate(t *testing.T) {
+	config := getFargateServiceConfig()
+	config.LogRetentionInDays = pulumi.IntRef(45)
+	config.Memory = pulumi.StringRef("4096")
+	config.Ports = []int{0}
+	config.Subnets = nil
+
+	assert.Equal(t, map[string]string{
+		"fargateService.logRetentionInDays": "must be a retention period supported by CloudWatch Logs, e.g. 30, got 45",
+		"fargateService.memory":             "4096 MiB is not supported by FARGATE with 256 CPU units, supported sizes are 512 to 2048 MiB",
+		"fargateService.ports[0]":           "must be between 1 and 65535, got 0",
+		"fargateService.subnets":            "must contain at least one subnet",
+	}, fieldErrors(t, config.Validate()))
+}
+
+// TestNewFargateServiceFromArgsDefaults is a unit test that checks that the defaults are applied to inputs that leave the task size unset.
+func TestNewFargateServiceFromArgsDefaults(t *testing.T) {
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewFargateServiceFromArgs(ctx, "my-fargate-service", &FargateServiceArgs{
+			Image:   "nginx",
+			Subnets: pulumi.ToStringArray([]string{"subnet-018d8f50ed69b2301"}),
+			VpcID:   pulumi.String("vpc-0123456789abcdef0"),
+		})
+		return err
+	})
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-fargate-service")
+	assert.Equal(t, resource.NewStringProperty("256"), taskDefinition.Inputs["cpu"])
+	assert.Equal(t, resource.NewStringProperty("512"), taskDefinition.Inputs["memory"])
+
+	logGroup := m.resource(t, "aws:cloudwatch/logGroup:LogGroup", "my-fargate-service")
+	assert.Equal(t, resource.NewNumberProperty(30), logGroup.Inputs["retentionInDays"])
+}
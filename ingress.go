@@ -0,0 +1,194 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/vpc"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// defaultIngressHealthCheckGracePeriodSeconds is the health check grace period of a service behind an ingress,
+// unless set. It gives new tasks time to start before failed load balancer health checks stop them.
+const defaultIngressHealthCheckGracePeriodSeconds = 60
+
+// ServiceIngressConfig defines how an existing Application Load Balancer routes traffic to an AWS ECS service. The
+// service gets its own target group and a listener rule on the listener. If LoadBalancerSecurityGroupID is set, the
+// security groups of the service are opened to it on the container port. Unless set, the target group forwards HTTP
+// traffic to IP targets and the listener rule matches every path.
+type ServiceIngressConfig struct {
+	ContainerName               string                   `json:"containerName"`
+	ContainerPort               int                      `json:"containerPort"`
+	DeregistrationDelay         *int                     `json:"deregistrationDelay,omitempty"`
+	HealthCheckPath             *string                  `json:"healthCheckPath,omitempty"`
+	HostHeaders                 []string                 `json:"hostHeaders,omitempty"`
+	ListenerArn                 string                   `json:"listenerArn"`
+	LoadBalancerSecurityGroupID *string                  `json:"loadBalancerSecurityGroupId,omitempty"`
+	PathPatterns                []string                 `json:"pathPatterns,omitempty"`
+	Priority                    *int                     `json:"priority,omitempty"`
+	Protocol                    *string                  `json:"protocol,omitempty"`
+	Stickiness                  *IngressStickinessConfig `json:"stickiness,omitempty"`
+	TargetType                  *string                  `json:"targetType,omitempty"`
+	VpcID                       string                   `json:"vpcId"`
+}
+
+// IngressStickinessConfig defines session stickiness of a target group. Type is lb_cookie (the default), which uses
+// a cookie generated by the load balancer, or app_cookie, which uses the application cookie named CookieName.
+type IngressStickinessConfig struct {
+	CookieDuration *int    `json:"cookieDuration,omitempty"`
+	CookieName     *string `json:"cookieName,omitempty"`
+	Type           *string `json:"type,omitempty"`
+}
+
+// ServiceIngressArgs defines inputs for routing traffic from an existing Application Load Balancer to an AWS ECS
+// service. The path patterns and host headers are plain values, because they determine the conditions of the
+// listener rule.
+type ServiceIngressArgs struct {
+	ContainerName               pulumi.StringInput
+	ContainerPort               pulumi.IntInput
+	DeregistrationDelay         pulumi.IntPtrInput
+	HealthCheckPath             pulumi.StringPtrInput
+	HostHeaders                 []string
+	ListenerArn                 pulumi.StringInput
+	LoadBalancerSecurityGroupID pulumi.StringPtrInput
+	PathPatterns                []string
+	Priority                    pulumi.IntPtrInput
+	Protocol                    pulumi.StringPtrInput
+	Stickiness                  *lb.TargetGroupStickinessArgs
+	TargetType                  pulumi.StringPtrInput
+	VpcID                       pulumi.StringInput
+}
+
+// toArgs converts the ingress configuration into inputs, filling in the defaults of the stickiness.
+func (c ServiceIngressConfig) toArgs() *ServiceIngressArgs {
+	args := &ServiceIngressArgs{
+		ContainerName:               pulumi.String(c.ContainerName),
+		ContainerPort:               pulumi.Int(c.ContainerPort),
+		DeregistrationDelay:         pulumi.IntPtrFromPtr(c.DeregistrationDelay),
+		HealthCheckPath:             pulumi.StringPtrFromPtr(c.HealthCheckPath),
+		HostHeaders:                 c.HostHeaders,
+		ListenerArn:                 pulumi.String(c.ListenerArn),
+		LoadBalancerSecurityGroupID: pulumi.StringPtrFromPtr(c.LoadBalancerSecurityGroupID),
+		PathPatterns:                c.PathPatterns,
+		Priority:                    pulumi.IntPtrFromPtr(c.Priority),
+		Protocol:                    pulumi.StringPtrFromPtr(c.Protocol),
+		TargetType:                  pulumi.StringPtrFromPtr(c.TargetType),
+		VpcID:                       pulumi.String(c.VpcID),
+	}
+
+	if c.Stickiness != nil {
+		stickinessType := "lb_cookie"
+		if c.Stickiness.Type != nil {
+			stickinessType = *c.Stickiness.Type
+		}
+		args.Stickiness = &lb.TargetGroupStickinessArgs{
+			CookieDuration: pulumi.IntPtrFromPtr(c.Stickiness.CookieDuration),
+			CookieName:     pulumi.StringPtrFromPtr(c.Stickiness.CookieName),
+			Enabled:        pulumi.Bool(true),
+			Type:           pulumi.String(stickinessType),
+		}
+	}
+
+	return args
+}
+
+func (c ServiceIngressConfig) validate(v *validator, path string) {
+	v.required(path+".containerName", c.ContainerName)
+	v.between(path+".containerPort", &c.ContainerPort, 1, 65535)
+	v.required(path+".listenerArn", c.ListenerArn)
+	v.required(path+".vpcId", c.VpcID)
+	v.oneOf(path+".protocol", c.Protocol, "HTTP", "HTTPS")
+	v.oneOf(path+".targetType", c.TargetType, "ip", "instance")
+	v.between(path+".priority", c.Priority, 1, 50000)
+	v.between(path+".deregistrationDelay", c.DeregistrationDelay, 0, 3600)
+
+	stickiness := c.Stickiness
+	if stickiness == nil {
+		return
+	}
+	stickinessPath := path + ".stickiness"
+	v.oneOf(stickinessPath+".type", stickiness.Type, "lb_cookie", "app_cookie")
+	v.between(stickinessPath+".cookieDuration", stickiness.CookieDuration, 1, 604800)
+	appCookie := stickiness.Type != nil && *stickiness.Type == "app_cookie"
+	if appCookie && stickiness.CookieName == nil {
+		v.errorf(stickinessPath+".cookieName", "is required for app_cookie stickiness")
+	}
+	if !appCookie && stickiness.CookieName != nil {
+		v.errorf(stickinessPath+".cookieName", "is only supported by app_cookie stickiness")
+	}
+}
+
+// newServiceIngress creates the target group and listener rule that route traffic from the load balancer to a
+// service, and the rules that open each of the given task security groups to the load balancer. It returns the
+// load balancer configuration that registers the tasks of the service with the target group, and the listener rule,
+// which has to be attached before the service is created.
+func newServiceIngress(ctx *pulumi.Context, name string, args *ServiceIngressArgs, securityGroups pulumi.StringArrayInput, tags pulumi.StringMapInput, parent pulumi.Resource) (*ecs.ServiceLoadBalancerArgs, *lb.ListenerRule, error) {
+	protocol := args.Protocol
+	if protocol == nil {
+		protocol = pulumi.String("HTTP")
+	}
+	targetType := args.TargetType
+	if targetType == nil {
+		targetType = pulumi.String("ip")
+	}
+
+	targetGroup, err := lb.NewTargetGroup(ctx, name, &lb.TargetGroupArgs{
+		DeregistrationDelay: args.DeregistrationDelay,
+		HealthCheck: &lb.TargetGroupHealthCheckArgs{
+			Path: args.HealthCheckPath,
+		},
+		Port:       args.ContainerPort,
+		Protocol:   protocol,
+		Stickiness: args.Stickiness,
+		Tags:       tags,
+		TargetType: targetType,
+		VpcId:      args.VpcID,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create new target group: %v", err)
+	}
+
+	listenerRule, err := lb.NewListenerRule(ctx, name, &lb.ListenerRuleArgs{
+		Actions: lb.ListenerRuleActionArray{
+			&lb.ListenerRuleActionArgs{
+				TargetGroupArn: targetGroup.Arn,
+				Type:           pulumi.String("forward"),
+			},
+		},
+		Conditions:  listenerRuleConditions(args.PathPatterns, args.HostHeaders),
+		ListenerArn: args.ListenerArn,
+		Priority:    args.Priority,
+		Tags:        tags,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create new listener rule: %v", err)
+	}
+
+	if args.LoadBalancerSecurityGroupID != nil {
+		// One rule is created per security group, so the list itself has to be known before the deployment.
+		groups, ok := securityGroups.(pulumi.StringArray)
+		if !ok {
+			return nil, nil, fmt.Errorf("ingress requires the security groups of the service to be known before the deployment")
+		}
+		for i, securityGroup := range groups {
+			_, err = vpc.NewSecurityGroupIngressRule(ctx, fmt.Sprintf("%s-alb-%d", name, i), &vpc.SecurityGroupIngressRuleArgs{
+				FromPort:                  args.ContainerPort,
+				IpProtocol:                pulumi.String("tcp"),
+				ReferencedSecurityGroupId: args.LoadBalancerSecurityGroupID,
+				SecurityGroupId:           securityGroup,
+				ToPort:                    args.ContainerPort,
+			}, pulumi.Parent(parent))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to create new security group ingress rule: %v", err)
+			}
+		}
+	}
+
+	loadBalancer := &ecs.ServiceLoadBalancerArgs{
+		ContainerName:  args.ContainerName,
+		ContainerPort:  args.ContainerPort,
+		TargetGroupArn: targetGroup.Arn,
+	}
+	return loadBalancer, listenerRule, nil
+}
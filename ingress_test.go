@@ -0,0 +1,100 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// ingressServiceFixture returns the service fixture behind an Application Load Balancer.
+func ingressServiceFixture(t *testing.T) (*ServiceConfig, createService) {
+	serviceConfig, create := serviceFixture(t)
+	serviceConfig.LoadBalancers = nil
+	serviceConfig.HealthCheckGracePeriodSeconds = nil
+	serviceConfig.Ingress = &ServiceIngressConfig{
+		ContainerName:               "my-container",
+		ContainerPort:               80,
+		HealthCheckPath:             pulumi.StringRef("/health"),
+		HostHeaders:                 []string{"api.example.com"},
+		ListenerArn:                 productionListenerArn,
+		LoadBalancerSecurityGroupID: pulumi.StringRef("sg-0123456789abcdef0"),
+		Priority:                    pulumi.IntRef(10),
+		Stickiness:                  &IngressStickinessConfig{CookieDuration: pulumi.IntRef(3600)},
+		VpcID:                       "vpc-0123456789abcdef0",
+	}
+	return serviceConfig, create
+}
+
+// TestNewServiceIngress is a unit test that checks the target group, listener rule and security group rules created for a service with an ingress.
+func TestNewServiceIngress(t *testing.T) {
+	_, create := ingressServiceFixture(t)
+
+	m := create(nil)
+
+	targetGroup := m.resource(t, "aws:lb/targetGroup:TargetGroup", "my-ecs-service")
+	assert.Equal(t, componentURN(ServiceType, "my-ecs-service"), targetGroup.Parent)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"cookieDuration": 3600,
+		"enabled":        true,
+		"type":           "lb_cookie",
+	}), targetGroup.Inputs["stickiness"].ObjectValue())
+
+	rule := m.resource(t, "aws:lb/listenerRule:ListenerRule", "my-ecs-service")
+	assert.Equal(t, resource.NewStringProperty(productionListenerArn), rule.Inputs["listenerArn"])
+	assert.Equal(t, resource.NewNumberProperty(10), rule.Inputs["priority"])
+	assert.Empty(t, rule.IgnoreChanges)
+
+	ingressRule := m.resource(t, "aws:vpc/securityGroupIngressRule:SecurityGroupIngressRule", "my-ecs-service-alb-0")
+	assert.Equal(t, resource.NewStringProperty("sg-05b0afb82b145e891"), ingressRule.Inputs["securityGroupId"])
+	assert.Equal(t, resource.NewStringProperty("sg-0123456789abcdef0"), ingressRule.Inputs["referencedSecurityGroupId"])
+	assert.Equal(t, resource.NewNumberProperty(80), ingressRule.Inputs["fromPort"])
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+		"containerName":  "my-container",
+		"containerPort":  80,
+		"targetGroupArn": "arn:aws:mock:us-west-2:123456789012:my-ecs-service",
+	}))}), service.Inputs["loadBalancers"])
+	assert.Equal(t, resource.NewNumberProperty(defaultIngressHealthCheckGracePeriodSeconds), service.Inputs["healthCheckGracePeriodSeconds"])
+}
+
+// TestNewServiceFromArgsIngress is a unit test that checks that the ingress of a service accepts the ARN of a listener created in the same program.
+func TestNewServiceFromArgsIngress(t *testing.T) {
+	_, create := ingressServiceFixture(t)
+
+	m := create(func(ctx *pulumi.Context, args *ServiceArgs) error {
+		listener, err := lb.NewListener(ctx, "my-listener", &lb.ListenerArgs{
+			DefaultActions: lb.ListenerDefaultActionArray{
+				&lb.ListenerDefaultActionArgs{Type: pulumi.String("fixed-response")},
+			},
+			LoadBalancerArn: pulumi.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-alb/0123456789abcdef"),
+		})
+		if err != nil {
+			return err
+		}
+
+		args.Ingress.ListenerArn = listener.Arn
+		return nil
+	})
+
+	rule := m.resource(t, "aws:lb/listenerRule:ListenerRule", "my-ecs-service")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-listener"), rule.Inputs["listenerArn"])
+}
+
+// TestValidateServiceIngress is a unit test that checks the checks of the ingress block of a service.
+func TestValidateServiceIngress(t *testing.T) {
+	serviceConfig, _ := ingressServiceFixture(t)
+	serviceConfig.HealthCheckGracePeriodSeconds = pulumi.IntRef(30)
+	serviceConfig.NetworkConfiguration.SecurityGroups = nil
+	serviceConfig.Ingress.ListenerArn = ""
+	serviceConfig.Ingress.Stickiness = &IngressStickinessConfig{Type: pulumi.StringRef("app_cookie")}
+
+	assert.Equal(t, map[string]string{
+		"service.ingress.listenerArn":                 "is required",
+		"service.ingress.stickiness.cookieName":       "is required for app_cookie stickiness",
+		"service.networkConfiguration.securityGroups": "must contain at least one security group when ingress.loadBalancerSecurityGroupId is set",
+	}, fieldErrors(t, serviceConfig.Validate()))
+}
@@ -0,0 +1,278 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// referencePattern matches ${env:NAME}, ${config:key} and ${secret:key} references in configuration values.
+var referencePattern = regexp.MustCompile(`\$\{(env|config|secret):([^}]+)\}`)
+
+// partitionReference is replaced with the partition of the AWS provider, such as aws or aws-cn, so that ARNs work in
+// the China and GovCloud regions too. Other ${aws:...} references are IAM policy variables and are left in place.
+const partitionReference = "${aws:partition}"
+
+// interpolate returns a copy of a configuration in which ${env:NAME} and ${config:key} references are expanded.
+// ${secret:key} references, and references to stack config values that are secrets, are left in place; they are
+// resolved into Pulumi secrets by resolveReferences once the configuration has been converted into inputs.
+func interpolate[T any](ctx *pulumi.Context, config T) (T, error) {
+	var interpolated T
+	data, err := json.Marshal(config)
+	if err != nil {
+		return interpolated, fmt.Errorf("failed to interpolate config: %v", err)
+	}
+
+	expanded, err := expandReferences(ctx, string(data), jsonEscape)
+	if err != nil {
+		return interpolated, err
+	}
+
+	err = json.Unmarshal([]byte(expanded), &interpolated)
+	if err != nil {
+		return interpolated, fmt.Errorf("failed to interpolate config: %v", err)
+	}
+
+	return interpolated, nil
+}
+
+// expandReferences replaces the ${env:NAME}, ${config:key} and ${aws:partition} references in s with their values,
+// passed through escape if it is not nil. A reference to a stack config value that is a secret is rewritten to a ${secret:key}
+// reference instead, so that its value is never part of a plain string.
+func expandReferences(ctx *pulumi.Context, s string, escape func(string) string) (string, error) {
+	var err error
+	expanded := referencePattern.ReplaceAllStringFunc(s, func(reference string) string {
+		match := referencePattern.FindStringSubmatch(reference)
+		kind, key := match[1], match[2]
+
+		switch {
+		case kind == "secret":
+			return reference
+		case kind == "config" && ctx.IsConfigSecret(configKey(ctx, key)):
+			return fmt.Sprintf("${secret:%s}", key)
+		}
+
+		value, lookupErr := lookupReference(ctx, kind, key)
+		if lookupErr != nil {
+			err = lookupErr
+			return reference
+		}
+		if escape != nil {
+			value = escape(value)
+		}
+		return value
+	})
+	if err != nil || !strings.Contains(expanded, partitionReference) {
+		return expanded, err
+	}
+
+	partition, err := aws.GetPartition(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %v", partitionReference, err)
+	}
+	return strings.ReplaceAll(expanded, partitionReference, partition.Partition), nil
+}
+
+// lookupReference returns the value of an ${env:NAME} or ${config:key} reference.
+func lookupReference(ctx *pulumi.Context, kind, key string) (string, error) {
+	if kind == "env" {
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			return "", fmt.Errorf("failed to resolve ${env:%s}: environment variable %s is not set", key, key)
+		}
+		return value, nil
+	}
+
+	value, err := config.Try(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ${config:%s}: %v", key, err)
+	}
+	return value, nil
+}
+
+// resolveString resolves every reference in s. If s contains secret references, the result is a secret output.
+func resolveString(ctx *pulumi.Context, s string, escape func(string) string) (pulumi.StringInput, error) {
+	expanded, err := expandReferences(ctx, s, escape)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := referencePattern.FindAllStringSubmatchIndex(expanded, -1)
+	if len(matches) == 0 {
+		return pulumi.String(expanded), nil
+	}
+
+	var format strings.Builder
+	var args []interface{}
+	last := 0
+	for _, match := range matches {
+		format.WriteString(strings.ReplaceAll(expanded[last:match[0]], "%", "%%"))
+		format.WriteString("%s")
+
+		key := expanded[match[4]:match[5]]
+		secret, err := config.TrySecret(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve ${secret:%s}: %v", key, err)
+		}
+		if escape != nil {
+			secret = secret.ApplyT(escape).(pulumi.StringOutput)
+		}
+		args = append(args, secret)
+		last = match[1]
+	}
+	format.WriteString(strings.ReplaceAll(expanded[last:], "%", "%%"))
+
+	return pulumi.Sprintf(format.String(), args...), nil
+}
+
+// resolveReferences resolves the references in the string inputs of args, a pointer to one of the Args types,
+// in place. Inputs that contain secret references are replaced with secret outputs.
+func resolveReferences(ctx *pulumi.Context, args interface{}) error {
+	return resolveValue(ctx, reflect.ValueOf(args))
+}
+
+func resolveValue(ctx *pulumi.Context, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+			return resolveValue(ctx, v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			err := resolveValue(ctx, v.Field(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		return resolvePlainString(ctx, v)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			err := resolveValue(ctx, v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return resolveMap(ctx, v)
+	case reflect.Interface:
+		return resolveInterface(ctx, v)
+	}
+
+	return nil
+}
+
+// resolvePlainString resolves the references in a settable string. Secret references are not supported, because the
+// string cannot hold a secret output.
+func resolvePlainString(ctx *pulumi.Context, v reflect.Value) error {
+	if !v.CanSet() || !referencePattern.MatchString(v.String()) {
+		return nil
+	}
+
+	expanded, err := expandReferences(ctx, v.String(), nil)
+	if err != nil {
+		return err
+	}
+	if referencePattern.MatchString(expanded) {
+		return fmt.Errorf("secret references are not supported in %q", v.String())
+	}
+	v.SetString(expanded)
+	return nil
+}
+
+// resolveMap resolves the references in the values of a map, replacing plain string inputs with their resolved
+// inputs.
+func resolveMap(ctx *pulumi.Context, v reflect.Value) error {
+	for _, key := range v.MapKeys() {
+		value := v.MapIndex(key)
+		resolved, ok, err := resolveInput(ctx, value)
+		if err != nil {
+			return err
+		}
+		if ok {
+			v.SetMapIndex(key, resolved)
+			continue
+		}
+		err = resolveValue(ctx, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveInterface resolves the references in an interface, such as a pulumi.StringInput, replacing a plain string
+// input with its resolved input. Outputs are left alone.
+func resolveInterface(ctx *pulumi.Context, v reflect.Value) error {
+	if v.IsNil() {
+		return nil
+	}
+	if _, ok := v.Interface().(pulumi.Output); ok {
+		return nil
+	}
+	resolved, ok, err := resolveInput(ctx, v)
+	if err != nil {
+		return err
+	}
+	if ok && v.CanSet() {
+		v.Set(resolved)
+		return nil
+	}
+	return resolveValue(ctx, v.Elem())
+}
+
+// resolveInput resolves the references in v if it holds a plain string input, such as pulumi.String or
+// pulumi.StringPtr, and reports whether it did.
+func resolveInput(ctx *pulumi.Context, v reflect.Value) (reflect.Value, bool, error) {
+	if v.Kind() != reflect.Interface || v.IsNil() {
+		return reflect.Value{}, false, nil
+	}
+
+	var s string
+	switch elem := v.Elem(); {
+	case elem.Kind() == reflect.String:
+		s = elem.String()
+	case elem.Kind() == reflect.Ptr && !elem.IsNil() && elem.Elem().Kind() == reflect.String:
+		s = elem.Elem().String()
+	default:
+		return reflect.Value{}, false, nil
+	}
+	if !referencePattern.MatchString(s) {
+		return reflect.Value{}, false, nil
+	}
+
+	input, err := resolveString(ctx, s, nil)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+	resolved := reflect.ValueOf(input)
+	if !resolved.Type().AssignableTo(v.Type()) {
+		return reflect.Value{}, false, fmt.Errorf("references are not supported in %q", s)
+	}
+
+	return resolved, true, nil
+}
+
+// configKey returns the fully qualified stack config key, adding the project namespace if key has none.
+func configKey(ctx *pulumi.Context, key string) string {
+	if !strings.Contains(key, ":") {
+		return ctx.Project() + ":" + key
+	}
+	return key
+}
+
+// jsonEscape escapes s for use inside a JSON string.
+func jsonEscape(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data[1 : len(data)-1])
+}
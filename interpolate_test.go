@@ -0,0 +1,99 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// setStackConfig sets the Pulumi stack config that programs run against mocks read, marking secretKeys as secrets.
+func setStackConfig(t *testing.T, config, secretKeys string) {
+	t.Setenv(pulumi.EnvConfig, config)
+	t.Setenv(pulumi.EnvConfigSecretKeys, secretKeys)
+}
+
+// TestInterpolateService is a unit test that checks that environment variable, config and secret references in a service configuration are resolved.
+func TestInterpolateService(t *testing.T) {
+	t.Setenv("LAUNCH_TYPE", "FARGATE")
+	setStackConfig(t,
+		`{"project:clusterName": "my-cluster", "project:owner": "myteam"}`,
+		`["project:owner"]`)
+
+	serviceConfig, err := getServiceConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	serviceConfig.ClusterArn = "arn:aws:ecs:us-west-2:123456789012:cluster/${config:clusterName}"
+	serviceConfig.LaunchType = pulumi.StringRef("${env:LAUNCH_TYPE}")
+	serviceConfig.Tags = map[string]string{"Owner": "team-${config:owner}"}
+
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewService(ctx, *serviceConfig)
+		return err
+	})
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:ecs:us-west-2:123456789012:cluster/my-cluster"), service.Inputs["cluster"])
+	assert.Equal(t, resource.NewStringProperty("FARGATE"), service.Inputs["launchType"])
+
+	owner := service.Inputs["tags"].ObjectValue()["Owner"]
+	assert.True(t, owner.IsSecret())
+	assert.Equal(t, "team-myteam", owner.SecretValue().Element.StringValue())
+}
+
+// TestInterpolateTaskDefinitionSecret is a unit test that checks that a secret in a container definition is escaped and stays secret.
+func TestInterpolateTaskDefinitionSecret(t *testing.T) {
+	t.Setenv("AWS_ACCOUNT_ID", "123456789012")
+	setStackConfig(t, `{"project:apiKey": "abc\"123"}`, `["project:apiKey"]`)
+
+	taskDefinitionConfig, err := getTaskDefinitionConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	taskDefinitionConfig.ContainerDefinitions[0].Environment = []ContainerKeyValuePair{{Name: "API_KEY", Value: "${secret:apiKey}"}}
+
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewTaskDefinition(ctx, *taskDefinitionConfig)
+		return err
+	})
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-task-definition")
+	containerDefinitions := taskDefinition.Inputs["containerDefinitions"]
+	assert.True(t, containerDefinitions.IsSecret())
+	assert.JSONEq(t,
+		`[{"name":"my-container","image":"nginx","environment":[{"name":"API_KEY","value":"abc\"123"}],"portMappings":[{"containerPort":80,"hostPort":80}]}]`,
+		containerDefinitions.SecretValue().Element.StringValue())
+}
+
+// TestInterpolateMissingReference is a unit test that checks that an unset environment variable is reported before any resource is registered.
+func TestInterpolateMissingReference(t *testing.T) {
+	clusterConfig, err := getClusterConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	clusterConfig.Name = "${env:ECS_TEST_UNSET_VARIABLE}"
+
+	m := newMocks()
+	err = pulumi.RunErr(func(ctx *pulumi.Context) error {
+		_, err := NewCluster(ctx, *clusterConfig)
+		return err
+	}, pulumi.WithMocks("project", "stack", m))
+
+	assert.ErrorContains(t, err, "environment variable ECS_TEST_UNSET_VARIABLE is not set")
+	assert.Equal(t, 0, m.count(ClusterType))
+}
+
+// TestInterpolatePartition is a unit test that checks that ${aws:partition} is replaced with the partition of the provider and that IAM policy variables are left in place.
+func TestInterpolatePartition(t *testing.T) {
+	clusterConfig, err := getClusterConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	clusterConfig.Tags = map[string]string{"Bucket": "arn:${aws:partition}:s3:::my-bucket/${aws:username}"}
+
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewCluster(ctx, *clusterConfig)
+		return err
+	})
+
+	cluster := m.resource(t, "aws:ecs/cluster:Cluster", clusterConfig.Name)
+	assert.Equal(t, resource.NewStringProperty("arn:aws:s3:::my-bucket/${aws:username}"), cluster.Inputs["tags"].ObjectValue()["Bucket"])
+}
@@ -0,0 +1,180 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeConfig decodes a JSON or YAML document into v, which is typically a pointer to one of the configuration
+// types or to a Spec. Fields are matched by their JSON tags, whatever the format, and unknown fields are rejected.
+func DecodeConfig(data []byte, v interface{}) error {
+	var document interface{}
+	err := yaml.Unmarshal(data, &document)
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %v", err)
+	}
+
+	return decodeStrict(document, v)
+}
+
+// LoadConfigFile reads a JSON or YAML file into v. If key is not empty, only the value under that top-level key is
+// decoded, as in the configurations in the /examples directory, e.g. "service" or "taskDefinition".
+func LoadConfigFile(path, key string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var document interface{}
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	if key != "" {
+		root, ok := normalizeYAML(document).(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("config file %s is not an object", path)
+		}
+		document, ok = root[key]
+		if !ok {
+			return fmt.Errorf("'%s' key not found in config file %s", key, path)
+		}
+	}
+
+	err = decodeStrict(document, v)
+	if err != nil {
+		return fmt.Errorf("failed to decode config file %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// LoadStackConfig reads the structured Pulumi stack configuration value of key into v, e.g. the service object
+// under `my-project:service` in Pulumi.<stack>.yaml. Keys without a namespace are looked up in the project namespace.
+func LoadStackConfig(ctx *pulumi.Context, key string, v interface{}) error {
+	var value interface{}
+	err := config.TryObject(ctx, key, &value)
+	if err != nil {
+		return fmt.Errorf("failed to read stack config %s: %v", key, err)
+	}
+
+	err = decodeStrict(value, v)
+	if err != nil {
+		return fmt.Errorf("failed to decode stack config %s: %v", key, err)
+	}
+
+	return nil
+}
+
+// decodeStrict decodes a generic document into v through its JSON encoding, rejecting unknown fields. Numbers are
+// accepted for string fields, such as the cpu and memory of a task definition.
+func decodeStrict(document interface{}, v interface{}) error {
+	data, err := json.Marshal(stringifyNumbers(normalizeYAML(document), reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// normalizeYAML converts the maps with non-string keys that YAML produces into maps with string keys, so that the
+// document can be encoded as JSON.
+func normalizeYAML(document interface{}) interface{} {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = normalizeYAML(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			normalized[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return normalized
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeYAML(item)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// stringifyNumbers converts the numbers in document that are decoded into string fields of t into strings, since YAML
+// reads unquoted values such as `cpu: 256` as numbers.
+func stringifyNumbers(document interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return document
+	}
+
+	switch value := document.(type) {
+	case map[string]interface{}:
+		if t.Kind() == reflect.Struct {
+			stringifyFields(value, jsonFields(t))
+		} else if t.Kind() == reflect.Map {
+			for key, item := range value {
+				value[key] = stringifyNumbers(item, t.Elem())
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice {
+			for i, item := range value {
+				value[i] = stringifyNumbers(item, t.Elem())
+			}
+		}
+	case int, int64, uint64, float64:
+		if t.Kind() == reflect.String {
+			return fmt.Sprint(value)
+		}
+	}
+	return document
+}
+
+// stringifyFields applies stringifyNumbers to the values of an object, by the types of the fields they decode into.
+// Like encoding/json, keys match the field names case-insensitively.
+func stringifyFields(object map[string]interface{}, fields map[string]reflect.Type) {
+	for key, item := range object {
+		if field, ok := fields[strings.ToLower(key)]; ok {
+			object[key] = stringifyNumbers(item, field)
+		}
+	}
+}
+
+// jsonFields returns the types of the fields of a struct type by their lower case JSON names, including those of
+// embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case !field.IsExported() || name == "-":
+			continue
+		case field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct:
+			for embedded, fieldType := range jsonFields(field.Type) {
+				fields[embedded] = fieldType
+			}
+			continue
+		case name == "":
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
@@ -0,0 +1,91 @@
+package ecs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+const serviceConfigYAML = `
+service:
+  name: my-ecs-service
+  clusterArn: arn:aws:ecs:us-west-2:123456789012:cluster/my-cluster
+  taskDefinition: my-task-definition
+  desiredCount: 2
+  launchType: FARGATE
+  networkConfiguration:
+    subnets:
+      - subnet-018d8f50ed69b2301
+    assignPublicIp: true
+  tags:
+    Environment: Production
+`
+
+// TestLoadConfigFileYAML is a unit test that checks that a YAML file is decoded with the JSON tags of the configuration types.
+func TestLoadConfigFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(serviceConfigYAML), 0o600)
+	assert.NoError(t, err)
+
+	var serviceConfig ServiceConfig
+	err = LoadConfigFile(path, "service", &serviceConfig)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "my-ecs-service", serviceConfig.Name)
+	assert.Equal(t, 2, *serviceConfig.DesiredCount)
+	assert.Equal(t, []string{"subnet-018d8f50ed69b2301"}, serviceConfig.NetworkConfiguration.Subnets)
+	assert.True(t, *serviceConfig.NetworkConfiguration.AssignPublicIP)
+	assert.Equal(t, map[string]string{"Environment": "Production"}, serviceConfig.Tags)
+}
+
+// TestLoadConfigFileJSON is a unit test that checks that the JSON configurations in the /examples directory load as before.
+func TestLoadConfigFileJSON(t *testing.T) {
+	data, err := os.ReadFile("examples/TaskDefinition/config.json")
+	assert.NoError(t, err)
+	var expected struct {
+		TaskDefinition TaskDefinitionConfig `json:"taskDefinition"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &expected))
+
+	var taskDefinitionConfig TaskDefinitionConfig
+	err = LoadConfigFile("examples/TaskDefinition/config.json", "taskDefinition", &taskDefinitionConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.TaskDefinition, taskDefinitionConfig)
+}
+
+// TestDecodeConfigUnknownField is a unit test that checks that unknown fields are rejected in nested objects too.
+func TestDecodeConfigUnknownField(t *testing.T) {
+	var clusterConfig ClusterConfig
+	err := DecodeConfig([]byte("name: my-cluster\nsettings:\n  - name: containerInsights\n    valeu: enabled\n"), &clusterConfig)
+	assert.ErrorContains(t, err, `unknown field "valeu"`)
+}
+
+// TestLoadStackConfig is a unit test that checks that a configuration is read from the Pulumi stack config.
+func TestLoadStackConfig(t *testing.T) {
+	t.Setenv(pulumi.EnvConfig, `{"project:cluster": "{\"name\": \"my-cluster\", \"tags\": {\"owner\": \"myteam\"}}"}`)
+
+	var clusterConfig ClusterConfig
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		return LoadStackConfig(ctx, "cluster", &clusterConfig)
+	}, pulumi.WithMocks("project", "stack", newMocks()))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "my-cluster", clusterConfig.Name)
+	assert.Equal(t, map[string]string{"owner": "myteam"}, clusterConfig.Tags)
+}
+
+// TestDecodeConfigNumericStrings is a unit test that checks that unquoted YAML numbers are accepted for string fields, such as the cpu and memory of a task definition.
+func TestDecodeConfigNumericStrings(t *testing.T) {
+	var taskDefinitionConfig TaskDefinitionConfig
+	err := DecodeConfig([]byte("name: my-task-definition\ncpu: 256\nmemory: 512\ncontainerDefinitions:\n  - name: my-container\n    image: nginx\n    cpu: 128\n    environment:\n      - name: PORT\n        value: 8080\n"), &taskDefinitionConfig)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "256", *taskDefinitionConfig.CPU)
+	assert.Equal(t, "512", *taskDefinitionConfig.Memory)
+	assert.Equal(t, 128, *taskDefinitionConfig.ContainerDefinitions[0].CPU)
+	assert.Equal(t, "8080", taskDefinitionConfig.ContainerDefinitions[0].Environment[0].Value)
+}
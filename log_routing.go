@@ -0,0 +1,208 @@
+package ecs
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// logRouterName is the name of the FireLens log router container that is added to a task definition.
+const logRouterName = "log_router"
+
+// defaultLogRouterImage is the AWS for Fluent Bit image that the log router runs unless another image is set.
+const defaultLogRouterImage = "public.ecr.aws/aws-observability/aws-for-fluent-bit:stable"
+
+// TaskLogRoutingConfig defines a FireLens log router that ships the logs of the containers of a task definition to
+// Destination, which is one of opensearch, datadog, s3 or firehose. A Fluent Bit log_router container is added to the
+// task definition, and the containers, every one unless Containers is set, send their logs to it.
+//
+// Endpoint and Index are the host and index of an OpenSearch domain, Bucket and Prefix the bucket and key prefix of
+// S3, and DeliveryStream the name of a Firehose delivery stream; Region is the region of these destinations. For
+// Datadog, APIKey is the ARN or name of the Secrets Manager secret or Systems Manager parameter that holds the API key,
+// and Site the Datadog site, datadoghq.com by default. Options are added to the output options of every container.
+type TaskLogRoutingConfig struct {
+	APIKey            *string           `json:"apiKey,omitempty"`
+	Bucket            *string           `json:"bucket,omitempty"`
+	Containers        []string          `json:"containers,omitempty"`
+	CPU               *int              `json:"cpu,omitempty"`
+	DeliveryStream    *string           `json:"deliveryStream,omitempty"`
+	Destination       string            `json:"destination"`
+	Endpoint          *string           `json:"endpoint,omitempty"`
+	Image             *string           `json:"image,omitempty"`
+	Index             *string           `json:"index,omitempty"`
+	MemoryReservation *int              `json:"memoryReservation,omitempty"`
+	Options           map[string]string `json:"options,omitempty"`
+	Prefix            *string           `json:"prefix,omitempty"`
+	Region            *string           `json:"region,omitempty"`
+	Site              *string           `json:"site,omitempty"`
+}
+
+func (c TaskLogRoutingConfig) validate(v *validator, path string, containers []ContainerDefinition) {
+	v.oneOf(path+".destination", &c.Destination, "opensearch", "datadog", "s3", "firehose")
+
+	required := map[string]*string{}
+	switch c.Destination {
+	case "opensearch":
+		required = map[string]*string{"endpoint": c.Endpoint, "region": c.Region}
+	case "datadog":
+		required = map[string]*string{"apiKey": c.APIKey}
+	case "s3":
+		required = map[string]*string{"bucket": c.Bucket, "region": c.Region}
+	case "firehose":
+		required = map[string]*string{"deliveryStream": c.DeliveryStream, "region": c.Region}
+	}
+	for _, field := range sortedKeys(required) {
+		if required[field] == nil || *required[field] == "" {
+			v.errorf(path+"."+field, "is required for %s", c.Destination)
+		}
+	}
+	v.between(path+".cpu", c.CPU, 0, 10240)
+	v.between(path+".memoryReservation", c.MemoryReservation, 6, 120*1024)
+
+	for i, name := range c.Containers {
+		if !slices.ContainsFunc(containers, func(c ContainerDefinition) bool { return c.Name == name }) {
+			v.errorf(fmt.Sprintf("%s.containers[%d]", path, i), "unknown container %q", name)
+		}
+	}
+	for _, container := range containers {
+		if container.Name == logRouterName {
+			v.errorf(path, "requires that no container is named %q", logRouterName)
+		}
+		if container.FirelensConfiguration != nil {
+			v.errorf(path, "must not be set together with the firelensConfiguration of container %q", container.Name)
+		}
+	}
+}
+
+// apply returns the container definitions of the task definition name with the log router added and the log
+// configuration of the routed containers pointed at it.
+func (c TaskLogRoutingConfig) apply(name string, containers []ContainerDefinition) []ContainerDefinition {
+	image := defaultLogRouterImage
+	if c.Image != nil {
+		image = *c.Image
+	}
+	memoryReservation := 50
+	if c.MemoryReservation != nil {
+		memoryReservation = *c.MemoryReservation
+	}
+
+	containers = slices.Clone(containers)
+	for i, container := range containers {
+		if len(c.Containers) > 0 && !slices.Contains(c.Containers, container.Name) {
+			continue
+		}
+
+		logConfiguration := &ContainerLogConfiguration{LogDriver: "awsfirelens", Options: c.outputOptions(name, container.Name)}
+		if c.Destination == "datadog" && c.APIKey != nil {
+			logConfiguration.SecretOptions = []ContainerSecret{{Name: "apikey", ValueFrom: *c.APIKey}}
+		}
+		containers[i].LogConfiguration = logConfiguration
+
+		// The log router has to run before the container starts logging.
+		if !slices.ContainsFunc(container.DependsOn, func(d ContainerDependency) bool { return d.ContainerName == logRouterName }) {
+			containers[i].DependsOn = append(slices.Clone(container.DependsOn), ContainerDependency{Condition: "START", ContainerName: logRouterName})
+		}
+	}
+
+	return append(containers, ContainerDefinition{
+		CPU:       c.CPU,
+		Essential: pulumi.BoolRef(true),
+		FirelensConfiguration: &ContainerFirelensConfiguration{
+			Options: map[string]string{"enable-ecs-log-metadata": "true"},
+			Type:    "fluentbit",
+		},
+		Image:             image,
+		MemoryReservation: &memoryReservation,
+		Name:              logRouterName,
+		User:              pulumi.StringRef("0"),
+	})
+}
+
+// outputOptions returns the Fluent Bit output options of a container of the task definition name.
+func (c TaskLogRoutingConfig) outputOptions(name, container string) map[string]string {
+	var options map[string]string
+	switch c.Destination {
+	case "opensearch":
+		index := name
+		if c.Index != nil {
+			index = *c.Index
+		}
+		options = map[string]string{
+			"Name":               "opensearch",
+			"Host":               stringValue(c.Endpoint),
+			"Port":               "443",
+			"Index":              index,
+			"Suppress_Type_Name": "On",
+			"Trace_Error":        "On",
+			"aws_auth":           "On",
+			"aws_region":         stringValue(c.Region),
+			"tls":                "On",
+		}
+	case "datadog":
+		site := "datadoghq.com"
+		if c.Site != nil {
+			site = *c.Site
+		}
+		options = map[string]string{
+			"Name":           "datadog",
+			"Host":           "http-intake.logs." + site,
+			"TLS":            "on",
+			"compress":       "gzip",
+			"dd_message_key": "log",
+			"dd_service":     container,
+			"dd_source":      name,
+			"provider":       "ecs",
+		}
+	case "s3":
+		prefix := name
+		if c.Prefix != nil {
+			prefix = strings.Trim(*c.Prefix, "/")
+		}
+		options = map[string]string{
+			"Name":            "s3",
+			"bucket":          stringValue(c.Bucket),
+			"region":          stringValue(c.Region),
+			"s3_key_format":   "/" + prefix + "/" + container + "/%Y/%m/%d/%H/%M/%S-$UUID",
+			"total_file_size": "1M",
+			"upload_timeout":  "1m",
+			"use_put_object":  "On",
+		}
+	case "firehose":
+		options = map[string]string{
+			"Name":            "kinesis_firehose",
+			"delivery_stream": stringValue(c.DeliveryStream),
+			"region":          stringValue(c.Region),
+		}
+	}
+	if options == nil {
+		options = map[string]string{}
+	}
+	maps.Copy(options, c.Options)
+	return options
+}
+
+// taskRoleStatements returns the statements that allow the log router to write to an S3 or Firehose destination.
+// The ARN of an OpenSearch domain cannot be derived from its endpoint, so access to it has to be granted by hand. The
+// ARNs use the ${aws:partition} reference, which NewTaskDefinitionFromArgs resolves.
+func (c TaskLogRoutingConfig) taskRoleStatements() []policyStatement {
+	switch c.Destination {
+	case "s3":
+		return []policyStatement{{Sid: "LogRoutingBucket", Effect: "Allow", Action: []string{"s3:PutObject"}, Resource: []string{"arn:" + partitionReference + ":s3:::" + stringValue(c.Bucket) + "/*"}}}
+	case "firehose":
+		return []policyStatement{{Sid: "LogRoutingDeliveryStream", Effect: "Allow", Action: []string{"firehose:PutRecordBatch"}, Resource: []string{
+			fmt.Sprintf("arn:%s:firehose:%s:*:deliverystream/%s", partitionReference, stringValue(c.Region), stringValue(c.DeliveryStream)),
+		}}}
+	}
+	return nil
+}
+
+// stringValue returns the value of an optional string, or the empty string if it is not set.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
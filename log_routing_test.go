@@ -0,0 +1,104 @@
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewTaskDefinitionLogRouting is a unit test that checks that a FireLens log router is added to a task definition and that its containers send their logs to it.
+func TestNewTaskDefinitionLogRouting(t *testing.T) {
+	taskDefinitionConfig, create := taskDefinitionFixture(t)
+	taskDefinitionConfig.LogRouting = &TaskLogRoutingConfig{
+		APIKey:      pulumi.StringRef("arn:aws:secretsmanager:us-west-2:123456789012:secret:datadog-AbCdEf"),
+		Destination: "datadog",
+		Options:     map[string]string{"dd_tags": "env:prod"},
+		Site:        pulumi.StringRef("datadoghq.eu"),
+	}
+	taskDefinitionConfig.Logging = &TaskLoggingConfig{}
+
+	m := create()
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-task-definition")
+	assert.JSONEq(t, `[{
+		"name": "my-container",
+		"image": "nginx",
+		"portMappings": [{"containerPort": 80, "hostPort": 80}],
+		"dependsOn": [{"condition": "START", "containerName": "log_router"}],
+		"logConfiguration": {
+			"logDriver": "awsfirelens",
+			"options": {
+				"Name": "datadog",
+				"Host": "http-intake.logs.datadoghq.eu",
+				"TLS": "on",
+				"compress": "gzip",
+				"dd_message_key": "log",
+				"dd_service": "my-container",
+				"dd_source": "my-task-definition",
+				"dd_tags": "env:prod",
+				"provider": "ecs"
+			},
+			"secretOptions": [{"name": "apikey", "valueFrom": "arn:aws:secretsmanager:us-west-2:123456789012:secret:datadog-AbCdEf"}]
+		}
+	}, {
+		"name": "log_router",
+		"image": "public.ecr.aws/aws-observability/aws-for-fluent-bit:stable",
+		"essential": true,
+		"firelensConfiguration": {"type": "fluentbit", "options": {"enable-ecs-log-metadata": "true"}},
+		"memoryReservation": 50,
+		"user": "0",
+		"logConfiguration": {"logDriver": "awslogs", "options": {
+			"awslogs-group": "/ecs/my-task-definition",
+			"awslogs-region": "us-west-2",
+			"awslogs-stream-prefix": "my-task-definition"
+		}}
+	}]`, taskDefinition.Inputs["containerDefinitions"].StringValue())
+
+	executionPolicy := m.resource(t, "aws:iam/rolePolicy:RolePolicy", "my-task-definition-execution")
+	var document policyDocument
+	assert.NoError(t, json.Unmarshal([]byte(executionPolicy.Inputs["policy"].StringValue()), &document))
+	assert.Equal(t, []policyStatement{
+		{Sid: "Secrets", Effect: "Allow", Action: []string{"secretsmanager:GetSecretValue"}, Resource: []string{"arn:aws:secretsmanager:us-west-2:123456789012:secret:datadog-AbCdEf"}},
+	}, document.Statement)
+}
+
+// TestLogRoutingTaskRoleStatements is a unit test that checks the task role policy that lets the log router write to its destination.
+func TestLogRoutingTaskRoleStatements(t *testing.T) {
+	s3 := TaskLogRoutingConfig{Destination: "s3", Bucket: pulumi.StringRef("my-logs"), Region: pulumi.StringRef("us-west-2")}
+	assert.Equal(t, []policyStatement{
+		{Sid: "LogRoutingBucket", Effect: "Allow", Action: []string{"s3:PutObject"}, Resource: []string{"arn:${aws:partition}:s3:::my-logs/*"}},
+	}, s3.taskRoleStatements())
+	assert.Equal(t, "/my-task-definition/my-container/%Y/%m/%d/%H/%M/%S-$UUID", s3.outputOptions("my-task-definition", "my-container")["s3_key_format"])
+
+	firehose := TaskLogRoutingConfig{Destination: "firehose", DeliveryStream: pulumi.StringRef("my-stream"), Region: pulumi.StringRef("us-west-2")}
+	assert.Equal(t, []policyStatement{
+		{Sid: "LogRoutingDeliveryStream", Effect: "Allow", Action: []string{"firehose:PutRecordBatch"}, Resource: []string{"arn:${aws:partition}:firehose:us-west-2:*:deliverystream/my-stream"}},
+	}, firehose.taskRoleStatements())
+
+	assert.Empty(t, TaskLogRoutingConfig{Destination: "opensearch"}.taskRoleStatements())
+}
+
+// TestValidateTaskDefinitionLogRouting is a unit test that checks the checks of the log routing of a task definition.
+func TestValidateTaskDefinitionLogRouting(t *testing.T) {
+	taskDefinitionConfig, _ := taskDefinitionFixture(t)
+	taskDefinitionConfig.ContainerDefinitions[0].FirelensConfiguration = &ContainerFirelensConfiguration{Type: "fluentbit"}
+	taskDefinitionConfig.LogRouting = &TaskLogRoutingConfig{
+		Containers:  []string{"my-sidecar"},
+		Destination: "firehose",
+		Region:      pulumi.StringRef("us-west-2"),
+	}
+
+	assert.Equal(t, map[string]string{
+		"taskDefinition.logRouting":                `must not be set together with the firelensConfiguration of container "my-container"`,
+		"taskDefinition.logRouting.containers[0]":  `unknown container "my-sidecar"`,
+		"taskDefinition.logRouting.deliveryStream": "is required for firehose",
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+
+	taskDefinitionConfig.ContainerDefinitions[0].FirelensConfiguration = nil
+	taskDefinitionConfig.LogRouting = &TaskLogRoutingConfig{Destination: "splunk"}
+	assert.Equal(t, map[string]string{
+		"taskDefinition.logRouting.destination": `must be one of opensearch, datadog, s3, firehose, got "splunk"`,
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+}
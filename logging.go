@@ -0,0 +1,219 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// TaskLoggingConfig defines the CloudWatch log groups that NewTaskDefinition creates for the containers of a task
+// definition that do not set their own logConfiguration. Mode is task (the default), which creates a single log
+// group /ecs/<name>, or container, which creates a log group /ecs/<name>/<container> per container. Unless set, logs
+// are kept for 30 days and encrypted with a key owned by CloudWatch Logs.
+type TaskLoggingConfig struct {
+	KmsKeyArn           *string                       `json:"kmsKeyArn,omitempty"`
+	Mode                *string                       `json:"mode,omitempty"`
+	RetentionInDays     *int                          `json:"retentionInDays,omitempty"`
+	SubscriptionFilters []LogSubscriptionFilterConfig `json:"subscriptionFilters,omitempty"`
+}
+
+// LogSubscriptionFilterConfig defines a subscription filter that streams the log events of a log group that match
+// FilterPattern, or every event if it is not set, to a Kinesis stream, Firehose delivery stream or Lambda function.
+// RoleArn is the role that CloudWatch Logs assumes to write to a Kinesis or Firehose destination.
+type LogSubscriptionFilterConfig struct {
+	DestinationArn string  `json:"destinationArn"`
+	Distribution   *string `json:"distribution,omitempty"`
+	FilterPattern  *string `json:"filterPattern,omitempty"`
+	Name           string  `json:"name"`
+	RoleArn        *string `json:"roleArn,omitempty"`
+}
+
+// TaskLoggingArgs defines inputs for the log groups of a task definition. Containers lists the containers whose logs
+// are sent to the log groups.
+type TaskLoggingArgs struct {
+	Containers          []string
+	KmsKeyArn           pulumi.StringPtrInput
+	PerContainer        bool
+	RetentionInDays     pulumi.IntPtrInput
+	SubscriptionFilters []LogSubscriptionFilterConfig
+}
+
+func (c TaskLoggingConfig) validate(v *validator, path string) {
+	v.oneOf(path+".mode", c.Mode, "task", "container")
+	if c.KmsKeyArn != nil && !strings.HasPrefix(*c.KmsKeyArn, "arn:") {
+		v.errorf(path+".kmsKeyArn", "must be an ARN, got %q", *c.KmsKeyArn)
+	}
+	if c.RetentionInDays != nil && !slices.Contains(logRetentionDays, *c.RetentionInDays) {
+		v.errorf(path+".retentionInDays", "must be a retention period supported by CloudWatch Logs, e.g. 30, got %d", *c.RetentionInDays)
+	}
+
+	var names []string
+	for i, filter := range c.SubscriptionFilters {
+		filterPath := fmt.Sprintf("%s.subscriptionFilters[%d]", path, i)
+		v.required(filterPath+".name", filter.Name)
+		v.required(filterPath+".destinationArn", filter.DestinationArn)
+		v.oneOf(filterPath+".distribution", filter.Distribution, "ByLogStream", "Random")
+		if filter.Name != "" && slices.Contains(names, filter.Name) {
+			v.errorf(filterPath+".name", "duplicate subscription filter %q", filter.Name)
+		}
+		names = append(names, filter.Name)
+	}
+	// CloudWatch Logs allows two subscription filters per log group.
+	if len(c.SubscriptionFilters) > 2 {
+		v.errorf(path+".subscriptionFilters", "must not contain more than 2 subscription filters, got %d", len(c.SubscriptionFilters))
+	}
+}
+
+// toArgs converts the logging configuration into inputs for the given containers, leaving out those that set their
+// own log configuration.
+func (c TaskLoggingConfig) toArgs(containers []ContainerDefinition) *TaskLoggingArgs {
+	retentionInDays := 30
+	if c.RetentionInDays != nil {
+		retentionInDays = *c.RetentionInDays
+	}
+
+	args := &TaskLoggingArgs{
+		KmsKeyArn:           pulumi.StringPtrFromPtr(c.KmsKeyArn),
+		PerContainer:        c.Mode != nil && *c.Mode == "container",
+		RetentionInDays:     pulumi.Int(retentionInDays),
+		SubscriptionFilters: c.SubscriptionFilters,
+	}
+	for _, container := range containers {
+		if container.LogConfiguration == nil {
+			args.Containers = append(args.Containers, container.Name)
+		}
+	}
+	return args
+}
+
+// taskLogGroups holds the log groups of a task definition by name.
+type taskLogGroups struct {
+	args   *TaskLoggingArgs
+	groups map[string]*cloudwatch.LogGroup
+	name   string
+	region string
+}
+
+// newTaskLogGroups creates the log groups of a task definition, and their subscription filters.
+func newTaskLogGroups(ctx *pulumi.Context, name string, args *TaskLoggingArgs, tags pulumi.StringMapInput, parent pulumi.Resource) (*taskLogGroups, error) {
+	region, err := aws.GetRegion(ctx, nil, pulumi.Parent(parent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get region: %v", err)
+	}
+
+	logGroups := &taskLogGroups{args: args, groups: map[string]*cloudwatch.LogGroup{}, name: name, region: region.Name}
+	containers := []string{""}
+	if args.PerContainer {
+		containers = args.Containers
+	}
+	for _, container := range containers {
+		resourceName := name + "-logs"
+		if container != "" {
+			resourceName += "-" + container
+		}
+
+		groupName := logGroups.groupName(container)
+		logGroup, err := cloudwatch.NewLogGroup(ctx, resourceName, &cloudwatch.LogGroupArgs{
+			KmsKeyId:        args.KmsKeyArn,
+			Name:            pulumi.String(groupName),
+			RetentionInDays: args.RetentionInDays,
+			Tags:            tags,
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new log group: %v", err)
+		}
+		logGroups.groups[groupName] = logGroup
+
+		for _, filter := range args.SubscriptionFilters {
+			filterPattern := ""
+			if filter.FilterPattern != nil {
+				filterPattern = *filter.FilterPattern
+			}
+			_, err = cloudwatch.NewLogSubscriptionFilter(ctx, resourceName+"-"+filter.Name, &cloudwatch.LogSubscriptionFilterArgs{
+				DestinationArn: pulumi.String(filter.DestinationArn),
+				Distribution:   pulumi.StringPtrFromPtr(filter.Distribution),
+				FilterPattern:  pulumi.String(filterPattern),
+				LogGroup:       logGroup.Name,
+				Name:           pulumi.String(filter.Name),
+				RoleArn:        pulumi.StringPtrFromPtr(filter.RoleArn),
+			}, pulumi.Parent(parent))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create new log subscription filter: %v", err)
+			}
+		}
+	}
+	return logGroups, nil
+}
+
+// groupName returns the name of the log group of a container, or of the whole task if container is empty.
+func (l *taskLogGroups) groupName(container string) string {
+	if container == "" || !l.args.PerContainer {
+		return "/ecs/" + l.name
+	}
+	return "/ecs/" + l.name + "/" + container
+}
+
+// inject returns the JSON encoded container definitions with an awslogs log configuration that writes to the log
+// groups added to the containers that do not set their own.
+func (l *taskLogGroups) inject(containerDefinitions pulumi.StringInput) pulumi.StringOutput {
+	return containerDefinitions.ToStringOutput().ApplyT(func(definitions string) (string, error) {
+		var containers []ContainerDefinition
+		err := json.Unmarshal([]byte(definitions), &containers)
+		if err != nil {
+			return "", fmt.Errorf("could not unmarshal container definitions json: %v", err)
+		}
+
+		for i, container := range containers {
+			if container.LogConfiguration != nil || !slices.Contains(l.args.Containers, container.Name) {
+				continue
+			}
+			containers[i].LogConfiguration = &ContainerLogConfiguration{
+				LogDriver: "awslogs",
+				Options: map[string]string{
+					"awslogs-group":         l.groupName(container.Name),
+					"awslogs-region":        l.region,
+					"awslogs-stream-prefix": l.name,
+				},
+			}
+		}
+
+		data, err := json.Marshal(containers)
+		if err != nil {
+			return "", fmt.Errorf("could not marshal container definitions json: %v", err)
+		}
+		return string(data), nil
+	}).(pulumi.StringOutput)
+}
+
+// newPolicy attaches a policy to an execution role that allows it to write to the log groups.
+func (l *taskLogGroups) newPolicy(ctx *pulumi.Context, name string, role *iam.Role, parent pulumi.Resource) (*iam.RolePolicy, error) {
+	var arns pulumi.StringArray
+	for _, groupName := range sortedKeys(l.groups) {
+		arns = append(arns, pulumi.Sprintf("%s:log-stream:*", l.groups[groupName].Arn))
+	}
+
+	policy := arns.ToStringArrayOutput().ApplyT(func(resources []string) (string, error) {
+		data, err := json.Marshal(policyDocument{Version: "2012-10-17", Statement: []policyStatement{
+			{Sid: "TaskLogStreams", Effect: "Allow", Action: []string{"logs:CreateLogStream", "logs:PutLogEvents"}, Resource: resources},
+		}})
+		if err != nil {
+			return "", fmt.Errorf("could not marshal role policy json: %v", err)
+		}
+		return string(data), nil
+	}).(pulumi.StringOutput)
+
+	rolePolicy, err := iam.NewRolePolicy(ctx, name, &iam.RolePolicyArgs{
+		Policy: policy,
+		Role:   role.Name,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new role policy: %v", err)
+	}
+	return rolePolicy, nil
+}
@@ -0,0 +1,90 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewTaskDefinitionLogging is a unit test that checks that log groups are created for the containers of a task definition without their own log configuration, and that the containers are sent to them.
+func TestNewTaskDefinitionLogging(t *testing.T) {
+	taskDefinitionConfig, create := taskDefinitionFixture(t)
+	taskDefinitionConfig.ContainerDefinitions = append(taskDefinitionConfig.ContainerDefinitions, ContainerDefinition{
+		Name:  "my-sidecar",
+		Image: "busybox",
+		LogConfiguration: &ContainerLogConfiguration{
+			LogDriver: "json-file",
+		},
+	})
+	taskDefinitionConfig.Logging = &TaskLoggingConfig{
+		KmsKeyArn:       pulumi.StringRef("arn:aws:kms:us-west-2:123456789012:key/my-key"),
+		Mode:            pulumi.StringRef("container"),
+		RetentionInDays: pulumi.IntRef(90),
+		SubscriptionFilters: []LogSubscriptionFilterConfig{
+			{Name: "errors", DestinationArn: "arn:aws:lambda:us-west-2:123456789012:function:my-function", FilterPattern: pulumi.StringRef("ERROR")},
+		},
+	}
+
+	m := create()
+
+	assert.Equal(t, 1, m.count("aws:cloudwatch/logGroup:LogGroup"))
+	logGroup := m.resource(t, "aws:cloudwatch/logGroup:LogGroup", "my-task-definition-logs-my-container")
+	assert.Equal(t, resource.NewStringProperty("/ecs/my-task-definition/my-container"), logGroup.Inputs["name"])
+	assert.Equal(t, resource.NewNumberProperty(90), logGroup.Inputs["retentionInDays"])
+	assert.Equal(t, resource.NewStringProperty("arn:aws:kms:us-west-2:123456789012:key/my-key"), logGroup.Inputs["kmsKeyId"])
+
+	filter := m.resource(t, "aws:cloudwatch/logSubscriptionFilter:LogSubscriptionFilter", "my-task-definition-logs-my-container-errors")
+	assert.Equal(t, resource.NewStringProperty("ERROR"), filter.Inputs["filterPattern"])
+	assert.Equal(t, resource.NewStringProperty("/ecs/my-task-definition/my-container"), filter.Inputs["logGroup"])
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-task-definition")
+	assert.JSONEq(t, `[{
+		"name": "my-container",
+		"image": "nginx",
+		"portMappings": [{"containerPort": 80, "hostPort": 80}],
+		"logConfiguration": {"logDriver": "awslogs", "options": {
+			"awslogs-group": "/ecs/my-task-definition/my-container",
+			"awslogs-region": "us-west-2",
+			"awslogs-stream-prefix": "my-task-definition"
+		}}
+	}, {
+		"name": "my-sidecar",
+		"image": "busybox",
+		"logConfiguration": {"logDriver": "json-file"}
+	}]`, taskDefinition.Inputs["containerDefinitions"].StringValue())
+
+	policy := m.resource(t, "aws:iam/rolePolicy:RolePolicy", "my-task-definition-execution-logs")
+	assert.JSONEq(t, `{
+		"Version": "2012-10-17",
+		"Statement": [{"Sid": "TaskLogStreams", "Effect": "Allow", "Action": ["logs:CreateLogStream", "logs:PutLogEvents"], "Resource": [
+			"arn:aws:mock:us-west-2:123456789012:my-task-definition-logs-my-container:log-stream:*"
+		]}]
+	}`, policy.Inputs["policy"].StringValue())
+}
+
+// TestValidateTaskDefinitionLogging is a unit test that checks the checks of the log groups created for a task definition.
+func TestValidateTaskDefinitionLogging(t *testing.T) {
+	taskDefinitionConfig, _ := taskDefinitionFixture(t)
+	taskDefinitionConfig.ExecutionRole = nil
+	taskDefinitionConfig.Logging = &TaskLoggingConfig{
+		KmsKeyArn:       pulumi.StringRef("my-key"),
+		Mode:            pulumi.StringRef("service"),
+		RetentionInDays: pulumi.IntRef(42),
+		SubscriptionFilters: []LogSubscriptionFilterConfig{
+			{Name: "errors", DestinationArn: "arn:aws:lambda:us-west-2:123456789012:function:my-function"},
+			{Name: "errors", Distribution: pulumi.StringRef("random")},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"taskDefinition.logging":                                       "requires an executionRole or executionRoleArn",
+		"taskDefinition.logging.kmsKeyArn":                             `must be an ARN, got "my-key"`,
+		"taskDefinition.logging.mode":                                  `must be one of task, container, got "service"`,
+		"taskDefinition.logging.retentionInDays":                       "must be a retention period supported by CloudWatch Logs, e.g. 30, got 42",
+		"taskDefinition.logging.subscriptionFilters[1].destinationArn": "is required",
+		"taskDefinition.logging.subscriptionFilters[1].distribution":   `must be one of ByLogStream, Random, got "random"`,
+		"taskDefinition.logging.subscriptionFilters[1].name":           `duplicate subscription filter "errors"`,
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+}
@@ -0,0 +1,147 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// collectorName is the name of the AWS Distro for OpenTelemetry collector container that is added to a task
+// definition.
+const collectorName = "aws-otel-collector"
+
+// defaultCollectorImage is the AWS Distro for OpenTelemetry collector image that runs unless another image is set.
+const defaultCollectorImage = "public.ecr.aws/aws-observability/aws-otel-collector:latest"
+
+// defaultCollectorConfig is the collector configuration shipped with the image, which receives OTLP and X-Ray traces
+// and metrics and sends them to X-Ray and CloudWatch.
+const defaultCollectorConfig = "/etc/ecs/ecs-default-config.yaml"
+
+// TaskObservabilityConfig defines an AWS Distro for OpenTelemetry collector sidecar that receives the traces and
+// metrics of the containers of a task definition, every one unless Containers is set. The collector configuration is
+// Config, inline YAML, or the Systems Manager parameter ConfigParameter, by ARN or name; without either, the
+// configuration shipped with the image sends traces to X-Ray and metrics to CloudWatch.
+type TaskObservabilityConfig struct {
+	Config            *string  `json:"config,omitempty"`
+	ConfigParameter   *string  `json:"configParameter,omitempty"`
+	Containers        []string `json:"containers,omitempty"`
+	CPU               *int     `json:"cpu,omitempty"`
+	Image             *string  `json:"image,omitempty"`
+	MemoryReservation *int     `json:"memoryReservation,omitempty"`
+}
+
+func (c TaskObservabilityConfig) validate(v *validator, path string, containers []ContainerDefinition) {
+	if c.Config != nil && c.ConfigParameter != nil {
+		v.errorf(path+".configParameter", "must not be set together with config")
+	}
+	v.between(path+".cpu", c.CPU, 0, 10240)
+	v.between(path+".memoryReservation", c.MemoryReservation, 6, 120*1024)
+
+	for i, name := range c.Containers {
+		if !slices.ContainsFunc(containers, func(c ContainerDefinition) bool { return c.Name == name }) {
+			v.errorf(fmt.Sprintf("%s.containers[%d]", path, i), "unknown container %q", name)
+		}
+	}
+	if slices.ContainsFunc(containers, func(c ContainerDefinition) bool { return c.Name == collectorName }) {
+		v.errorf(path, "requires that no container is named %q", collectorName)
+	}
+}
+
+// apply returns the container definitions with the collector added and the instrumented containers pointed at it. In
+// bridge network mode the containers reach the collector through a link instead of localhost.
+func (c TaskObservabilityConfig) apply(containers []ContainerDefinition, networkMode *string) []ContainerDefinition {
+	bridge := networkMode == nil || *networkMode == "bridge"
+	containers = slices.Clone(containers)
+	for i, container := range containers {
+		if len(c.Containers) > 0 && !slices.Contains(c.Containers, container.Name) {
+			continue
+		}
+		containers[i] = instrumentContainer(container, bridge)
+	}
+	return append(containers, c.collector())
+}
+
+// instrumentContainer returns the container definition with the environment variables that point its X-Ray and
+// OpenTelemetry SDKs at the collector, and a dependency on the collector.
+func instrumentContainer(container ContainerDefinition, bridge bool) ContainerDefinition {
+	host := "localhost"
+	if bridge {
+		host = collectorName
+	}
+
+	environment := slices.Clone(container.Environment)
+	for _, variable := range []ContainerKeyValuePair{
+		{Name: "AWS_XRAY_DAEMON_ADDRESS", Value: host + ":2000"},
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://" + host + ":4317"},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "grpc"},
+		{Name: "OTEL_SERVICE_NAME", Value: container.Name},
+	} {
+		// Variables that the container sets itself take precedence.
+		if !slices.ContainsFunc(environment, func(e ContainerKeyValuePair) bool { return e.Name == variable.Name }) {
+			environment = append(environment, variable)
+		}
+	}
+
+	instrumented := container
+	instrumented.Environment = environment
+	if !slices.ContainsFunc(container.DependsOn, func(d ContainerDependency) bool { return d.ContainerName == collectorName }) {
+		instrumented.DependsOn = append(slices.Clone(container.DependsOn), ContainerDependency{Condition: "START", ContainerName: collectorName})
+	}
+	if bridge && !slices.Contains(container.Links, collectorName) {
+		instrumented.Links = append(slices.Clone(container.Links), collectorName)
+	}
+	return instrumented
+}
+
+// collector returns the container definition of the collector.
+func (c TaskObservabilityConfig) collector() ContainerDefinition {
+	image := defaultCollectorImage
+	if c.Image != nil {
+		image = *c.Image
+	}
+	memoryReservation := 64
+	if c.MemoryReservation != nil {
+		memoryReservation = *c.MemoryReservation
+	}
+
+	collector := ContainerDefinition{
+		CPU:               c.CPU,
+		Essential:         pulumi.BoolRef(true),
+		Image:             image,
+		MemoryReservation: &memoryReservation,
+		Name:              collectorName,
+	}
+	switch {
+	case c.Config != nil:
+		collector.Environment = []ContainerKeyValuePair{{Name: "AOT_CONFIG_CONTENT", Value: *c.Config}}
+	case c.ConfigParameter != nil:
+		collector.Secrets = []ContainerSecret{{Name: "AOT_CONFIG_CONTENT", ValueFrom: *c.ConfigParameter}}
+	default:
+		collector.Command = []string{"--config=" + defaultCollectorConfig}
+	}
+	return collector
+}
+
+// taskRoleStatements returns the statements that allow the collector to send traces to X-Ray and metrics to
+// CloudWatch, like the AWSDistroOpenTelemetryPolicy managed policy. None of these actions can be limited to the
+// resources of the task, because the collector configuration decides where the data goes.
+func (c TaskObservabilityConfig) taskRoleStatements() []policyStatement {
+	return []policyStatement{
+		{Sid: "ObservabilityTraces", Effect: "Allow", Action: []string{
+			"xray:GetSamplingRules",
+			"xray:GetSamplingStatisticSummaries",
+			"xray:GetSamplingTargets",
+			"xray:PutTelemetryRecords",
+			"xray:PutTraceSegments",
+		}, Resource: []string{"*"}},
+		{Sid: "ObservabilityMetrics", Effect: "Allow", Action: []string{"cloudwatch:PutMetricData"}, Resource: []string{"*"}},
+		{Sid: "ObservabilityLogs", Effect: "Allow", Action: []string{
+			"logs:CreateLogGroup",
+			"logs:CreateLogStream",
+			"logs:DescribeLogGroups",
+			"logs:DescribeLogStreams",
+			"logs:PutLogEvents",
+		}, Resource: []string{"*"}},
+	}
+}
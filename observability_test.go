@@ -0,0 +1,89 @@
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewTaskDefinitionObservability is a unit test that checks that an OpenTelemetry collector is added to a task definition, that its containers are pointed at it and that the task role may send traces and metrics.
+func TestNewTaskDefinitionObservability(t *testing.T) {
+	taskDefinitionConfig, create := taskDefinitionFixture(t)
+	taskDefinitionConfig.NetworkMode = pulumi.StringRef("awsvpc")
+	taskDefinitionConfig.TaskRoleArn = nil
+	taskDefinitionConfig.TaskRole = &TaskDefinitionRoleConfig{}
+	taskDefinitionConfig.ContainerDefinitions[0].Environment = []ContainerKeyValuePair{{Name: "OTEL_SERVICE_NAME", Value: "my-app"}}
+	taskDefinitionConfig.Observability = &TaskObservabilityConfig{ConfigParameter: pulumi.StringRef("/my-app/otel-config")}
+
+	m := create()
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-task-definition")
+	assert.JSONEq(t, `[{
+		"name": "my-container",
+		"image": "nginx",
+		"portMappings": [{"containerPort": 80, "hostPort": 80}],
+		"dependsOn": [{"condition": "START", "containerName": "aws-otel-collector"}],
+		"environment": [
+			{"name": "OTEL_SERVICE_NAME", "value": "my-app"},
+			{"name": "AWS_XRAY_DAEMON_ADDRESS", "value": "localhost:2000"},
+			{"name": "OTEL_EXPORTER_OTLP_ENDPOINT", "value": "http://localhost:4317"},
+			{"name": "OTEL_EXPORTER_OTLP_PROTOCOL", "value": "grpc"}
+		]
+	}, {
+		"name": "aws-otel-collector",
+		"image": "public.ecr.aws/aws-observability/aws-otel-collector:latest",
+		"essential": true,
+		"memoryReservation": 64,
+		"secrets": [{"name": "AOT_CONFIG_CONTENT", "valueFrom": "/my-app/otel-config"}]
+	}]`, taskDefinition.Inputs["containerDefinitions"].StringValue())
+
+	executionPolicy := m.resource(t, "aws:iam/rolePolicy:RolePolicy", "my-task-definition-execution")
+	assert.JSONEq(t, `{
+		"Version": "2012-10-17",
+		"Statement": [{"Sid": "Parameters", "Effect": "Allow", "Action": ["ssm:GetParameters"], "Resource": ["arn:aws:ssm:*:*:parameter/my-app/otel-config"]}]
+	}`, executionPolicy.Inputs["policy"].StringValue())
+
+	taskPolicy := m.resource(t, "aws:iam/rolePolicy:RolePolicy", "my-task-definition-task")
+	var document policyDocument
+	assert.NoError(t, json.Unmarshal([]byte(taskPolicy.Inputs["policy"].StringValue()), &document))
+	assert.Equal(t, []string{"ObservabilityTraces", "ObservabilityMetrics", "ObservabilityLogs"}, []string{
+		document.Statement[0].Sid, document.Statement[1].Sid, document.Statement[2].Sid,
+	})
+}
+
+// TestObservabilityBridgeNetworkMode is a unit test that checks that containers in bridge network mode reach the collector through a link.
+func TestObservabilityBridgeNetworkMode(t *testing.T) {
+	containers := TaskObservabilityConfig{Containers: []string{"my-app"}}.apply([]ContainerDefinition{
+		{Name: "my-app", Image: "my-app"},
+		{Name: "my-proxy", Image: "envoy"},
+	}, nil)
+
+	assert.Len(t, containers, 3)
+	assert.Equal(t, []string{"aws-otel-collector"}, containers[0].Links)
+	assert.Contains(t, containers[0].Environment, ContainerKeyValuePair{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://aws-otel-collector:4317"})
+	assert.Empty(t, containers[1].Environment)
+	assert.Equal(t, []string{"--config=/etc/ecs/ecs-default-config.yaml"}, containers[2].Command)
+}
+
+// TestValidateTaskDefinitionObservability is a unit test that checks the checks of the collector of a task definition.
+func TestValidateTaskDefinitionObservability(t *testing.T) {
+	taskDefinitionConfig, _ := taskDefinitionFixture(t)
+	taskDefinitionConfig.Observability = &TaskObservabilityConfig{
+		Config:          pulumi.StringRef("receivers: {}"),
+		ConfigParameter: pulumi.StringRef("/my-app/otel-config"),
+		Containers:      []string{"my-sidecar"},
+	}
+
+	assert.Equal(t, map[string]string{
+		"taskDefinition.observability.configParameter": "must not be set together with config",
+		"taskDefinition.observability.containers[0]":   `unknown container "my-sidecar"`,
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+
+	taskDefinitionConfig.ExecutionRole = nil
+	taskDefinitionConfig.Observability = &TaskObservabilityConfig{ConfigParameter: pulumi.StringRef("/my-app/otel-config")}
+	assert.Equal(t, map[string]string{
+		"taskDefinition.observability.configParameter": "requires an executionRole or executionRoleArn",
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+}
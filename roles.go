@@ -0,0 +1,260 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/iam"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// TaskDefinitionRoleConfig defines an IAM role that NewTaskDefinition creates for the task definition. The
+// statements are attached as an inline policy and the managed policies by ARN.
+type TaskDefinitionRoleConfig struct {
+	ManagedPolicyArns []string                `json:"managedPolicyArns,omitempty"`
+	Statements        []PolicyStatementConfig `json:"statements,omitempty"`
+}
+
+// PolicyStatementConfig defines a statement of an IAM policy. Effect defaults to Allow.
+type PolicyStatementConfig struct {
+	Actions   []string                       `json:"actions"`
+	Condition map[string]map[string][]string `json:"condition,omitempty"`
+	Effect    *string                        `json:"effect,omitempty"`
+	Resources []string                       `json:"resources"`
+	Sid       *string                        `json:"sid,omitempty"`
+}
+
+// TaskDefinitionRoleArgs defines inputs for an IAM role that NewTaskDefinitionFromArgs creates for the task
+// definition.
+type TaskDefinitionRoleArgs struct {
+	ManagedPolicyArns []string
+	// Policy is a JSON encoded IAM policy document that is attached to the role inline, if set.
+	Policy pulumi.StringInput
+}
+
+// policyDocument is the JSON representation of an IAM policy.
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+// policyStatement is the JSON representation of a statement of an IAM policy.
+type policyStatement struct {
+	Sid       string                         `json:"Sid,omitempty"`
+	Effect    string                         `json:"Effect"`
+	Action    []string                       `json:"Action"`
+	Resource  []string                       `json:"Resource"`
+	Condition map[string]map[string][]string `json:"Condition,omitempty"`
+}
+
+// ecrImagePattern matches images in a private ECR repository and captures the account, region and repository name.
+var ecrImagePattern = regexp.MustCompile(`^(\d{12})\.dkr\.ecr\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?/([^:@]+)`)
+
+func (c TaskDefinitionRoleConfig) validate(v *validator, path string) {
+	for i, statement := range c.Statements {
+		statementPath := fmt.Sprintf("%s.statements[%d]", path, i)
+		if len(statement.Actions) == 0 {
+			v.errorf(statementPath+".actions", "must contain at least one action")
+		}
+		if len(statement.Resources) == 0 {
+			v.errorf(statementPath+".resources", "must contain at least one resource")
+		}
+		v.oneOf(statementPath+".effect", statement.Effect, "Allow", "Deny")
+	}
+	for i, policyArn := range c.ManagedPolicyArns {
+		if !strings.HasPrefix(policyArn, "arn:") {
+			v.errorf(fmt.Sprintf("%s.managedPolicyArns[%d]", path, i), "must be an ARN, got %q", policyArn)
+		}
+	}
+}
+
+// toArgs converts the role configuration into inputs, adding the given statements in front of the configured ones.
+func (c TaskDefinitionRoleConfig) toArgs(statements []policyStatement) (*TaskDefinitionRoleArgs, error) {
+	for _, statement := range c.Statements {
+		effect := "Allow"
+		if statement.Effect != nil {
+			effect = *statement.Effect
+		}
+		var sid string
+		if statement.Sid != nil {
+			sid = *statement.Sid
+		}
+		statements = append(statements, policyStatement{
+			Sid:       sid,
+			Effect:    effect,
+			Action:    statement.Actions,
+			Resource:  statement.Resources,
+			Condition: statement.Condition,
+		})
+	}
+
+	args := &TaskDefinitionRoleArgs{ManagedPolicyArns: c.ManagedPolicyArns}
+	if len(statements) == 0 {
+		return args, nil
+	}
+
+	policy, err := json.Marshal(policyDocument{Version: "2012-10-17", Statement: statements})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal role policy json: %v", err)
+	}
+	args.Policy = pulumi.String(policy)
+	return args, nil
+}
+
+// executionRoleStatements returns the statements that allow ECS to start the given containers and nothing more: to
+// pull their images from ECR and with their repository credentials, to write to their awslogs log groups, and to
+// read their secrets and environment files. Secrets referenced by the name of a parameter live in the region and
+// account of the task, which are not known here, so they are matched in any region and account. ARNs that are not
+// derived from another ARN use the ${aws:partition} reference, which NewTaskDefinitionFromArgs resolves.
+func executionRoleStatements(containers []ContainerDefinition) []policyStatement {
+	r := executionRoleResources{
+		buckets:         map[string]bool{},
+		ecrRepositories: map[string]bool{},
+		logGroups:       map[string]bool{},
+		logStreams:      map[string]bool{},
+		objects:         map[string]bool{},
+		parameters:      map[string]bool{},
+		secrets:         map[string]bool{},
+	}
+	for _, container := range containers {
+		r.addImage(container.Image)
+		if container.RepositoryCredentials != nil {
+			r.addSecret(container.RepositoryCredentials.CredentialsParameter)
+		}
+		for _, secret := range container.Secrets {
+			r.addSecret(secret.ValueFrom)
+		}
+		for _, environmentFile := range container.EnvironmentFiles {
+			r.addEnvironmentFile(environmentFile.Value)
+		}
+		if container.LogConfiguration != nil {
+			r.addLogConfiguration(*container.LogConfiguration)
+		}
+	}
+	return r.statements()
+}
+
+// executionRoleResources collects the resources that the execution role needs access to, by kind.
+type executionRoleResources struct {
+	buckets         map[string]bool
+	ecrRepositories map[string]bool
+	logGroups       map[string]bool
+	logStreams      map[string]bool
+	objects         map[string]bool
+	parameters      map[string]bool
+	secrets         map[string]bool
+}
+
+// addImage adds the repository of an image, if it is in a private ECR repository.
+func (r executionRoleResources) addImage(image string) {
+	match := ecrImagePattern.FindStringSubmatch(image)
+	if match == nil {
+		return
+	}
+	r.ecrRepositories[fmt.Sprintf("arn:%s:ecr:%s:%s:repository/%s", partitionReference, match[2], match[1], match[3])] = true
+}
+
+// addSecret adds the Secrets Manager secret or Systems Manager parameter that valueFrom references.
+func (r executionRoleResources) addSecret(valueFrom string) {
+	parts := strings.Split(valueFrom, ":")
+	switch {
+	case !strings.HasPrefix(valueFrom, "arn:"):
+		r.parameters["arn:"+partitionReference+":ssm:*:*:parameter/"+strings.TrimPrefix(valueFrom, "/")] = true
+	case len(parts) >= 7 && parts[2] == "secretsmanager":
+		// The ARN of a secret may be followed by a JSON key, version stage and version ID.
+		r.secrets[strings.Join(parts[:7], ":")] = true
+	case len(parts) >= 6 && parts[2] == "ssm":
+		r.parameters[valueFrom] = true
+	}
+}
+
+// addEnvironmentFile adds an environment file in S3 and its bucket, in the partition of the ARN of the file.
+func (r executionRoleResources) addEnvironmentFile(value string) {
+	parts := strings.SplitN(value, ":", 6)
+	if len(parts) < 6 || parts[0] != "arn" || parts[2] != "s3" {
+		return
+	}
+	bucket, _, _ := strings.Cut(parts[5], "/")
+	r.objects[value] = true
+	r.buckets[strings.Join(append(parts[:5:5], bucket), ":")] = true
+}
+
+// addLogConfiguration adds the secret options of a log configuration and, for the awslogs driver, its log group.
+func (r executionRoleResources) addLogConfiguration(logConfiguration ContainerLogConfiguration) {
+	for _, secret := range logConfiguration.SecretOptions {
+		r.addSecret(secret.ValueFrom)
+	}
+	group, ok := logConfiguration.Options["awslogs-group"]
+	if logConfiguration.LogDriver != "awslogs" || !ok {
+		return
+	}
+	region := logConfiguration.Options["awslogs-region"]
+	if region == "" {
+		region = "*"
+	}
+	logGroup := fmt.Sprintf("arn:%s:logs:%s:*:log-group:%s", partitionReference, region, group)
+	r.logStreams[logGroup+":log-stream:*"] = true
+	if logConfiguration.Options["awslogs-create-group"] == "true" {
+		r.logGroups[logGroup+":*"] = true
+	}
+}
+
+// statements returns a statement for every kind of resource that was added.
+func (r executionRoleResources) statements() []policyStatement {
+	var statements []policyStatement
+	add := func(sid string, resources map[string]bool, actions ...string) {
+		if len(resources) > 0 {
+			statements = append(statements, policyStatement{Sid: sid, Effect: "Allow", Action: actions, Resource: sortedKeys(resources)})
+		}
+	}
+	if len(r.ecrRepositories) > 0 {
+		add("EcrAuthorization", map[string]bool{"*": true}, "ecr:GetAuthorizationToken")
+	}
+	add("EcrPull", r.ecrRepositories, "ecr:BatchCheckLayerAvailability", "ecr:BatchGetImage", "ecr:GetDownloadUrlForLayer")
+	add("LogGroups", r.logGroups, "logs:CreateLogGroup")
+	add("LogStreams", r.logStreams, "logs:CreateLogStream", "logs:PutLogEvents")
+	add("Secrets", r.secrets, "secretsmanager:GetSecretValue")
+	add("Parameters", r.parameters, "ssm:GetParameters")
+	add("EnvironmentFiles", r.objects, "s3:GetObject")
+	add("EnvironmentFileBuckets", r.buckets, "s3:GetBucketLocation")
+	return statements
+}
+
+// newTaskDefinitionRole creates an IAM role that ECS tasks can assume, with the policies in args attached. It also
+// returns the policies, which have to be attached before the role is used.
+func newTaskDefinitionRole(ctx *pulumi.Context, name string, args *TaskDefinitionRoleArgs, tags pulumi.StringMapInput, parent pulumi.Resource) (*iam.Role, []pulumi.Resource, error) {
+	role, err := iam.NewRole(ctx, name, &iam.RoleArgs{
+		AssumeRolePolicy: pulumi.String(ecsTasksAssumeRolePolicy),
+		Tags:             tags,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create new role: %v", err)
+	}
+
+	var policies []pulumi.Resource
+	if args.Policy != nil {
+		policy, err := iam.NewRolePolicy(ctx, name, &iam.RolePolicyArgs{
+			Policy: args.Policy,
+			Role:   role.Name,
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create new role policy: %v", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	for i, policyArn := range args.ManagedPolicyArns {
+		attachment, err := iam.NewRolePolicyAttachment(ctx, fmt.Sprintf("%s-%d", name, i), &iam.RolePolicyAttachmentArgs{
+			PolicyArn: pulumi.String(policyArn),
+			Role:      role.Name,
+		}, pulumi.Parent(parent))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to attach role policy: %v", err)
+		}
+		policies = append(policies, attachment)
+	}
+
+	return role, policies, nil
+}
@@ -0,0 +1,106 @@
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExecutionRoleStatements is a unit test that checks the execution role policy derived from container definitions.
+func TestExecutionRoleStatements(t *testing.T) {
+	statements := executionRoleStatements([]ContainerDefinition{
+		{
+			Image: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-app:1.0.0",
+			LogConfiguration: &ContainerLogConfiguration{
+				LogDriver: "awslogs",
+				Options:   map[string]string{"awslogs-group": "/ecs/my-app", "awslogs-region": "us-west-2", "awslogs-create-group": "true"},
+			},
+			Secrets: []ContainerSecret{
+				{Name: "DB_PASSWORD", ValueFrom: "arn:aws:secretsmanager:us-west-2:123456789012:secret:my-db-AbCdEf:password::"},
+				{Name: "API_KEY", ValueFrom: "/my-app/api-key"},
+			},
+			EnvironmentFiles: []ContainerEnvironmentFile{{Type: "s3", Value: "arn:aws:s3:::my-bucket/my-app.env"}},
+		},
+		{
+			Image:                 "ghcr.io/my-org/my-sidecar:latest",
+			RepositoryCredentials: &ContainerRepositoryCredentials{CredentialsParameter: "arn:aws:secretsmanager:us-west-2:123456789012:secret:ghcr-XyZ123"},
+		},
+	})
+
+	assert.Equal(t, []policyStatement{
+		{Sid: "EcrAuthorization", Effect: "Allow", Action: []string{"ecr:GetAuthorizationToken"}, Resource: []string{"*"}},
+		{Sid: "EcrPull", Effect: "Allow", Action: []string{"ecr:BatchCheckLayerAvailability", "ecr:BatchGetImage", "ecr:GetDownloadUrlForLayer"}, Resource: []string{"arn:${aws:partition}:ecr:us-west-2:123456789012:repository/my-app"}},
+		{Sid: "LogGroups", Effect: "Allow", Action: []string{"logs:CreateLogGroup"}, Resource: []string{"arn:${aws:partition}:logs:us-west-2:*:log-group:/ecs/my-app:*"}},
+		{Sid: "LogStreams", Effect: "Allow", Action: []string{"logs:CreateLogStream", "logs:PutLogEvents"}, Resource: []string{"arn:${aws:partition}:logs:us-west-2:*:log-group:/ecs/my-app:log-stream:*"}},
+		{Sid: "Secrets", Effect: "Allow", Action: []string{"secretsmanager:GetSecretValue"}, Resource: []string{
+			"arn:aws:secretsmanager:us-west-2:123456789012:secret:ghcr-XyZ123",
+			"arn:aws:secretsmanager:us-west-2:123456789012:secret:my-db-AbCdEf",
+		}},
+		{Sid: "Parameters", Effect: "Allow", Action: []string{"ssm:GetParameters"}, Resource: []string{"arn:${aws:partition}:ssm:*:*:parameter/my-app/api-key"}},
+		{Sid: "EnvironmentFiles", Effect: "Allow", Action: []string{"s3:GetObject"}, Resource: []string{"arn:aws:s3:::my-bucket/my-app.env"}},
+		{Sid: "EnvironmentFileBuckets", Effect: "Allow", Action: []string{"s3:GetBucketLocation"}, Resource: []string{"arn:aws:s3:::my-bucket"}},
+	}, statements)
+
+	assert.Empty(t, executionRoleStatements([]ContainerDefinition{{Image: "nginx"}}))
+
+	statements = executionRoleStatements([]ContainerDefinition{{
+		Image:            "nginx",
+		EnvironmentFiles: []ContainerEnvironmentFile{{Type: "s3", Value: "arn:aws-cn:s3:::my-bucket/my-app.env"}},
+	}})
+	assert.Equal(t, []string{"arn:aws-cn:s3:::my-bucket"}, statements[1].Resource)
+}
+
+// TestNewTaskDefinitionRoles is a unit test that checks the execution and task roles created for a task definition.
+func TestNewTaskDefinitionRoles(t *testing.T) {
+	taskDefinitionConfig, create := taskDefinitionFixture(t)
+	taskDefinitionConfig.ContainerDefinitions[0].Secrets = []ContainerSecret{{Name: "API_KEY", ValueFrom: "/my-app/api-key"}}
+	taskDefinitionConfig.TaskRoleArn = nil
+	taskDefinitionConfig.TaskRole = &TaskDefinitionRoleConfig{
+		ManagedPolicyArns: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+		Statements: []PolicyStatementConfig{
+			{Actions: []string{"sqs:SendMessage"}, Resources: []string{"arn:aws:sqs:us-west-2:123456789012:my-queue"}},
+		},
+	}
+
+	m := create()
+
+	executionPolicy := m.resource(t, "aws:iam/rolePolicy:RolePolicy", "my-task-definition-execution")
+	assert.JSONEq(t, `{
+		"Version": "2012-10-17",
+		"Statement": [{"Sid": "Parameters", "Effect": "Allow", "Action": ["ssm:GetParameters"], "Resource": ["arn:aws:ssm:*:*:parameter/my-app/api-key"]}]
+	}`, executionPolicy.Inputs["policy"].StringValue())
+
+	taskPolicy := m.resource(t, "aws:iam/rolePolicy:RolePolicy", "my-task-definition-task")
+	var document policyDocument
+	assert.NoError(t, json.Unmarshal([]byte(taskPolicy.Inputs["policy"].StringValue()), &document))
+	assert.Equal(t, []string{"sqs:SendMessage"}, document.Statement[0].Action)
+
+	attachment := m.resource(t, "aws:iam/rolePolicyAttachment:RolePolicyAttachment", "my-task-definition-task-0")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"), attachment.Inputs["policyArn"])
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-task-definition")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-task-definition-execution"), taskDefinition.Inputs["executionRoleArn"])
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-task-definition-task"), taskDefinition.Inputs["taskRoleArn"])
+}
+
+// TestValidateTaskDefinitionRoles is a unit test that checks the checks of the roles created for a task definition.
+func TestValidateTaskDefinitionRoles(t *testing.T) {
+	taskDefinitionConfig, _ := taskDefinitionFixture(t)
+	taskDefinitionConfig.ExecutionRoleArn = pulumi.StringRef("arn:aws:iam::123456789012:role/ecsTaskExecutionRole")
+	taskDefinitionConfig.TaskRoleArn = nil
+	taskDefinitionConfig.TaskRole = &TaskDefinitionRoleConfig{
+		ManagedPolicyArns: []string{"AmazonS3ReadOnlyAccess"},
+		Statements:        []PolicyStatementConfig{{Effect: pulumi.StringRef("allow")}},
+	}
+
+	assert.Equal(t, map[string]string{
+		"taskDefinition.executionRoleArn":                 "must not be set together with executionRole",
+		"taskDefinition.taskRole.managedPolicyArns[0]":    `must be an ARN, got "AmazonS3ReadOnlyAccess"`,
+		"taskDefinition.taskRole.statements[0].actions":   "must contain at least one action",
+		"taskDefinition.taskRole.statements[0].effect":    `must be one of Allow, Deny, got "allow"`,
+		"taskDefinition.taskRole.statements[0].resources": "must contain at least one resource",
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+}
@@ -0,0 +1,301 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// RolloutType is the type token of the Rollout component resource.
+const RolloutType = "ecscomponent:index:Rollout"
+
+// RolloutConfig defines a weighted canary rollout of a service that uses the EXTERNAL deployment controller. The
+// stable and canary task sets each register their tasks with their own target group, and a listener rule splits the
+// traffic between them. Steps lists the percentages of traffic and tasks that the canary gets, ending at 100, and
+// Step is the index of the current one; advance it and run `pulumi up` to move the rollout on.
+//
+// At the last step, the canary is promoted: it gets all tasks and traffic and the stable task set is removed. The
+// promoted task set keeps its name, so moving the canary configuration into stable to start the next rollout leaves
+// it as it is. Without a canary, the stable task set gets all tasks and traffic.
+type RolloutConfig struct {
+	Canary       *TaskSetConfig    `json:"canary,omitempty"`
+	HostHeaders  []string          `json:"hostHeaders,omitempty"`
+	ListenerArn  string            `json:"listenerArn"`
+	Name         string            `json:"name"`
+	PathPatterns []string          `json:"pathPatterns,omitempty"`
+	Priority     *int              `json:"priority,omitempty"`
+	Stable       TaskSetConfig     `json:"stable"`
+	Step         int               `json:"step"`
+	Steps        []int             `json:"steps,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// RolloutArgs defines inputs for a weighted canary rollout. CanaryWeight is the percentage of traffic and tasks
+// that the canary gets; Canary and CanaryTargetGroupArn are ignored if it is 0, and Stable and StableTargetGroupArn
+// if it is 100.
+type RolloutArgs struct {
+	Canary               *TaskSetArgs
+	CanaryTargetGroupArn pulumi.StringInput
+	CanaryWeight         int
+	Conditions           lb.ListenerRuleConditionArray
+	ListenerArn          pulumi.StringInput
+	Name                 string
+	Priority             pulumi.IntPtrInput
+	Stable               *TaskSetArgs
+	StableTargetGroupArn pulumi.StringInput
+	Tags                 pulumi.StringMapInput
+}
+
+// Rollout is a component resource that manages the stable and canary task sets of an AWS ECS service and the
+// listener rule that splits the traffic between them.
+type Rollout struct {
+	pulumi.ResourceState
+
+	// Canary is the canary task set, or nil if no rollout is in progress or the canary has been promoted.
+	Canary *TaskSet
+	// CanaryWeight is the percentage of traffic and tasks that the canary gets.
+	CanaryWeight pulumi.IntOutput
+	// ListenerRule is the listener rule that splits the traffic between the task sets.
+	ListenerRule *lb.ListenerRule
+	// Stable is the stable task set, which is the promoted canary at the last step.
+	Stable *TaskSet
+}
+
+// ToArgs converts the rollout configuration into inputs for its current step. It returns an error if Step is not
+// the index of one of the Steps.
+func (c RolloutConfig) ToArgs() (*RolloutArgs, error) {
+	args := &RolloutArgs{
+		Conditions:           listenerRuleConditions(c.PathPatterns, c.HostHeaders),
+		ListenerArn:          pulumi.String(c.ListenerArn),
+		Name:                 c.Name,
+		Priority:             pulumi.IntPtrFromPtr(c.Priority),
+		Stable:               c.Stable.ToArgs(),
+		StableTargetGroupArn: pulumi.String(rolloutTargetGroupArn(c.Stable)),
+		Tags:                 pulumi.ToStringMap(c.Tags),
+	}
+	if c.Canary == nil || len(c.Steps) == 0 {
+		return args, nil
+	}
+	if c.Step < 0 || c.Step >= len(c.Steps) {
+		return nil, fmt.Errorf("rollout %s requires a step between 0 and %d, got %d", c.Name, len(c.Steps)-1, c.Step)
+	}
+
+	args.Canary = c.Canary.ToArgs()
+	args.CanaryTargetGroupArn = pulumi.String(rolloutTargetGroupArn(*c.Canary))
+	args.CanaryWeight = c.Steps[c.Step]
+	return args, nil
+}
+
+// rolloutTargetGroupArn returns the ARN of the first target group a task set registers its tasks with.
+func rolloutTargetGroupArn(taskSet TaskSetConfig) string {
+	for _, loadBalancer := range taskSet.LoadBalancers {
+		if loadBalancer.TargetGroupArn != nil {
+			return *loadBalancer.TargetGroupArn
+		}
+	}
+	return ""
+}
+
+// Validate checks the rollout configuration and returns a *ValidationError listing every problem found.
+func (c RolloutConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "rollout")
+	return v.err()
+}
+
+func (c RolloutConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	v.required(path+".listenerArn", c.ListenerArn)
+	v.between(path+".priority", c.Priority, 1, 50000)
+
+	validateRolloutTaskSet(v, path+".stable", c.Stable)
+	if c.Canary == nil {
+		return
+	}
+	validateRolloutTaskSet(v, path+".canary", *c.Canary)
+	if c.Canary.Name == c.Stable.Name {
+		v.errorf(path+".canary.name", "must differ from the name of the stable task set")
+	}
+	if c.Canary.Service != c.Stable.Service || c.Canary.Cluster != c.Stable.Cluster {
+		v.errorf(path+".canary", "must belong to the same cluster and service as the stable task set")
+	}
+	if arn := rolloutTargetGroupArn(*c.Canary); arn != "" && arn == rolloutTargetGroupArn(c.Stable) {
+		v.errorf(path+".canary.loadBalancers", "must use a different target group than the stable task set")
+	}
+
+	if len(c.Steps) == 0 {
+		v.errorf(path+".steps", "must contain at least one step when a canary is set")
+		return
+	}
+	for i, weight := range c.Steps {
+		stepPath := fmt.Sprintf("%s.steps[%d]", path, i)
+		v.between(stepPath, &weight, 1, 100)
+		if i > 0 && weight <= c.Steps[i-1] {
+			v.errorf(stepPath, "(%d) must be greater than the previous step (%d)", weight, c.Steps[i-1])
+		}
+	}
+	if c.Steps[len(c.Steps)-1] != 100 {
+		v.errorf(path+".steps", "must end at 100")
+	}
+	v.between(path+".step", &c.Step, 0, len(c.Steps)-1)
+}
+
+// validateRolloutTaskSet checks a task set of a rollout, which must register its tasks with a target group and
+// leave its scale to the rollout.
+func validateRolloutTaskSet(v *validator, path string, taskSet TaskSetConfig) {
+	taskSet.validate(v, path)
+	if rolloutTargetGroupArn(taskSet) == "" {
+		v.errorf(path+".loadBalancers", "must contain a load balancer with a targetGroupArn")
+	}
+	if taskSet.Scale != nil {
+		v.errorf(path+".scale", "is managed by the rollout")
+	}
+}
+
+// NewRollout creates or advances a weighted canary rollout of an AWS ECS service.
+func NewRollout(ctx *pulumi.Context, config RolloutConfig, opts ...pulumi.ResourceOption) (*Rollout, error) {
+	config, err := interpolate(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := config.ToArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRolloutFromArgs(ctx, args, opts...)
+}
+
+// NewRolloutFromArgs creates or advances a weighted canary rollout of an AWS ECS service from inputs. The scale of
+// the task sets follows the traffic: the canary runs CanaryWeight percent of the desired count of the service and
+// the stable task set the rest. At a CanaryWeight of 100, the canary is promoted and the stable task set is removed.
+func NewRolloutFromArgs(ctx *pulumi.Context, args *RolloutArgs, opts ...pulumi.ResourceOption) (*Rollout, error) {
+	err := checkRolloutArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resolveReferences(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &Rollout{}
+	err = ctx.RegisterComponentResource(RolloutType, args.Name, component, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource: %v", err)
+	}
+
+	taskSets, targetGroups := rolloutTaskSets(args)
+	components, err := NewTaskSetsFromArgs(ctx, taskSets, pulumi.Parent(component))
+	if err != nil {
+		return nil, err
+	}
+	component.Stable = components[0]
+	if len(components) > 1 {
+		component.Canary = components[1]
+	}
+
+	// The listener rule is updated after the task sets, so that traffic only shifts once their tasks are scaled.
+	dependsOn := make([]pulumi.Resource, 0, len(components))
+	for _, taskSet := range components {
+		dependsOn = append(dependsOn, taskSet)
+	}
+	component.ListenerRule, err = lb.NewListenerRule(ctx, args.Name, &lb.ListenerRuleArgs{
+		Actions: lb.ListenerRuleActionArray{
+			&lb.ListenerRuleActionArgs{
+				Forward: &lb.ListenerRuleActionForwardArgs{
+					TargetGroups: targetGroups,
+				},
+				Type: pulumi.String("forward"),
+			},
+		},
+		Conditions:  args.Conditions,
+		ListenerArn: args.ListenerArn,
+		Priority:    args.Priority,
+		Tags:        args.Tags,
+	}, pulumi.Parent(component), pulumi.DependsOn(dependsOn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new listener rule: %v", err)
+	}
+
+	component.CanaryWeight = pulumi.Int(args.CanaryWeight).ToIntOutput()
+
+	err = ctx.RegisterResourceOutputs(component, component.outputs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to register component resource outputs: %v", err)
+	}
+
+	return component, nil
+}
+
+// checkRolloutArgs checks that the rollout inputs describe a valid step.
+func checkRolloutArgs(args *RolloutArgs) error {
+	if args == nil || args.Stable == nil {
+		return fmt.Errorf("rollout requires a stable task set")
+	}
+	if args.CanaryWeight < 0 || args.CanaryWeight > 100 {
+		return fmt.Errorf("rollout %s requires a CanaryWeight between 0 and 100, got %d", args.Name, args.CanaryWeight)
+	}
+	if args.CanaryWeight > 0 && (args.Canary == nil || args.CanaryTargetGroupArn == nil) {
+		return fmt.Errorf("rollout %s requires a Canary and CanaryTargetGroupArn when CanaryWeight is set", args.Name)
+	}
+	return nil
+}
+
+// rolloutTaskSets returns the task sets of a rollout, scaled to their share of the tasks, and the target groups the
+// listener rule forwards to with their share of the traffic. At a CanaryWeight of 100, the canary is promoted and
+// is the only task set. The scale is set on copies, so that the args of the caller are left as they are.
+//
+// Every task set is force deleted, because the stable task set is removed at promotion while it still runs tasks.
+func rolloutTaskSets(args *RolloutArgs) ([]*TaskSetArgs, lb.ListenerRuleActionForwardTargetGroupArray) {
+	var taskSets []*TaskSetArgs
+	var targetGroups lb.ListenerRuleActionForwardTargetGroupArray
+	add := func(taskSet *TaskSetArgs, targetGroupArn pulumi.StringInput, weight int) {
+		scaled := *taskSet
+		scaled.ForceDelete = pulumi.Bool(true)
+		scaled.Scale = rolloutScale(weight)
+		taskSets = append(taskSets, &scaled)
+		targetGroups = append(targetGroups, &lb.ListenerRuleActionForwardTargetGroupArgs{
+			Arn:    targetGroupArn,
+			Weight: pulumi.Int(weight),
+		})
+	}
+
+	if args.CanaryWeight < 100 {
+		add(args.Stable, args.StableTargetGroupArn, 100-args.CanaryWeight)
+	}
+	if args.CanaryWeight > 0 {
+		add(args.Canary, args.CanaryTargetGroupArn, args.CanaryWeight)
+	}
+	return taskSets, targetGroups
+}
+
+// outputs returns the outputs of the rollout component.
+func (r *Rollout) outputs() pulumi.Map {
+	outputs := pulumi.Map{
+		"canaryWeight":    r.CanaryWeight,
+		"listenerRuleArn": r.ListenerRule.Arn,
+		"stableArn":       r.Stable.Arn,
+	}
+	if r.Canary != nil {
+		outputs["canaryArn"] = r.Canary.Arn
+	}
+	return outputs
+}
+
+// rolloutScale returns the scale of a task set that runs percent of the desired count of its service.
+func rolloutScale(percent int) *ecs.TaskSetScaleArgs {
+	return &ecs.TaskSetScaleArgs{
+		Unit:  pulumi.String("PERCENT"),
+		Value: pulumi.Float64(float64(percent)),
+	}
+}
@@ -0,0 +1,157 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+const rolloutJSON = `{
+  "name": "my-rollout",
+  "listenerArn": "arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/my-alb/0123456789abcdef/0123456789abcdef",
+  "stable": {
+    "name": "my-service-v1",
+    "cluster": "my-cluster",
+    "service": "my-service",
+    "taskDefinition": "my-task-definition:1",
+    "loadBalancers": [{ "containerName": "my-container", "containerPort": 80, "targetGroupArn": "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/v1/0123456789abcdef" }]
+  },
+  "canary": {
+    "name": "my-service-v2",
+    "cluster": "my-cluster",
+    "service": "my-service",
+    "taskDefinition": "my-task-definition:2",
+    "loadBalancers": [{ "containerName": "my-container", "containerPort": 80, "targetGroupArn": "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/v2/0123456789abcdef" }]
+  },
+  "steps": [10, 50, 100],
+  "step": 0
+}`
+
+func getRolloutConfig(t *testing.T) RolloutConfig {
+	var rolloutConfig RolloutConfig
+	err := DecodeConfig([]byte(rolloutJSON), &rolloutConfig)
+	assert.NoError(t, err)
+	return rolloutConfig
+}
+
+// forwardWeights returns the weight of each target group the listener rule forwards to.
+func forwardWeights(rule registeredResource) map[string]float64 {
+	weights := map[string]float64{}
+	action := rule.Inputs["actions"].ArrayValue()[0].ObjectValue()
+	for _, targetGroup := range action["forward"].ObjectValue()["targetGroups"].ArrayValue() {
+		weights[targetGroup.ObjectValue()["arn"].StringValue()] = targetGroup.ObjectValue()["weight"].NumberValue()
+	}
+	return weights
+}
+
+// TestNewRolloutCanaryStep is a unit test that checks the scale of the task sets and the traffic weights at an intermediate step.
+func TestNewRolloutCanaryStep(t *testing.T) {
+	rolloutConfig := getRolloutConfig(t)
+	rolloutConfig.Step = 1
+
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := NewRollout(ctx, rolloutConfig)
+		return err
+	})
+
+	stable := m.resource(t, "aws:ecs/taskSet:TaskSet", "my-service-v1")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"unit": "PERCENT", "value": 50}), stable.Inputs["scale"].ObjectValue())
+	assert.Equal(t, resource.NewBoolProperty(true), stable.Inputs["forceDelete"])
+	canary := m.resource(t, "aws:ecs/taskSet:TaskSet", "my-service-v2")
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"unit": "PERCENT", "value": 50}), canary.Inputs["scale"].ObjectValue())
+	assert.Empty(t, m.resource(t, TaskSetType, "my-service-v2").Aliases)
+	assert.Empty(t, canary.Aliases)
+
+	rule := m.resource(t, "aws:lb/listenerRule:ListenerRule", "my-rollout")
+	assert.Equal(t, componentURN(RolloutType, "my-rollout"), rule.Parent)
+	assert.Equal(t, map[string]float64{
+		"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/v1/0123456789abcdef": 50,
+		"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/v2/0123456789abcdef": 50,
+	}, forwardWeights(rule))
+}
+
+// TestNewRolloutPromotion is a unit test that checks that the canary is promoted at the last step: it keeps its task set and gets all tasks and traffic, and the stable task set is removed.
+func TestNewRolloutPromotion(t *testing.T) {
+	rolloutConfig := getRolloutConfig(t)
+	rolloutConfig.Step = 2
+
+	var rollout *Rollout
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		var err error
+		rollout, err = NewRollout(ctx, rolloutConfig)
+		return err
+	})
+
+	assert.Equal(t, 1, m.count("aws:ecs/taskSet:TaskSet"))
+	promoted := m.resource(t, "aws:ecs/taskSet:TaskSet", "my-service-v2")
+	assert.Equal(t, resource.NewStringProperty("my-task-definition:2"), promoted.Inputs["taskDefinition"])
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{"unit": "PERCENT", "value": 100}), promoted.Inputs["scale"].ObjectValue())
+	assert.Nil(t, rollout.Canary)
+
+	rule := m.resource(t, "aws:lb/listenerRule:ListenerRule", "my-rollout")
+	assert.Equal(t, map[string]float64{
+		"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/v2/0123456789abcdef": 100,
+	}, forwardWeights(rule))
+}
+
+// TestNewRolloutFromArgs is a unit test that checks that invalid rollout inputs are rejected and that the inputs of the caller are not changed.
+func TestNewRolloutFromArgs(t *testing.T) {
+	args, err := getRolloutConfig(t).ToArgs()
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(args *RolloutArgs)
+		err    string
+	}{
+		{"negative weight", func(args *RolloutArgs) { args.CanaryWeight = -10 }, "rollout my-rollout requires a CanaryWeight between 0 and 100, got -10"},
+		{"weight above 100", func(args *RolloutArgs) { args.CanaryWeight = 150 }, "rollout my-rollout requires a CanaryWeight between 0 and 100, got 150"},
+		{"no canary", func(args *RolloutArgs) { args.Canary = nil }, "rollout my-rollout requires a Canary and CanaryTargetGroupArn when CanaryWeight is set"},
+		{"no canary target group", func(args *RolloutArgs) { args.CanaryTargetGroupArn = nil }, "rollout my-rollout requires a Canary and CanaryTargetGroupArn when CanaryWeight is set"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			invalid := *args
+			tc.modify(&invalid)
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				_, err := NewRolloutFromArgs(ctx, &invalid)
+				return err
+			}, pulumi.WithMocks("project", "stack", newMocks()))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	runWithMocks(t, newMocks(), func(ctx *pulumi.Context) error {
+		_, err := NewRolloutFromArgs(ctx, args)
+		return err
+	})
+	assert.Nil(t, args.Stable.Scale)
+	assert.Nil(t, args.Canary.Scale)
+}
+
+// TestRolloutConfigToArgs is a unit test that checks that a step outside of the steps is rejected instead of indexing past them.
+func TestRolloutConfigToArgs(t *testing.T) {
+	rolloutConfig := getRolloutConfig(t)
+	rolloutConfig.Step = 3
+
+	_, err := rolloutConfig.ToArgs()
+	assert.EqualError(t, err, "rollout my-rollout requires a step between 0 and 2, got 3")
+}
+
+// TestValidateRollout is a unit test that checks the checks of a rollout configuration.
+func TestValidateRollout(t *testing.T) {
+	rolloutConfig := getRolloutConfig(t)
+	rolloutConfig.Canary.Service = "my-other-service"
+	rolloutConfig.Steps = []int{50, 20}
+	rolloutConfig.Step = 3
+
+	assert.Equal(t, map[string]string{
+		"rollout.canary":   "must belong to the same cluster and service as the stable task set",
+		"rollout.step":     "must be between 0 and 1, got 3",
+		"rollout.steps":    "must end at 100",
+		"rollout.steps[1]": "(20) must be greater than the previous step (50)",
+	}, fieldErrors(t, rolloutConfig.Validate()))
+}
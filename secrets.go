@@ -0,0 +1,255 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/secretsmanager"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ssm"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// TaskSecretConfig defines a secret that is exposed to containers of a task definition as the environment variable
+// Name. It references an existing Secrets Manager secret or Systems Manager parameter by name, or is created from
+// Value, which must be a ${secret:key} reference or a stack config secret, in the Store secretsmanager (the default)
+// or ssm. JSONKey selects a key of a JSON Secrets Manager secret, and KmsKeyArn is the customer managed key that
+// encrypts the secret. Unless Containers is set, the secret is exposed to every container.
+type TaskSecretConfig struct {
+	Containers []string `json:"containers,omitempty"`
+	JSONKey    *string  `json:"jsonKey,omitempty"`
+	KmsKeyArn  *string  `json:"kmsKeyArn,omitempty"`
+	Name       string   `json:"name"`
+	Parameter  *string  `json:"parameter,omitempty"`
+	Secret     *string  `json:"secret,omitempty"`
+	Store      *string  `json:"store,omitempty"`
+	Value      *string  `json:"value,omitempty"`
+}
+
+// TaskSecretArgs defines inputs for a secret that is exposed to containers of a task definition. Value is an input,
+// so that it can hold a Pulumi secret; the other fields determine which resources are created and are plain values.
+type TaskSecretArgs struct {
+	Containers []string
+	JSONKey    *string
+	KmsKeyArn  *string
+	Name       string
+	Parameter  *string
+	Secret     *string
+	Store      string
+	Value      pulumi.StringInput
+}
+
+// taskSecrets holds the ARNs that the secrets of a task definition are read from, in the order of the secrets.
+type taskSecrets struct {
+	args       []*TaskSecretArgs
+	parameters pulumi.StringArray
+	secrets    pulumi.StringArray
+	valueFroms pulumi.StringArray
+}
+
+// toArgs converts the secret configuration into inputs.
+func (c TaskSecretConfig) toArgs() *TaskSecretArgs {
+	store := "secretsmanager"
+	switch {
+	case c.Store != nil:
+		store = *c.Store
+	case c.Parameter != nil:
+		store = "ssm"
+	}
+
+	var value pulumi.StringInput
+	if c.Value != nil {
+		value = pulumi.String(*c.Value)
+	}
+
+	return &TaskSecretArgs{
+		Containers: c.Containers,
+		JSONKey:    c.JSONKey,
+		KmsKeyArn:  c.KmsKeyArn,
+		Name:       c.Name,
+		Parameter:  c.Parameter,
+		Secret:     c.Secret,
+		Store:      store,
+		Value:      value,
+	}
+}
+
+// validateTaskSecrets checks the secrets of a task definition against its container definitions.
+func validateTaskSecrets(v *validator, path string, secrets []TaskSecretConfig, containers []ContainerDefinition) {
+	var names []string
+	for i, secret := range secrets {
+		secretPath := fmt.Sprintf("%s[%d]", path, i)
+		if secret.Name != "" && slices.Contains(names, secret.Name) {
+			v.errorf(secretPath+".name", "duplicate secret %q", secret.Name)
+		}
+		names = append(names, secret.Name)
+
+		secret.validate(v, secretPath)
+		secret.validateContainers(v, secretPath, containers)
+	}
+}
+
+func (c TaskSecretConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	sources := 0
+	for _, source := range []*string{c.Parameter, c.Secret, c.Value} {
+		if source != nil {
+			sources++
+		}
+	}
+	if sources != 1 {
+		v.errorf(path, "must set exactly one of parameter, secret and value")
+	}
+	if c.Value != nil && !strings.Contains(*c.Value, "${secret:") {
+		v.errorf(path+".value", "must be a ${secret:key} reference or a stack config secret")
+	}
+
+	v.oneOf(path+".store", c.Store, "secretsmanager", "ssm")
+	if c.Store != nil && c.Value == nil {
+		v.errorf(path+".store", "is only supported for secrets created from a value")
+	}
+	if c.JSONKey != nil && (c.Parameter != nil || c.Store != nil && *c.Store == "ssm") {
+		v.errorf(path+".jsonKey", "is only supported by Secrets Manager secrets")
+	}
+	if c.KmsKeyArn != nil && !strings.HasPrefix(*c.KmsKeyArn, "arn:") {
+		v.errorf(path+".kmsKeyArn", "must be an ARN, got %q", *c.KmsKeyArn)
+	}
+}
+
+// validateContainers checks that the containers of the secret exist and do not define a secret with its name.
+func (c TaskSecretConfig) validateContainers(v *validator, path string, containers []ContainerDefinition) {
+	for i, name := range c.Containers {
+		if !slices.ContainsFunc(containers, func(container ContainerDefinition) bool { return container.Name == name }) {
+			v.errorf(fmt.Sprintf("%s.containers[%d]", path, i), "unknown container %q", name)
+		}
+	}
+	for _, container := range containers {
+		if len(c.Containers) > 0 && !slices.Contains(c.Containers, container.Name) {
+			continue
+		}
+		if slices.ContainsFunc(container.Secrets, func(s ContainerSecret) bool { return s.Name == c.Name }) {
+			v.errorf(path+".name", "container %q already has a secret %q", container.Name, c.Name)
+		}
+	}
+}
+
+// newTaskSecrets looks up the existing secrets and parameters of a task definition by name, and creates the ones
+// that are defined by a value.
+func newTaskSecrets(ctx *pulumi.Context, name string, secrets []*TaskSecretArgs, tags pulumi.StringMapInput, parent pulumi.Resource) (*taskSecrets, error) {
+	result := &taskSecrets{args: secrets}
+	for _, secret := range secrets {
+		resourceName := name + "-secret-" + secret.Name
+
+		var arn pulumi.StringOutput
+		switch {
+		case secret.Secret != nil:
+			arn = secretsmanager.LookupSecretOutput(ctx, secretsmanager.LookupSecretOutputArgs{
+				Name: pulumi.String(*secret.Secret),
+			}, pulumi.Parent(parent)).Arn()
+		case secret.Parameter != nil:
+			arn = ssm.LookupParameterOutput(ctx, ssm.LookupParameterOutputArgs{
+				Name:           pulumi.String(*secret.Parameter),
+				WithDecryption: pulumi.Bool(false),
+			}, pulumi.Parent(parent)).Arn()
+		case secret.Value != nil && secret.Store == "ssm":
+			parameter, err := ssm.NewParameter(ctx, resourceName, &ssm.ParameterArgs{
+				KeyId: pulumi.StringPtrFromPtr(secret.KmsKeyArn),
+				Tags:  tags,
+				Type:  pulumi.String("SecureString"),
+				Value: pulumi.ToSecret(secret.Value).(pulumi.StringOutput),
+			}, pulumi.Parent(parent))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create new parameter: %v", err)
+			}
+			arn = parameter.Arn
+		case secret.Value != nil:
+			created, err := secretsmanager.NewSecret(ctx, resourceName, &secretsmanager.SecretArgs{
+				KmsKeyId: pulumi.StringPtrFromPtr(secret.KmsKeyArn),
+				Tags:     tags,
+			}, pulumi.Parent(parent))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create new secret: %v", err)
+			}
+
+			_, err = secretsmanager.NewSecretVersion(ctx, resourceName, &secretsmanager.SecretVersionArgs{
+				SecretId:     created.ID(),
+				SecretString: pulumi.ToSecret(secret.Value).(pulumi.StringOutput),
+			}, pulumi.Parent(parent))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create new secret version: %v", err)
+			}
+			arn = created.Arn
+		default:
+			return nil, fmt.Errorf("secret %s requires a parameter, secret or value", secret.Name)
+		}
+
+		valueFrom := arn
+		if secret.Store == "ssm" {
+			result.parameters = append(result.parameters, arn)
+		} else {
+			result.secrets = append(result.secrets, arn)
+			if secret.JSONKey != nil {
+				valueFrom = pulumi.Sprintf("%s:%s::", arn, *secret.JSONKey)
+			}
+		}
+		result.valueFroms = append(result.valueFroms, valueFrom)
+	}
+	return result, nil
+}
+
+// inject returns the JSON encoded container definitions with the secrets added to the containers they are exposed to.
+func (s *taskSecrets) inject(containerDefinitions pulumi.StringInput) pulumi.StringOutput {
+	return pulumi.All(containerDefinitions, s.valueFroms).ApplyT(func(all []interface{}) (string, error) {
+		var containers []ContainerDefinition
+		err := json.Unmarshal([]byte(all[0].(string)), &containers)
+		if err != nil {
+			return "", fmt.Errorf("could not unmarshal container definitions json: %v", err)
+		}
+
+		valueFroms, _ := all[1].([]string)
+		for i, secret := range s.args {
+			for j := range containers {
+				if len(secret.Containers) == 0 || slices.Contains(secret.Containers, containers[j].Name) {
+					containers[j].Secrets = append(containers[j].Secrets, ContainerSecret{Name: secret.Name, ValueFrom: valueFroms[i]})
+				}
+			}
+		}
+
+		data, err := json.Marshal(containers)
+		if err != nil {
+			return "", fmt.Errorf("could not marshal container definitions json: %v", err)
+		}
+		return string(data), nil
+	}).(pulumi.StringOutput)
+}
+
+// policy returns a JSON encoded IAM policy that allows reading the secrets and decrypting them with their keys.
+func (s *taskSecrets) policy() pulumi.StringOutput {
+	var kmsKeys []string
+	for _, secret := range s.args {
+		if secret.KmsKeyArn != nil && !slices.Contains(kmsKeys, *secret.KmsKeyArn) {
+			kmsKeys = append(kmsKeys, *secret.KmsKeyArn)
+		}
+	}
+	slices.Sort(kmsKeys)
+
+	return pulumi.All(s.secrets, s.parameters).ApplyT(func(all []interface{}) (string, error) {
+		var statements []policyStatement
+		if secrets, _ := all[0].([]string); len(secrets) > 0 {
+			statements = append(statements, policyStatement{Sid: "TaskSecrets", Effect: "Allow", Action: []string{"secretsmanager:GetSecretValue"}, Resource: secrets})
+		}
+		if parameters, _ := all[1].([]string); len(parameters) > 0 {
+			statements = append(statements, policyStatement{Sid: "TaskParameters", Effect: "Allow", Action: []string{"ssm:GetParameters"}, Resource: parameters})
+		}
+		if len(kmsKeys) > 0 {
+			statements = append(statements, policyStatement{Sid: "TaskSecretKeys", Effect: "Allow", Action: []string{"kms:Decrypt"}, Resource: kmsKeys})
+		}
+
+		data, err := json.Marshal(policyDocument{Version: "2012-10-17", Statement: statements})
+		if err != nil {
+			return "", fmt.Errorf("could not marshal role policy json: %v", err)
+		}
+		return string(data), nil
+	}).(pulumi.StringOutput)
+}
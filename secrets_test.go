@@ -0,0 +1,74 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewTaskDefinitionSecrets is a unit test that checks that the secrets of a task definition are created or looked up, injected into the containers and granted to the execution role.
+func TestNewTaskDefinitionSecrets(t *testing.T) {
+	setStackConfig(t, `{"project:dbPassword": "hunter2"}`, `["project:dbPassword"]`)
+
+	taskDefinitionConfig, create := taskDefinitionFixture(t)
+	taskDefinitionConfig.Secrets = []TaskSecretConfig{
+		{Name: "DB_PASSWORD", Value: pulumi.StringRef("${config:dbPassword}")},
+		{Name: "API_KEY", Parameter: pulumi.StringRef("/my-app/api-key"), KmsKeyArn: pulumi.StringRef("arn:aws:kms:us-west-2:123456789012:key/my-key")},
+		{Name: "CONFIG_URL", Secret: pulumi.StringRef("my-config"), JSONKey: pulumi.StringRef("url"), Containers: []string{"my-container"}},
+	}
+
+	m := create()
+
+	version := m.resource(t, "aws:secretsmanager/secretVersion:SecretVersion", "my-task-definition-secret-DB_PASSWORD")
+	assert.True(t, version.Inputs["secretString"].IsSecret())
+	assert.Equal(t, "hunter2", version.Inputs["secretString"].SecretValue().Element.StringValue())
+	assert.Equal(t, 1, m.count("aws:secretsmanager/secret:Secret"))
+	assert.Equal(t, 0, m.count("aws:ssm/parameter:Parameter"))
+
+	taskDefinition := m.resource(t, "aws:ecs/taskDefinition:TaskDefinition", "my-task-definition")
+	assert.JSONEq(t, `[{
+		"name": "my-container",
+		"image": "nginx",
+		"portMappings": [{"containerPort": 80, "hostPort": 80}],
+		"secrets": [
+			{"name": "DB_PASSWORD", "valueFrom": "arn:aws:mock:us-west-2:123456789012:my-task-definition-secret-DB_PASSWORD"},
+			{"name": "API_KEY", "valueFrom": "arn:aws:mock:us-west-2:123456789012:/my-app/api-key"},
+			{"name": "CONFIG_URL", "valueFrom": "arn:aws:mock:us-west-2:123456789012:my-config:url::"}
+		]
+	}]`, taskDefinition.Inputs["containerDefinitions"].StringValue())
+
+	policy := m.resource(t, "aws:iam/rolePolicy:RolePolicy", "my-task-definition-execution-secrets")
+	assert.JSONEq(t, `{
+		"Version": "2012-10-17",
+		"Statement": [
+			{"Sid": "TaskSecrets", "Effect": "Allow", "Action": ["secretsmanager:GetSecretValue"], "Resource": [
+				"arn:aws:mock:us-west-2:123456789012:my-task-definition-secret-DB_PASSWORD",
+				"arn:aws:mock:us-west-2:123456789012:my-config"
+			]},
+			{"Sid": "TaskParameters", "Effect": "Allow", "Action": ["ssm:GetParameters"], "Resource": ["arn:aws:mock:us-west-2:123456789012:/my-app/api-key"]},
+			{"Sid": "TaskSecretKeys", "Effect": "Allow", "Action": ["kms:Decrypt"], "Resource": ["arn:aws:kms:us-west-2:123456789012:key/my-key"]}
+		]
+	}`, policy.Inputs["policy"].StringValue())
+}
+
+// TestValidateTaskDefinitionSecrets is a unit test that checks the checks of the secrets of a task definition.
+func TestValidateTaskDefinitionSecrets(t *testing.T) {
+	taskDefinitionConfig, _ := taskDefinitionFixture(t)
+	taskDefinitionConfig.ExecutionRole = nil
+	taskDefinitionConfig.ContainerDefinitions[0].Secrets = []ContainerSecret{{Name: "API_KEY", ValueFrom: "/my-app/api-key"}}
+	taskDefinitionConfig.Secrets = []TaskSecretConfig{
+		{Name: "API_KEY", Parameter: pulumi.StringRef("/my-app/api-key")},
+		{Name: "DB_PASSWORD", Value: pulumi.StringRef("hunter2"), Store: pulumi.StringRef("ssm"), JSONKey: pulumi.StringRef("password")},
+		{Name: "TOKEN", Containers: []string{"my-sidecar"}},
+	}
+
+	assert.Equal(t, map[string]string{
+		"taskDefinition.secrets":                  "require an executionRole or executionRoleArn",
+		"taskDefinition.secrets[0].name":          `container "my-container" already has a secret "API_KEY"`,
+		"taskDefinition.secrets[1].jsonKey":       "is only supported by Secrets Manager secrets",
+		"taskDefinition.secrets[1].value":         "must be a ${secret:key} reference or a stack config secret",
+		"taskDefinition.secrets[2]":               "must set exactly one of parameter, secret and value",
+		"taskDefinition.secrets[2].containers[0]": `unknown container "my-sidecar"`,
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+}
@@ -0,0 +1,375 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Spec describes a complete AWS ECS topology in a single document.
+// Resources are keyed by a logical name, which other resources in the spec use to refer to them.
+type Spec struct {
+	AccountSettingsDefault []AccountSettingDefaultConfig     `json:"accountSettingsDefault,omitempty"`
+	CapacityProviders      map[string]CapacityProviderConfig `json:"capacityProviders,omitempty"`
+	Clusters               map[string]ClusterSpec            `json:"clusters,omitempty"`
+	Services               map[string]ServiceSpec            `json:"services,omitempty"`
+	TaskDefinitions        map[string]TaskDefinitionConfig   `json:"taskDefinitions,omitempty"`
+	TaskSets               map[string]TaskSetConfig          `json:"taskSets,omitempty"`
+}
+
+// ClusterSpec defines a cluster in a spec, together with the capacity providers attached to it.
+// Capacity providers are either FARGATE, FARGATE_SPOT or the logical name of a capacity provider in the spec.
+type ClusterSpec struct {
+	ClusterConfig
+	CapacityProviders                 []string `json:"capacityProviders,omitempty"`
+	DefaultCapacityProviderStrategies []struct {
+		Base             *int   `json:"base,omitempty"`
+		CapacityProvider string `json:"capacityProvider"`
+		Weight           *int   `json:"weight,omitempty"`
+	} `json:"defaultCapacityProviderStrategies"`
+}
+
+// ServiceSpec defines a service in a spec.
+// Cluster is the logical name of the cluster in the spec that runs the service, and replaces clusterArn.
+type ServiceSpec struct {
+	ServiceConfig
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// Topology holds the component resources created from a spec, keyed by their logical names.
+type Topology struct {
+	AccountSettingsDefault   []*AccountSettingDefault
+	CapacityProviders        map[string]*CapacityProvider
+	ClusterCapacityProviders map[string]*ClusterCapacityProvider
+	Clusters                 map[string]*Cluster
+	Services                 map[string]*Service
+	TaskDefinitions          map[string]*TaskDefinition
+	TaskSets                 map[string]*TaskSet
+}
+
+// LoadSpec reads a spec from a JSON or YAML file. Unknown fields are rejected.
+func LoadSpec(path string) (*Spec, error) {
+	var spec Spec
+	err := LoadConfigFile(path, "", &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// Validate checks every resource in the spec and the references between them, and returns a *ValidationError
+// listing every problem found.
+func (s *Spec) Validate() error {
+	v := &validator{}
+	s.withDefaults().validate(v)
+	return v.err()
+}
+
+func (s *Spec) validate(v *validator) {
+	for i, accountSettingDefault := range s.AccountSettingsDefault {
+		path := fmt.Sprintf("accountSettingsDefault[%d]", i)
+		v.required(path+".name", accountSettingDefault.Name)
+		v.required(path+".value", accountSettingDefault.Value)
+	}
+
+	for _, key := range sortedKeys(s.CapacityProviders) {
+		s.CapacityProviders[key].validate(v, "capacityProviders."+key)
+	}
+
+	for _, key := range sortedKeys(s.Clusters) {
+		cluster := s.Clusters[key]
+		path := "clusters." + key
+		cluster.ClusterConfig.validate(v, path)
+		for i, strategy := range cluster.DefaultCapacityProviderStrategies {
+			if !slices.Contains(cluster.CapacityProviders, strategy.CapacityProvider) {
+				v.errorf(fmt.Sprintf("%s.defaultCapacityProviderStrategies[%d].capacityProvider", path, i),
+					"%q is not one of the capacityProviders of the cluster", strategy.CapacityProvider)
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(s.TaskDefinitions) {
+		s.TaskDefinitions[key].validate(v, "taskDefinitions."+key)
+	}
+
+	for _, key := range sortedKeys(s.Services) {
+		service := s.Services[key]
+		path := "services." + key
+		config := service.ServiceConfig
+		if service.Cluster != "" {
+			if config.ClusterArn != "" {
+				v.errorf(path+".cluster", "must not be set together with clusterArn")
+			}
+			if _, ok := s.Clusters[service.Cluster]; !ok {
+				v.errorf(path+".cluster", "unknown cluster %q", service.Cluster)
+			}
+			config.ClusterArn = service.Cluster
+		}
+		config.validate(v, path)
+	}
+
+	for _, key := range sortedKeys(s.TaskSets) {
+		s.TaskSets[key].validate(v, "taskSets."+key)
+	}
+}
+
+// withDefaults returns a copy of the spec in which resources without a name are named after their logical name.
+func (s *Spec) withDefaults() *Spec {
+	spec := &Spec{
+		AccountSettingsDefault: s.AccountSettingsDefault,
+		CapacityProviders:      map[string]CapacityProviderConfig{},
+		Clusters:               map[string]ClusterSpec{},
+		Services:               map[string]ServiceSpec{},
+		TaskDefinitions:        map[string]TaskDefinitionConfig{},
+		TaskSets:               map[string]TaskSetConfig{},
+	}
+
+	for key, capacityProvider := range s.CapacityProviders {
+		if capacityProvider.Name == "" {
+			capacityProvider.Name = key
+		}
+		spec.CapacityProviders[key] = capacityProvider
+	}
+	for key, cluster := range s.Clusters {
+		if cluster.Name == "" {
+			cluster.Name = key
+		}
+		spec.Clusters[key] = cluster
+	}
+	for key, service := range s.Services {
+		if service.Name == "" {
+			service.Name = key
+		}
+		spec.Services[key] = service
+	}
+	for key, taskDefinition := range s.TaskDefinitions {
+		if taskDefinition.Name == "" {
+			taskDefinition.Name = key
+		}
+		spec.TaskDefinitions[key] = taskDefinition
+	}
+	for key, taskSet := range s.TaskSets {
+		if taskSet.Name == "" {
+			taskSet.Name = key
+		}
+		spec.TaskSets[key] = taskSet
+	}
+
+	return spec
+}
+
+// Apply creates every resource in the spec in dependency order: account settings, capacity providers, clusters and
+// their capacity providers, task definitions, services and finally task sets. References to other resources in the
+// spec are resolved by logical name into the outputs of those resources; other values are passed on as is.
+func Apply(ctx *pulumi.Context, spec *Spec, opts ...pulumi.ResourceOption) (*Topology, error) {
+	spec, err := interpolate(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	spec = spec.withDefaults()
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &Topology{
+		CapacityProviders:        map[string]*CapacityProvider{},
+		ClusterCapacityProviders: map[string]*ClusterCapacityProvider{},
+		Clusters:                 map[string]*Cluster{},
+		Services:                 map[string]*Service{},
+		TaskDefinitions:          map[string]*TaskDefinition{},
+		TaskSets:                 map[string]*TaskSet{},
+	}
+
+	topology.AccountSettingsDefault, err = NewAccountSettingsDefault(ctx, spec.AccountSettingsDefault, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = topology.applyCapacityProviders(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = topology.applyClusters(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = topology.applyTaskDefinitions(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = topology.applyServices(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = topology.applyTaskSets(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return topology, nil
+}
+
+// applyCapacityProviders creates the capacity providers of the spec.
+func (t *Topology) applyCapacityProviders(ctx *pulumi.Context, spec *Spec, opts []pulumi.ResourceOption) error {
+	for _, key := range sortedKeys(spec.CapacityProviders) {
+		capacityProviders, err := NewCapacityProvidersFromArgs(ctx, []*CapacityProviderArgs{spec.CapacityProviders[key].ToArgs()}, opts...)
+		if err != nil {
+			return err
+		}
+		t.CapacityProviders[key] = capacityProviders[0]
+	}
+	return nil
+}
+
+// applyClusters creates the clusters of the spec and attaches their capacity providers.
+func (t *Topology) applyClusters(ctx *pulumi.Context, spec *Spec, opts []pulumi.ResourceOption) error {
+	for _, key := range sortedKeys(spec.Clusters) {
+		config := spec.Clusters[key]
+		cluster, err := NewClusterFromArgs(ctx, config.Name, config.ToArgs(), opts...)
+		if err != nil {
+			return err
+		}
+		t.Clusters[key] = cluster
+
+		if len(config.CapacityProviders) == 0 {
+			continue
+		}
+		err = t.applyClusterCapacityProvider(ctx, spec, key, opts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyClusterCapacityProvider attaches the capacity providers of the cluster key to it, resolving the logical names of
+// capacity providers in the spec into their names.
+func (t *Topology) applyClusterCapacityProvider(ctx *pulumi.Context, spec *Spec, key string, opts []pulumi.ResourceOption) error {
+	config := spec.Clusters[key]
+
+	var dependencies []pulumi.Resource
+	clusterCapacityProviderConfig := ClusterCapacityProviderConfig{
+		ClusterName:                       config.Name,
+		DefaultCapacityProviderStrategies: slices.Clone(config.DefaultCapacityProviderStrategies),
+	}
+	for _, name := range config.CapacityProviders {
+		if capacityProvider, ok := t.CapacityProviders[name]; ok {
+			dependencies = append(dependencies, capacityProvider)
+			name = spec.CapacityProviders[name].Name
+		}
+		clusterCapacityProviderConfig.CapacityProviders = append(clusterCapacityProviderConfig.CapacityProviders, name)
+	}
+	for i, strategy := range clusterCapacityProviderConfig.DefaultCapacityProviderStrategies {
+		if capacityProvider, ok := spec.CapacityProviders[strategy.CapacityProvider]; ok {
+			clusterCapacityProviderConfig.DefaultCapacityProviderStrategies[i].CapacityProvider = capacityProvider.Name
+		}
+	}
+
+	args := clusterCapacityProviderConfig.ToArgs()
+	args.ClusterName = t.Clusters[key].Name
+	clusterCapacityProvider, err := NewClusterCapacityProviderFromArgs(ctx, config.Name, args, withDependsOn(opts, dependencies)...)
+	if err != nil {
+		return err
+	}
+	t.ClusterCapacityProviders[key] = clusterCapacityProvider
+	return nil
+}
+
+// applyTaskDefinitions creates the task definitions of the spec.
+func (t *Topology) applyTaskDefinitions(ctx *pulumi.Context, spec *Spec, opts []pulumi.ResourceOption) error {
+	for _, key := range sortedKeys(spec.TaskDefinitions) {
+		config := spec.TaskDefinitions[key]
+		args, err := config.ToArgs()
+		if err != nil {
+			return err
+		}
+
+		taskDefinition, err := NewTaskDefinitionFromArgs(ctx, config.Name, args, opts...)
+		if err != nil {
+			return err
+		}
+		t.TaskDefinitions[key] = taskDefinition
+	}
+	return nil
+}
+
+// applyServices creates the services of the spec, on the clusters and with the task definitions they reference.
+func (t *Topology) applyServices(ctx *pulumi.Context, spec *Spec, opts []pulumi.ResourceOption) error {
+	for _, key := range sortedKeys(spec.Services) {
+		config := spec.Services[key]
+		config.CapacityProviderStrategies = slices.Clone(config.CapacityProviderStrategies)
+		for i, strategy := range config.CapacityProviderStrategies {
+			if capacityProvider, ok := spec.CapacityProviders[strategy.CapacityProvider]; ok {
+				config.CapacityProviderStrategies[i].CapacityProvider = capacityProvider.Name
+			}
+		}
+
+		var dependencies []pulumi.Resource
+		args := config.ToArgs()
+		if cluster, ok := t.Clusters[config.Cluster]; ok {
+			args.ClusterArn = cluster.Arn
+			if clusterCapacityProvider, ok := t.ClusterCapacityProviders[config.Cluster]; ok {
+				dependencies = append(dependencies, clusterCapacityProvider)
+			}
+		}
+		if config.TaskDefinition != nil {
+			if taskDefinition, ok := t.TaskDefinitions[*config.TaskDefinition]; ok {
+				args.TaskDefinition = taskDefinition.Arn
+			}
+		}
+
+		service, err := NewServiceFromArgs(ctx, config.Name, args, withDependsOn(opts, dependencies)...)
+		if err != nil {
+			return err
+		}
+		t.Services[key] = service
+	}
+	return nil
+}
+
+// applyTaskSets creates the task sets of the spec, for the clusters, services and task definitions they reference.
+func (t *Topology) applyTaskSets(ctx *pulumi.Context, spec *Spec, opts []pulumi.ResourceOption) error {
+	for _, key := range sortedKeys(spec.TaskSets) {
+		config := spec.TaskSets[key]
+		args := config.ToArgs()
+		if cluster, ok := t.Clusters[config.Cluster]; ok {
+			args.Cluster = cluster.Arn
+		}
+		if service, ok := t.Services[config.Service]; ok {
+			args.Service = service.Arn
+		}
+		if taskDefinition, ok := t.TaskDefinitions[config.TaskDefinition]; ok {
+			args.TaskDefinition = taskDefinition.Arn
+		}
+
+		taskSets, err := NewTaskSetsFromArgs(ctx, []*TaskSetArgs{args}, opts...)
+		if err != nil {
+			return err
+		}
+		t.TaskSets[key] = taskSets[0]
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in ascending order, so that resources are created in a stable order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// withDependsOn returns a copy of opts that also makes the resource depend on dependencies.
+func withDependsOn(opts []pulumi.ResourceOption, dependencies []pulumi.Resource) []pulumi.ResourceOption {
+	if len(dependencies) == 0 {
+		return opts
+	}
+	return append(slices.Clone(opts), pulumi.DependsOn(dependencies))
+}
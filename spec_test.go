@@ -0,0 +1,69 @@
+package ecs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplySpec is a unit test that checks that the spec in examples/Spec/spec.json is created with its references resolved.
+func TestApplySpec(t *testing.T) {
+	spec, err := LoadSpec("examples/Spec/spec.json")
+	assert.NoError(t, err)
+
+	m := newMocks()
+	runWithMocks(t, m, func(ctx *pulumi.Context) error {
+		_, err := Apply(ctx, spec)
+		return err
+	})
+
+	assert.Equal(t, 1, m.count("aws:ecs/accountSettingDefault:AccountSettingDefault"))
+	assert.Equal(t, 1, m.count("aws:ecs/cluster:Cluster"))
+	assert.Equal(t, 1, m.count("aws:ecs/taskDefinition:TaskDefinition"))
+
+	clusterCapacityProviders := m.resource(t, "aws:ecs/clusterCapacityProviders:ClusterCapacityProviders", "my-cluster")
+	assert.Equal(t, resource.NewStringProperty("my-cluster"), clusterCapacityProviders.Inputs["clusterName"])
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-cluster"), service.Inputs["cluster"])
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-task-definition"), service.Inputs["taskDefinition"])
+}
+
+// TestSpecValidate is a unit test that checks that unknown references and invalid resources in a spec are reported together.
+func TestSpecValidate(t *testing.T) {
+	spec, err := LoadSpec("examples/Spec/spec.json")
+	assert.NoError(t, err)
+
+	service := spec.Services["api"]
+	service.Cluster = "missing"
+	spec.Services["api"] = service
+	taskDefinition := spec.TaskDefinitions["api"]
+	taskDefinition.ContainerDefinitions[0].Image = ""
+	spec.TaskDefinitions["api"] = taskDefinition
+
+	m := newMocks()
+	err = pulumi.RunErr(func(ctx *pulumi.Context) error {
+		_, err := Apply(ctx, spec)
+		return err
+	}, pulumi.WithMocks("project", "stack", m))
+
+	assert.Equal(t, map[string]string{
+		"services.api.cluster":                              `unknown cluster "missing"`,
+		"taskDefinitions.api.containerDefinitions[0].image": "is required",
+	}, fieldErrors(t, err))
+	assert.Equal(t, 0, m.count(ClusterType))
+}
+
+// TestLoadSpecUnknownField is a unit test that checks that a spec with an unknown field is rejected.
+func TestLoadSpecUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	err := os.WriteFile(path, []byte(`{"clusters": {"main": {"nmae": "my-cluster"}}}`), 0o600)
+	assert.NoError(t, err)
+
+	_, err = LoadSpec(path)
+	assert.ErrorContains(t, err, `unknown field "nmae"`)
+}
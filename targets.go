@@ -0,0 +1,263 @@
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/ecs"
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// ServiceTargetConfig defines a Network Load Balancer target group that the tasks of an AWS ECS service register
+// with on one of their container ports. A service can have several, for example one for gRPC and one for metrics.
+// The target group is reached through Listener, which is created on an existing Network Load Balancer. Unless set, the
+// target group forwards TCP traffic to IP targets and is health checked on the traffic port with the same protocol,
+// or TCP for UDP targets.
+type ServiceTargetConfig struct {
+	ContainerName       string                       `json:"containerName"`
+	ContainerPort       int                          `json:"containerPort"`
+	DeregistrationDelay *int                         `json:"deregistrationDelay,omitempty"`
+	HealthCheck         *ServiceTargetHealthCheck    `json:"healthCheck,omitempty"`
+	Listener            *ServiceTargetListenerConfig `json:"listener,omitempty"`
+	Name                string                       `json:"name"`
+	PreserveClientIP    *bool                        `json:"preserveClientIp,omitempty"`
+	Protocol            *string                      `json:"protocol,omitempty"`
+	ProxyProtocolV2     *bool                        `json:"proxyProtocolV2,omitempty"`
+	TargetType          *string                      `json:"targetType,omitempty"`
+	VpcID               string                       `json:"vpcId"`
+}
+
+// ServiceTargetHealthCheck defines the health check of a target group. Path is only used by HTTP and HTTPS health
+// checks.
+type ServiceTargetHealthCheck struct {
+	Path     *string `json:"path,omitempty"`
+	Port     *string `json:"port,omitempty"`
+	Protocol *string `json:"protocol,omitempty"`
+}
+
+// ServiceTargetListenerConfig defines a listener on an existing Network Load Balancer that forwards to a target
+// group. The listener uses the protocol of the target group; TLS listeners terminate TLS with CertificateArn.
+type ServiceTargetListenerConfig struct {
+	CertificateArn  *string `json:"certificateArn,omitempty"`
+	LoadBalancerArn string  `json:"loadBalancerArn"`
+	Port            int     `json:"port"`
+	SslPolicy       *string `json:"sslPolicy,omitempty"`
+}
+
+// ServiceTargetArgs defines inputs for a Network Load Balancer target group of an AWS ECS service. The name and
+// protocol are plain values, because they determine the names of the resources and how the target group is health
+// checked.
+type ServiceTargetArgs struct {
+	ContainerName       pulumi.StringInput
+	ContainerPort       pulumi.IntInput
+	DeregistrationDelay pulumi.IntPtrInput
+	HealthCheck         *lb.TargetGroupHealthCheckArgs
+	Listener            *ServiceTargetListenerArgs
+	Name                string
+	PreserveClientIP    pulumi.BoolPtrInput
+	Protocol            string
+	ProxyProtocolV2     pulumi.BoolPtrInput
+	TargetType          pulumi.StringPtrInput
+	VpcID               pulumi.StringInput
+}
+
+// ServiceTargetListenerArgs defines inputs for a listener on an existing Network Load Balancer.
+type ServiceTargetListenerArgs struct {
+	CertificateArn  pulumi.StringPtrInput
+	LoadBalancerArn pulumi.StringInput
+	Port            pulumi.IntInput
+	SslPolicy       pulumi.StringPtrInput
+}
+
+// targetProtocol returns the protocol of a target group, which defaults to TCP.
+func (c ServiceTargetConfig) targetProtocol() string {
+	if c.Protocol != nil {
+		return *c.Protocol
+	}
+	return "TCP"
+}
+
+// toArgs converts the target configuration into inputs, filling in the default protocol.
+func (c ServiceTargetConfig) toArgs() *ServiceTargetArgs {
+	args := &ServiceTargetArgs{
+		ContainerName:       pulumi.String(c.ContainerName),
+		ContainerPort:       pulumi.Int(c.ContainerPort),
+		DeregistrationDelay: pulumi.IntPtrFromPtr(c.DeregistrationDelay),
+		Name:                c.Name,
+		PreserveClientIP:    pulumi.BoolPtrFromPtr(c.PreserveClientIP),
+		Protocol:            c.targetProtocol(),
+		ProxyProtocolV2:     pulumi.BoolPtrFromPtr(c.ProxyProtocolV2),
+		TargetType:          pulumi.StringPtrFromPtr(c.TargetType),
+		VpcID:               pulumi.String(c.VpcID),
+	}
+
+	if c.HealthCheck != nil {
+		args.HealthCheck = &lb.TargetGroupHealthCheckArgs{
+			Path:     pulumi.StringPtrFromPtr(c.HealthCheck.Path),
+			Port:     pulumi.StringPtrFromPtr(c.HealthCheck.Port),
+			Protocol: pulumi.StringPtrFromPtr(c.HealthCheck.Protocol),
+		}
+	}
+	if c.Listener != nil {
+		args.Listener = &ServiceTargetListenerArgs{
+			CertificateArn:  pulumi.StringPtrFromPtr(c.Listener.CertificateArn),
+			LoadBalancerArn: pulumi.String(c.Listener.LoadBalancerArn),
+			Port:            pulumi.Int(c.Listener.Port),
+			SslPolicy:       pulumi.StringPtrFromPtr(c.Listener.SslPolicy),
+		}
+	}
+
+	return args
+}
+
+func (c ServiceTargetConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	v.required(path+".containerName", c.ContainerName)
+	v.between(path+".containerPort", &c.ContainerPort, 1, 65535)
+	v.required(path+".vpcId", c.VpcID)
+	v.oneOf(path+".protocol", c.Protocol, "TCP", "UDP", "TLS", "TCP_UDP")
+	v.oneOf(path+".targetType", c.TargetType, "ip", "instance")
+	v.between(path+".deregistrationDelay", c.DeregistrationDelay, 0, 3600)
+	if c.Listener == nil {
+		v.errorf(path+".listener", "is required")
+	}
+
+	if health := c.HealthCheck; health != nil {
+		v.oneOf(path+".healthCheck.protocol", health.Protocol, "TCP", "HTTP", "HTTPS")
+		if health.Path != nil && (health.Protocol == nil || *health.Protocol == "TCP") {
+			v.errorf(path+".healthCheck.path", "is only supported by HTTP and HTTPS health checks")
+		}
+	}
+
+	if listener := c.Listener; listener != nil {
+		listenerPath := path + ".listener"
+		v.required(listenerPath+".loadBalancerArn", listener.LoadBalancerArn)
+		v.between(listenerPath+".port", &listener.Port, 1, 65535)
+		tls := c.targetProtocol() == "TLS"
+		if tls && listener.CertificateArn == nil {
+			v.errorf(listenerPath+".certificateArn", "is required for TLS targets")
+		}
+		if !tls && (listener.CertificateArn != nil || listener.SslPolicy != nil) {
+			v.errorf(listenerPath, "certificateArn and sslPolicy are only supported by TLS targets")
+		}
+	}
+}
+
+// validateServiceTargets checks the targets of a service, whose names and container ports must be unique.
+func validateServiceTargets(v *validator, path string, targets []ServiceTargetConfig) {
+	names := map[string]bool{}
+	ports := map[string]bool{}
+	for i, target := range targets {
+		targetPath := fmt.Sprintf("%s[%d]", path, i)
+		target.validate(v, targetPath)
+		if names[target.Name] {
+			v.errorf(targetPath+".name", "%q is used by another target", target.Name)
+		}
+		names[target.Name] = true
+
+		port := fmt.Sprintf("%s:%d/%s", target.ContainerName, target.ContainerPort, target.targetProtocol())
+		if ports[port] {
+			v.errorf(targetPath+".containerPort", "%d of %s is used by another target with the same protocol", target.ContainerPort, target.ContainerName)
+		}
+		ports[port] = true
+	}
+}
+
+// newServiceTargets creates the target groups of a service and the listeners that forward to them. It returns the
+// target groups by target name, the load balancer configurations that register the tasks of the service with them,
+// and the listeners, which have to be created before the service.
+func newServiceTargets(ctx *pulumi.Context, name string, targets []*ServiceTargetArgs, tags pulumi.StringMapInput, parent pulumi.Resource) (map[string]*lb.TargetGroup, ecs.ServiceLoadBalancerArray, []pulumi.Resource, error) {
+	targetGroups := map[string]*lb.TargetGroup{}
+	var loadBalancers ecs.ServiceLoadBalancerArray
+	var listeners []pulumi.Resource
+	for _, target := range targets {
+		targetGroup, listener, err := newServiceTarget(ctx, name+"-"+target.Name, target, tags, parent)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		targetGroups[target.Name] = targetGroup
+		listeners = append(listeners, listener)
+
+		loadBalancers = append(loadBalancers, &ecs.ServiceLoadBalancerArgs{
+			ContainerName:  target.ContainerName,
+			ContainerPort:  target.ContainerPort,
+			TargetGroupArn: targetGroup.Arn,
+		})
+	}
+	return targetGroups, loadBalancers, listeners, nil
+}
+
+// newServiceTarget creates the target group of a target and the listener that forwards to it.
+func newServiceTarget(ctx *pulumi.Context, name string, args *ServiceTargetArgs, tags pulumi.StringMapInput, parent pulumi.Resource) (*lb.TargetGroup, *lb.Listener, error) {
+	if args.Listener == nil {
+		return nil, nil, fmt.Errorf("target %s requires a listener", args.Name)
+	}
+
+	protocol := args.Protocol
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	targetType := args.TargetType
+	if targetType == nil {
+		targetType = pulumi.String("ip")
+	}
+
+	// UDP cannot be health checked, so UDP targets are checked over TCP unless set.
+	healthCheck := &lb.TargetGroupHealthCheckArgs{}
+	if args.HealthCheck != nil {
+		*healthCheck = *args.HealthCheck
+	}
+	if healthCheck.Protocol == nil && (protocol == "UDP" || protocol == "TCP_UDP") {
+		healthCheck.Protocol = pulumi.String("TCP")
+	}
+
+	targetGroup, err := lb.NewTargetGroup(ctx, name, &lb.TargetGroupArgs{
+		DeregistrationDelay: args.DeregistrationDelay,
+		HealthCheck:         healthCheck,
+		Port:                args.ContainerPort,
+		PreserveClientIp:    boolStringPtr(args.PreserveClientIP),
+		Protocol:            pulumi.String(protocol),
+		ProxyProtocolV2:     args.ProxyProtocolV2,
+		Tags:                tags,
+		TargetType:          targetType,
+		VpcId:               args.VpcID,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create new target group: %v", err)
+	}
+
+	listener, err := lb.NewListener(ctx, name, &lb.ListenerArgs{
+		CertificateArn: args.Listener.CertificateArn,
+		DefaultActions: lb.ListenerDefaultActionArray{
+			&lb.ListenerDefaultActionArgs{
+				TargetGroupArn: targetGroup.Arn,
+				Type:           pulumi.String("forward"),
+			},
+		},
+		LoadBalancerArn: args.Listener.LoadBalancerArn,
+		Port:            args.Listener.Port,
+		Protocol:        pulumi.String(protocol),
+		SslPolicy:       args.Listener.SslPolicy,
+		Tags:            tags,
+	}, pulumi.Parent(parent))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create new listener: %v", err)
+	}
+	return targetGroup, listener, nil
+}
+
+// boolStringPtr converts an optional boolean into the string the provider expects for boolean target group
+// attributes such as preserve_client_ip.
+func boolStringPtr(value pulumi.BoolPtrInput) pulumi.StringPtrInput {
+	if value == nil {
+		return nil
+	}
+	return value.ToBoolPtrOutput().ApplyT(func(value *bool) *string {
+		if value == nil {
+			return nil
+		}
+		s := strconv.FormatBool(*value)
+		return &s
+	}).(pulumi.StringPtrOutput)
+}
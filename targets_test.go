@@ -0,0 +1,120 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-aws/sdk/v6/go/aws/lb"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+)
+
+const networkLoadBalancerArn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/net/my-nlb/0123456789abcdef"
+
+// targetsServiceFixture returns the service fixture behind a Network Load Balancer, on a gRPC and a metrics port.
+func targetsServiceFixture(t *testing.T) (*ServiceConfig, createService) {
+	serviceConfig, create := serviceFixture(t)
+	serviceConfig.LoadBalancers = nil
+	serviceConfig.Targets = []ServiceTargetConfig{
+		{
+			ContainerName:   "my-container",
+			ContainerPort:   50051,
+			Listener:        &ServiceTargetListenerConfig{CertificateArn: pulumi.StringRef("arn:aws:acm:us-west-2:123456789012:certificate/my-certificate"), LoadBalancerArn: networkLoadBalancerArn, Port: 443},
+			Name:            "grpc",
+			Protocol:        pulumi.StringRef("TLS"),
+			ProxyProtocolV2: pulumi.BoolRef(true),
+			VpcID:           "vpc-0123456789abcdef0",
+		},
+		{
+			ContainerName:    "my-container",
+			ContainerPort:    9090,
+			HealthCheck:      &ServiceTargetHealthCheck{Path: pulumi.StringRef("/metrics"), Protocol: pulumi.StringRef("HTTP")},
+			Listener:         &ServiceTargetListenerConfig{LoadBalancerArn: networkLoadBalancerArn, Port: 9090},
+			Name:             "metrics",
+			PreserveClientIP: pulumi.BoolRef(false),
+			VpcID:            "vpc-0123456789abcdef0",
+		},
+	}
+	return serviceConfig, create
+}
+
+// TestNewServiceTargets is a unit test that checks the target groups and listeners created for the targets of a service and how they are wired to it.
+func TestNewServiceTargets(t *testing.T) {
+	_, create := targetsServiceFixture(t)
+
+	m := create(nil)
+
+	grpc := m.resource(t, "aws:lb/targetGroup:TargetGroup", "my-ecs-service-grpc")
+	assert.Equal(t, componentURN(ServiceType, "my-ecs-service"), grpc.Parent)
+	assert.Equal(t, resource.NewStringProperty("TLS"), grpc.Inputs["protocol"])
+	assert.Equal(t, resource.NewBoolProperty(true), grpc.Inputs["proxyProtocolV2"])
+
+	metrics := m.resource(t, "aws:lb/targetGroup:TargetGroup", "my-ecs-service-metrics")
+	assert.Equal(t, resource.NewStringProperty("TCP"), metrics.Inputs["protocol"])
+	assert.Equal(t, resource.NewStringProperty("false"), metrics.Inputs["preserveClientIp"])
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"path":     "/metrics",
+		"protocol": "HTTP",
+	}), metrics.Inputs["healthCheck"].ObjectValue())
+
+	listener := m.resource(t, "aws:lb/listener:Listener", "my-ecs-service-grpc")
+	assert.Equal(t, resource.NewStringProperty(networkLoadBalancerArn), listener.Inputs["loadBalancerArn"])
+	assert.Equal(t, resource.NewStringProperty("TLS"), listener.Inputs["protocol"])
+	assert.Equal(t, 2, m.count("aws:lb/listener:Listener"))
+
+	service := m.resource(t, "aws:ecs/service:Service", "my-ecs-service")
+	assert.Equal(t, resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+			"containerName":  "my-container",
+			"containerPort":  50051,
+			"targetGroupArn": "arn:aws:mock:us-west-2:123456789012:my-ecs-service-grpc",
+		})),
+		resource.NewObjectProperty(resource.NewPropertyMapFromMap(map[string]interface{}{
+			"containerName":  "my-container",
+			"containerPort":  9090,
+			"targetGroupArn": "arn:aws:mock:us-west-2:123456789012:my-ecs-service-metrics",
+		})),
+	}), service.Inputs["loadBalancers"])
+}
+
+// TestNewServiceFromArgsTargets is a unit test that checks that the listeners of targets accept the ARN of a load balancer created in the same program.
+func TestNewServiceFromArgsTargets(t *testing.T) {
+	_, create := targetsServiceFixture(t)
+
+	m := create(func(ctx *pulumi.Context, args *ServiceArgs) error {
+		loadBalancer, err := lb.NewLoadBalancer(ctx, "my-nlb", &lb.LoadBalancerArgs{
+			LoadBalancerType: pulumi.String("network"),
+		})
+		if err != nil {
+			return err
+		}
+
+		args.Targets[0].Listener.LoadBalancerArn = loadBalancer.Arn
+		return nil
+	})
+
+	listener := m.resource(t, "aws:lb/listener:Listener", "my-ecs-service-grpc")
+	assert.Equal(t, resource.NewStringProperty("arn:aws:mock:us-west-2:123456789012:my-nlb"), listener.Inputs["loadBalancerArn"])
+}
+
+// TestValidateServiceTargets is a unit test that checks the checks of the targets of a service.
+func TestValidateServiceTargets(t *testing.T) {
+	serviceConfig, _ := targetsServiceFixture(t)
+	serviceConfig.Targets[0].Listener.CertificateArn = nil
+	serviceConfig.Targets[1].HealthCheck.Protocol = nil
+	serviceConfig.Targets = append(serviceConfig.Targets, ServiceTargetConfig{
+		ContainerName: "my-container",
+		ContainerPort: 9090,
+		Name:          "metrics",
+		Protocol:      pulumi.StringRef("HTTP"),
+		VpcID:         "vpc-0123456789abcdef0",
+	})
+
+	assert.Equal(t, map[string]string{
+		"service.targets[0].listener.certificateArn": "is required for TLS targets",
+		"service.targets[1].healthCheck.path":        "is only supported by HTTP and HTTPS health checks",
+		"service.targets[2].listener":                "is required",
+		"service.targets[2].name":                    `"metrics" is used by another target`,
+		"service.targets[2].protocol":                `must be one of TCP, UDP, TLS, TCP_UDP, got "HTTP"`,
+	}, fieldErrors(t, serviceConfig.Validate()))
+}
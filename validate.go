@@ -0,0 +1,763 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes a problem with a single configuration field.
+type FieldError struct {
+	// Path is the JSON path of the field, e.g. service.networkConfiguration.subnets.
+	Path string
+	// Message describes the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationError holds every problem found while validating a configuration.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	var messages []string
+	for _, fieldError := range e.Errors {
+		messages = append(messages, fieldError.Error())
+	}
+	return fmt.Sprintf("invalid configuration:\n  %s", strings.Join(messages, "\n  "))
+}
+
+// validator collects field errors.
+type validator struct {
+	errors []FieldError
+}
+
+// errorf records a problem with the field at path.
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the collected problems as a *ValidationError, or nil if there are none.
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+// required records a problem if value is empty.
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.errorf(path, "is required")
+	}
+}
+
+// oneOf records a problem if value is set to anything other than one of allowed.
+func (v *validator) oneOf(path string, value *string, allowed ...string) {
+	if value != nil && !slices.Contains(allowed, *value) {
+		v.errorf(path, "must be one of %s, got %q", strings.Join(allowed, ", "), *value)
+	}
+}
+
+// between records a problem if value is set to a number outside [min, max].
+func (v *validator) between(path string, value *int, min, max int) {
+	if value != nil && (*value < min || *value > max) {
+		v.errorf(path, "must be between %d and %d, got %d", min, max, *value)
+	}
+}
+
+// Validate checks the capacity provider configuration and returns a *ValidationError listing every problem found.
+func (c CapacityProviderConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "capacityProvider")
+	return v.err()
+}
+
+func (c CapacityProviderConfig) validate(v *validator, path string) {
+	validateCapacityProviderName(v, path+".name", c.Name)
+
+	provider := c.AutoscalingGroupProvider
+	providerPath := path + ".autoscalingGroupProvider"
+	v.required(providerPath+".autoscalingGroupArn", provider.AutoscalingGroupArn)
+	if provider.AutoscalingGroupArn != "" && !strings.HasPrefix(provider.AutoscalingGroupArn, "arn:") {
+		v.errorf(providerPath+".autoscalingGroupArn", "must be an ARN, got %q", provider.AutoscalingGroupArn)
+	}
+	v.oneOf(providerPath+".managedDraining", provider.ManagedDraining, "ENABLED", "DISABLED")
+	v.oneOf(providerPath+".managedTerminationProtection", provider.ManagedTerminationProtection, "ENABLED", "DISABLED")
+
+	scaling := provider.ManagedScaling
+	scalingPath := providerPath + ".managedScaling"
+	if provider.ManagedTerminationProtection != nil && *provider.ManagedTerminationProtection == "ENABLED" &&
+		(scaling == nil || scaling.Status == nil || *scaling.Status != "ENABLED") {
+		v.errorf(providerPath+".managedTerminationProtection", "requires managedScaling.status to be ENABLED")
+	}
+	if scaling == nil {
+		return
+	}
+	v.oneOf(scalingPath+".status", scaling.Status, "ENABLED", "DISABLED")
+	v.between(scalingPath+".targetCapacity", scaling.TargetCapacity, 1, 100)
+	v.between(scalingPath+".instanceWarmupPeriod", scaling.InstanceWarmupPeriod, 0, 10000)
+	v.between(scalingPath+".minimumScalingStepSize", scaling.MinimumScalingStepSize, 1, 10000)
+	v.between(scalingPath+".maximumScalingStepSize", scaling.MaximumScalingStepSize, 1, 10000)
+	if scaling.MinimumScalingStepSize != nil && scaling.MaximumScalingStepSize != nil &&
+		*scaling.MinimumScalingStepSize > *scaling.MaximumScalingStepSize {
+		v.errorf(scalingPath+".minimumScalingStepSize", "(%d) must not be greater than maximumScalingStepSize (%d)",
+			*scaling.MinimumScalingStepSize, *scaling.MaximumScalingStepSize)
+	}
+}
+
+// validateCapacityProviderName checks that a capacity provider name is set and does not use a reserved prefix.
+func validateCapacityProviderName(v *validator, path, name string) {
+	v.required(path, name)
+	for _, prefix := range []string{"aws", "ecs", "fargate"} {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			v.errorf(path, "must not start with %q", prefix)
+		}
+	}
+}
+
+// Validate checks the cluster configuration and returns a *ValidationError listing every problem found.
+func (c ClusterConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "cluster")
+	return v.err()
+}
+
+func (c ClusterConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+
+	if c.Configuration != nil {
+		executeCommand := c.Configuration.ExecuteCommand
+		executeCommandPath := path + ".configuration.executeCommand"
+		v.oneOf(executeCommandPath+".logging", executeCommand.Logging, "NONE", "DEFAULT", "OVERRIDE")
+		override := executeCommand.Logging != nil && *executeCommand.Logging == "OVERRIDE"
+		if override && executeCommand.LogConfiguration == nil {
+			v.errorf(executeCommandPath+".logConfiguration", "is required when logging is OVERRIDE")
+		}
+		if !override && executeCommand.LogConfiguration != nil {
+			v.errorf(executeCommandPath+".logConfiguration", "is only allowed when logging is OVERRIDE")
+		}
+	}
+
+	if c.ServiceConnectDefaults != nil {
+		v.required(path+".serviceConnectDefaults.namespace", c.ServiceConnectDefaults.Namespace)
+	}
+
+	for i, setting := range c.Settings {
+		settingPath := fmt.Sprintf("%s.settings[%d]", path, i)
+		v.oneOf(settingPath+".name", &setting.Name, "containerInsights")
+		v.oneOf(settingPath+".value", &setting.Value, "enabled", "disabled", "enhanced")
+	}
+}
+
+// Validate checks the service configuration and returns a *ValidationError listing every problem found.
+func (c ServiceConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "service")
+	return v.err()
+}
+
+func (c ServiceConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	v.required(path+".clusterArn", c.ClusterArn)
+
+	c.validateLaunch(v, path)
+	c.validateDeployment(v, path)
+	c.validateBlueGreen(v, path)
+	c.validateIngress(v, path)
+	c.validateTargets(v, path)
+	c.validateAutoScaling(v, path)
+	c.validateNetwork(v, path)
+	c.validateLoadBalancers(v, path)
+	c.validatePlacement(v, path)
+	c.validateServiceDiscovery(v, path)
+}
+
+// fargate reports whether the service runs on the FARGATE launch type.
+func (c ServiceConfig) fargate() bool {
+	return c.LaunchType != nil && *c.LaunchType == "FARGATE"
+}
+
+// deploymentControllerType returns the type of the deployment controller, or the empty string if it is not set.
+func (c ServiceConfig) deploymentControllerType() string {
+	if c.DeploymentController == nil {
+		return ""
+	}
+	return stringValue(c.DeploymentController.Type)
+}
+
+// validateLaunch checks the launch type, capacity provider strategies and scheduling strategy of the service.
+func (c ServiceConfig) validateLaunch(v *validator, path string) {
+	v.oneOf(path+".launchType", c.LaunchType, "EC2", "FARGATE", "EXTERNAL")
+	if c.LaunchType != nil && len(c.CapacityProviderStrategies) > 0 {
+		v.errorf(path+".capacityProviderStrategies", "must not be set together with launchType")
+	}
+	for i, strategy := range c.CapacityProviderStrategies {
+		strategyPath := fmt.Sprintf("%s.capacityProviderStrategies[%d]", path, i)
+		v.required(strategyPath+".name", strategy.CapacityProvider)
+		v.between(strategyPath+".base", strategy.Base, 0, 100000)
+		v.between(strategyPath+".weight", strategy.Weight, 0, 1000)
+	}
+
+	v.oneOf(path+".schedulingStrategy", c.SchedulingStrategy, "REPLICA", "DAEMON")
+	if c.fargate() && c.SchedulingStrategy != nil && *c.SchedulingStrategy == "DAEMON" {
+		v.errorf(path+".schedulingStrategy", "DAEMON is not supported by the FARGATE launch type")
+	}
+}
+
+// validateDeployment checks the desired count and deployment settings of the service.
+func (c ServiceConfig) validateDeployment(v *validator, path string) {
+	if c.DesiredCount != nil && *c.DesiredCount < 0 {
+		v.errorf(path+".desiredCount", "must not be negative, got %d", *c.DesiredCount)
+	}
+	v.between(path+".deploymentMinimumHealthyPercent", c.DeploymentMinimumHealthyPercent, 0, 100)
+	if c.DeploymentMinimumHealthyPercent != nil && c.DeploymentMaximumPercent != nil &&
+		*c.DeploymentMinimumHealthyPercent > *c.DeploymentMaximumPercent {
+		v.errorf(path+".deploymentMinimumHealthyPercent", "(%d) must not be greater than deploymentMaximumPercent (%d)",
+			*c.DeploymentMinimumHealthyPercent, *c.DeploymentMaximumPercent)
+	}
+	if c.DeploymentController != nil {
+		v.oneOf(path+".deploymentController.type", c.DeploymentController.Type, "ECS", "CODE_DEPLOY", "EXTERNAL")
+	}
+	v.oneOf(path+".propagateTags", c.PropagateTags, "SERVICE", "TASK_DEFINITION", "NONE")
+}
+
+// validateBlueGreen checks the blue/green deployment of the service.
+func (c ServiceConfig) validateBlueGreen(v *validator, path string) {
+	if c.BlueGreen == nil {
+		return
+	}
+	c.BlueGreen.validate(v, path+".blueGreen")
+	if c.DeploymentController != nil && c.deploymentControllerType() != "CODE_DEPLOY" {
+		v.errorf(path+".deploymentController.type", "must be CODE_DEPLOY for blue/green deployments")
+	}
+	if len(c.LoadBalancers) > 0 {
+		v.errorf(path+".loadBalancers", "must not be set together with blueGreen")
+	}
+}
+
+// validateIngress checks the ingress of the service.
+func (c ServiceConfig) validateIngress(v *validator, path string) {
+	if c.Ingress == nil {
+		return
+	}
+	c.Ingress.validate(v, path+".ingress")
+	if c.BlueGreen != nil {
+		v.errorf(path+".ingress", "must not be set together with blueGreen")
+	}
+	if c.deploymentControllerType() == "EXTERNAL" {
+		v.errorf(path+".ingress", "is not supported by the EXTERNAL deployment controller")
+	}
+	if c.Ingress.LoadBalancerSecurityGroupID != nil && (c.NetworkConfiguration == nil || len(c.NetworkConfiguration.SecurityGroups) == 0) {
+		v.errorf(path+".networkConfiguration.securityGroups", "must contain at least one security group when ingress.loadBalancerSecurityGroupId is set")
+	}
+}
+
+// validateTargets checks the load balancer targets of the service.
+func (c ServiceConfig) validateTargets(v *validator, path string) {
+	if len(c.Targets) == 0 {
+		return
+	}
+	validateServiceTargets(v, path+".targets", c.Targets)
+	if c.BlueGreen != nil {
+		v.errorf(path+".targets", "must not be set together with blueGreen")
+	}
+	if c.deploymentControllerType() == "EXTERNAL" {
+		v.errorf(path+".targets", "are not supported by the EXTERNAL deployment controller")
+	}
+}
+
+// validateAutoScaling checks the auto scaling of the service.
+func (c ServiceConfig) validateAutoScaling(v *validator, path string) {
+	if c.AutoScaling == nil {
+		return
+	}
+	c.AutoScaling.validate(v, path+".autoScaling")
+	if c.SchedulingStrategy != nil && *c.SchedulingStrategy == "DAEMON" {
+		v.errorf(path+".autoScaling", "is not supported by the DAEMON scheduling strategy")
+	}
+}
+
+// validateNetwork checks the network configuration of the service.
+func (c ServiceConfig) validateNetwork(v *validator, path string) {
+	if c.NetworkConfiguration == nil && c.fargate() {
+		v.errorf(path+".networkConfiguration", "is required for the FARGATE launch type")
+	}
+	if c.NetworkConfiguration != nil && len(c.NetworkConfiguration.Subnets) == 0 {
+		v.errorf(path+".networkConfiguration.subnets", "must contain at least one subnet")
+	}
+}
+
+// validateLoadBalancers checks the load balancers of the service and the health check grace period that applies to
+// them.
+func (c ServiceConfig) validateLoadBalancers(v *validator, path string) {
+	for i, loadBalancer := range c.LoadBalancers {
+		loadBalancerPath := fmt.Sprintf("%s.loadBalancers[%d]", path, i)
+		v.required(loadBalancerPath+".containerName", loadBalancer.ContainerName)
+		v.between(loadBalancerPath+".containerPort", &loadBalancer.ContainerPort, 1, 65535)
+		if (loadBalancer.ElbName == nil) == (loadBalancer.TargetGroupArn == nil) {
+			v.errorf(loadBalancerPath, "must set exactly one of elbName and targetGroupArn")
+		}
+	}
+	if c.HealthCheckGracePeriodSeconds != nil && len(c.LoadBalancers) == 0 && c.BlueGreen == nil && c.Ingress == nil && len(c.Targets) == 0 {
+		v.errorf(path+".healthCheckGracePeriodSeconds", "is only allowed when loadBalancers, blueGreen, ingress or targets are set")
+	}
+}
+
+// validatePlacement checks the placement strategies and constraints of the service.
+func (c ServiceConfig) validatePlacement(v *validator, path string) {
+	for i, strategy := range c.OrderedPlacementStrategies {
+		strategyPath := fmt.Sprintf("%s.orderedPlacementStrategies[%d]", path, i)
+		v.oneOf(strategyPath+".type", &strategy.Type, "random", "spread", "binpack")
+		if c.fargate() {
+			v.errorf(strategyPath, "is not supported by the FARGATE launch type")
+		}
+	}
+	for i, constraint := range c.PlacementConstraints {
+		constraintPath := fmt.Sprintf("%s.placementConstraints[%d]", path, i)
+		v.oneOf(constraintPath+".type", &constraint.Type, "distinctInstance", "memberOf")
+		if constraint.Type == "memberOf" && constraint.Expression == nil {
+			v.errorf(constraintPath+".expression", "is required for memberOf constraints")
+		}
+	}
+}
+
+// validateServiceDiscovery checks the Service Connect configuration and the service registry of the service.
+func (c ServiceConfig) validateServiceDiscovery(v *validator, path string) {
+	if c.ServiceConnectConfiguration != nil {
+		for i, service := range c.ServiceConnectConfiguration.Services {
+			servicePath := fmt.Sprintf("%s.serviceConnectConfiguration.services[%d]", path, i)
+			v.required(servicePath+".portName", service.PortName)
+			for j, clientAlias := range service.ClientAlias {
+				v.between(fmt.Sprintf("%s.clientAlias[%d].port", servicePath, j), &clientAlias.Port, 1, 65535)
+			}
+		}
+	}
+
+	if c.ServiceRegistry != nil {
+		v.required(path+".serviceRegistry.registryArn", c.ServiceRegistry.RegistryArn)
+	}
+}
+
+// Validate checks the task definition configuration and returns a *ValidationError listing every problem found.
+func (c TaskDefinitionConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "taskDefinition")
+	return v.err()
+}
+
+func (c TaskDefinitionConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+
+	v.oneOf(path+".networkMode", c.NetworkMode, "none", "bridge", "awsvpc", "host")
+	v.oneOf(path+".ipcMode", c.IpcMode, "host", "task", "none")
+	v.oneOf(path+".pidMode", c.PidMode, "host", "task")
+
+	c.validateCompatibilities(v, path)
+	volumes := c.validateVolumes(v, path)
+	c.validateContainers(v, path, volumes)
+	validateContainerDependencies(v, path+".containerDefinitions", c.ContainerDefinitions, c.NetworkMode)
+	c.validateSidecars(v, path)
+	c.validateRoles(v, path)
+}
+
+// awsvpc reports whether the task definition uses the awsvpc network mode.
+func (c TaskDefinitionConfig) awsvpc() bool {
+	return c.NetworkMode != nil && *c.NetworkMode == "awsvpc"
+}
+
+// hasExecutionRole reports whether the task definition creates or references an execution role.
+func (c TaskDefinitionConfig) hasExecutionRole() bool {
+	return c.ExecutionRole != nil || c.ExecutionRoleArn != nil
+}
+
+// validateCompatibilities checks the launch types the task definition requires, and the network mode and size that
+// FARGATE requires.
+func (c TaskDefinitionConfig) validateCompatibilities(v *validator, path string) {
+	for i, compatibility := range c.RequiresCompatibilities {
+		v.oneOf(fmt.Sprintf("%s.requiresCompatibilities[%d]", path, i), &compatibility, "EC2", "FARGATE", "EXTERNAL")
+	}
+	if !slices.Contains(c.RequiresCompatibilities, "FARGATE") {
+		return
+	}
+	if !c.awsvpc() {
+		networkMode := "bridge"
+		if c.NetworkMode != nil {
+			networkMode = *c.NetworkMode
+		}
+		v.errorf(path+".networkMode", "must be awsvpc for FARGATE, got %q", networkMode)
+	}
+	c.validateFargateSize(v, path)
+}
+
+// validateVolumes checks the volumes of the task definition and returns their names.
+func (c TaskDefinitionConfig) validateVolumes(v *validator, path string) []string {
+	var volumes []string
+	for i, volume := range c.Volumes {
+		volumePath := fmt.Sprintf("%s.volumes[%d].name", path, i)
+		v.required(volumePath, volume.Name)
+		if slices.Contains(volumes, volume.Name) {
+			v.errorf(volumePath, "duplicate volume %q", volume.Name)
+		}
+		volumes = append(volumes, volume.Name)
+	}
+	return volumes
+}
+
+// validateContainers checks the container definitions of the task definition, whose mount points may reference
+// volumes.
+func (c TaskDefinitionConfig) validateContainers(v *validator, path string, volumes []string) {
+	if len(c.ContainerDefinitions) == 0 {
+		v.errorf(path+".containerDefinitions", "must contain at least one container definition")
+	}
+	var containers []string
+	essential := false
+	for i, container := range c.ContainerDefinitions {
+		containerPath := fmt.Sprintf("%s.containerDefinitions[%d]", path, i)
+		if container.Name != "" && slices.Contains(containers, container.Name) {
+			v.errorf(containerPath+".name", "duplicate container %q", container.Name)
+		}
+		containers = append(containers, container.Name)
+		if container.Essential == nil || *container.Essential {
+			essential = true
+		}
+		validateContainer(v, containerPath, container, volumes, c.awsvpc())
+	}
+	if len(c.ContainerDefinitions) > 0 && !essential {
+		v.errorf(path+".containerDefinitions", "must contain at least one essential container")
+	}
+}
+
+// validateContainer checks a single container definition.
+func validateContainer(v *validator, path string, container ContainerDefinition, volumes []string, awsvpc bool) {
+	v.required(path+".name", container.Name)
+	v.required(path+".image", container.Image)
+	if container.Memory != nil && container.MemoryReservation != nil && *container.MemoryReservation > *container.Memory {
+		v.errorf(path+".memoryReservation", "(%d) must not be greater than memory (%d)",
+			*container.MemoryReservation, *container.Memory)
+	}
+
+	for i, portMapping := range container.PortMappings {
+		portMappingPath := fmt.Sprintf("%s.portMappings[%d]", path, i)
+		if portMapping.ContainerPort == nil && portMapping.ContainerPortRange == nil {
+			v.errorf(portMappingPath+".containerPort", "is required")
+		}
+		v.between(portMappingPath+".containerPort", portMapping.ContainerPort, 1, 65535)
+		v.between(portMappingPath+".hostPort", portMapping.HostPort, 0, 65535)
+		v.oneOf(portMappingPath+".protocol", portMapping.Protocol, "tcp", "udp")
+		if awsvpc && portMapping.HostPort != nil && portMapping.ContainerPort != nil &&
+			*portMapping.HostPort != *portMapping.ContainerPort {
+			v.errorf(portMappingPath+".hostPort", "must equal containerPort (%d) in awsvpc network mode, got %d",
+				*portMapping.ContainerPort, *portMapping.HostPort)
+		}
+	}
+
+	for i, mountPoint := range container.MountPoints {
+		if mountPoint.SourceVolume != nil && !slices.Contains(volumes, *mountPoint.SourceVolume) {
+			v.errorf(fmt.Sprintf("%s.mountPoints[%d].sourceVolume", path, i), "unknown volume %q", *mountPoint.SourceVolume)
+		}
+	}
+}
+
+// validateSidecars checks the log router and the collector that are added to the task definition.
+func (c TaskDefinitionConfig) validateSidecars(v *validator, path string) {
+	if c.LogRouting != nil {
+		c.LogRouting.validate(v, path+".logRouting", c.ContainerDefinitions)
+		if c.LogRouting.APIKey != nil && !c.hasExecutionRole() {
+			v.errorf(path+".logRouting.apiKey", "requires an executionRole or executionRoleArn")
+		}
+	}
+	if c.Observability != nil {
+		c.Observability.validate(v, path+".observability", c.ContainerDefinitions)
+		if c.Observability.ConfigParameter != nil && !c.hasExecutionRole() {
+			v.errorf(path+".observability.configParameter", "requires an executionRole or executionRoleArn")
+		}
+	}
+}
+
+// validateRoles checks the roles of the task definition and the log groups and secrets that need an execution role.
+func (c TaskDefinitionConfig) validateRoles(v *validator, path string) {
+	if c.Logging != nil {
+		c.Logging.validate(v, path+".logging")
+		if !c.hasExecutionRole() {
+			v.errorf(path+".logging", "requires an executionRole or executionRoleArn")
+		}
+	}
+	if len(c.Secrets) > 0 {
+		validateTaskSecrets(v, path+".secrets", c.Secrets, c.ContainerDefinitions)
+		if !c.hasExecutionRole() {
+			v.errorf(path+".secrets", "require an executionRole or executionRoleArn")
+		}
+	}
+	if c.ExecutionRole != nil {
+		c.ExecutionRole.validate(v, path+".executionRole")
+		if c.ExecutionRoleArn != nil {
+			v.errorf(path+".executionRoleArn", "must not be set together with executionRole")
+		}
+	}
+	if c.TaskRole != nil {
+		c.TaskRole.validate(v, path+".taskRole")
+		if c.TaskRoleArn != nil {
+			v.errorf(path+".taskRoleArn", "must not be set together with taskRole")
+		}
+	}
+}
+
+// validateContainerDependencies checks the dependsOn, links and volumesFrom of container definitions: that they
+// reference other containers of the task definition, that the conditions can be met and that no container waits for
+// itself, directly or through others.
+func validateContainerDependencies(v *validator, path string, containers []ContainerDefinition, networkMode *string) {
+	g := &containerGraph{v: v, byName: map[string]ContainerDefinition{}, edges: map[string][]string{}}
+	for _, container := range containers {
+		g.byName[container.Name] = container
+	}
+	bridge := networkMode == nil || *networkMode == "bridge"
+
+	for i, container := range containers {
+		containerPath := fmt.Sprintf("%s[%d]", path, i)
+		for j, dependency := range container.DependsOn {
+			g.dependsOn(fmt.Sprintf("%s.dependsOn[%d]", containerPath, j), container.Name, dependency)
+		}
+
+		for j, link := range container.Links {
+			linkPath := fmt.Sprintf("%s.links[%d]", containerPath, j)
+			if !bridge {
+				v.errorf(linkPath, "is only supported in bridge network mode")
+			}
+			name, _, _ := strings.Cut(link, ":")
+			g.reference(linkPath, container.Name, name)
+		}
+
+		for j, volumeFrom := range container.VolumesFrom {
+			volumePath := fmt.Sprintf("%s.volumesFrom[%d].sourceContainer", containerPath, j)
+			if volumeFrom.SourceContainer == nil {
+				v.errorf(volumePath, "is required")
+				continue
+			}
+			g.reference(volumePath, container.Name, *volumeFrom.SourceContainer)
+		}
+	}
+
+	g.validateCycles(path, containers)
+}
+
+// containerGraph collects the references between the container definitions of a task definition.
+type containerGraph struct {
+	v      *validator
+	byName map[string]ContainerDefinition
+	// edges holds the containers that each container starts after, in the order they are referenced.
+	edges map[string][]string
+}
+
+// reference records that container starts after the container called name, or a problem if there is no such other
+// container.
+func (g *containerGraph) reference(path, container, name string) {
+	switch _, ok := g.byName[name]; {
+	case name == container:
+		g.v.errorf(path, "must not reference the container itself")
+	case !ok:
+		g.v.errorf(path, "unknown container %q", name)
+	case !slices.Contains(g.edges[container], name):
+		g.edges[container] = append(g.edges[container], name)
+	}
+}
+
+// dependsOn records a dependency of container and checks that the container it waits for can meet its condition.
+func (g *containerGraph) dependsOn(path, container string, dependency ContainerDependency) {
+	g.reference(path+".containerName", container, dependency.ContainerName)
+	g.v.oneOf(path+".condition", &dependency.Condition, "START", "COMPLETE", "SUCCESS", "HEALTHY")
+
+	target, ok := g.byName[dependency.ContainerName]
+	if !ok || dependency.ContainerName == container {
+		return
+	}
+	switch dependency.Condition {
+	case "HEALTHY":
+		if target.HealthCheck == nil {
+			g.v.errorf(path+".condition", "HEALTHY requires container %q to have a healthCheck", target.Name)
+		}
+	case "COMPLETE", "SUCCESS":
+		// An essential container that exits stops the task, so it cannot be waited for.
+		if target.Essential == nil || *target.Essential {
+			g.v.errorf(path+".condition", "%s requires container %q to be non-essential", dependency.Condition, target.Name)
+		}
+	}
+}
+
+// validateCycles records a problem for every dependency cycle. A depth-first search finds the cycles; each is
+// reported once, at the container where the search entered it.
+func (g *containerGraph) validateCycles(path string, containers []ContainerDefinition) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, next := range g.edges[name] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				cycle := append(slices.Clone(stack[slices.Index(stack, next):]), next)
+				index := slices.IndexFunc(containers, func(c ContainerDefinition) bool { return c.Name == next })
+				g.v.errorf(fmt.Sprintf("%s[%d]", path, index), "has a dependency cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+	for _, container := range containers {
+		if state[container.Name] == unvisited {
+			visit(container.Name)
+		}
+	}
+}
+
+// containerWarnings returns warnings about container definitions that are valid but likely not what was meant.
+func containerWarnings(containers []ContainerDefinition) []string {
+	if len(containers) < 2 || slices.ContainsFunc(containers, func(c ContainerDefinition) bool { return c.Essential != nil && *c.Essential }) {
+		return nil
+	}
+	return []string{"no container is marked essential, so ECS treats every container that does not set essential as " +
+		"essential and stops the task when any of them exits"}
+}
+
+// fargateMemory lists the task memory sizes, in MiB, that Fargate supports for each task CPU size, in CPU units.
+var fargateMemory = map[int][]int{
+	256:   {512, 1024, 2048},
+	512:   memorySizes(1024, 4096, 1024),
+	1024:  memorySizes(2048, 8192, 1024),
+	2048:  memorySizes(4096, 16384, 1024),
+	4096:  memorySizes(8192, 30720, 1024),
+	8192:  memorySizes(16384, 61440, 4096),
+	16384: memorySizes(32768, 122880, 8192),
+}
+
+// memorySizes returns the memory sizes from min to max in increments of step.
+func memorySizes(min, max, step int) []int {
+	var sizes []int
+	for size := min; size <= max; size += step {
+		sizes = append(sizes, size)
+	}
+	return sizes
+}
+
+// validateFargateSize checks that the task CPU and memory are a combination that Fargate supports.
+func (c TaskDefinitionConfig) validateFargateSize(v *validator, path string) {
+	if c.CPU == nil {
+		v.errorf(path+".cpu", "is required for FARGATE")
+	}
+	if c.Memory == nil {
+		v.errorf(path+".memory", "is required for FARGATE")
+	}
+	if c.CPU == nil || c.Memory == nil {
+		return
+	}
+
+	cpu, ok := parseTaskSize(*c.CPU, "vcpu")
+	if !ok {
+		v.errorf(path+".cpu", "must be a number of CPU units or vCPUs, e.g. 1024 or \"1 vCPU\", got %q", *c.CPU)
+	}
+	memory, ok := parseTaskSize(*c.Memory, "gb")
+	if !ok {
+		v.errorf(path+".memory", "must be a number of MiB or GB, e.g. 2048 or \"2 GB\", got %q", *c.Memory)
+	}
+	if cpu == 0 || memory == 0 {
+		return
+	}
+
+	sizes, ok := fargateMemory[cpu]
+	if !ok {
+		v.errorf(path+".cpu", "%d CPU units is not supported by FARGATE", cpu)
+		return
+	}
+	if !slices.Contains(sizes, memory) {
+		v.errorf(path+".memory", "%d MiB is not supported by FARGATE with %d CPU units, supported sizes are %d to %d MiB",
+			memory, cpu, sizes[0], sizes[len(sizes)-1])
+	}
+}
+
+// parseTaskSize parses a task CPU or memory size, given either as a plain number or as a number followed by unit,
+// which is multiplied by 1024.
+func parseTaskSize(value, unit string) (int, bool) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	multiplier := 1.0
+	if strings.HasSuffix(value, unit) {
+		value = strings.TrimSpace(strings.TrimSuffix(value, unit))
+		multiplier = 1024
+	}
+
+	size, err := strconv.ParseFloat(value, 64)
+	if err != nil || size <= 0 {
+		return 0, false
+	}
+	return int(size * multiplier), true
+}
+
+// Validate checks the task set configuration and returns a *ValidationError listing every problem found.
+func (c TaskSetConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "taskSet")
+	return v.err()
+}
+
+func (c TaskSetConfig) validate(v *validator, path string) {
+	v.required(path+".name", c.Name)
+	v.required(path+".cluster", c.Cluster)
+	v.required(path+".service", c.Service)
+	v.required(path+".taskDefinition", c.TaskDefinition)
+
+	v.oneOf(path+".launchType", c.LaunchType, "EC2", "FARGATE", "EXTERNAL")
+	if c.LaunchType != nil && len(c.CapacityProviderStrategies) > 0 {
+		v.errorf(path+".capacityProviderStrategies", "must not be set together with launchType")
+	}
+	for i, strategy := range c.CapacityProviderStrategies {
+		strategyPath := fmt.Sprintf("%s.capacityProviderStrategies[%d]", path, i)
+		v.required(strategyPath+".capacityProvider", strategy.CapacityProvider)
+		v.between(strategyPath+".base", strategy.Base, 0, 100000)
+		v.between(strategyPath+".weight", &strategy.Weight, 0, 1000)
+	}
+
+	if c.NetworkConfiguration == nil && c.LaunchType != nil && *c.LaunchType == "FARGATE" {
+		v.errorf(path+".networkConfiguration", "is required for the FARGATE launch type")
+	}
+	if c.NetworkConfiguration != nil && len(c.NetworkConfiguration.Subnets) == 0 {
+		v.errorf(path+".networkConfiguration.subnets", "must contain at least one subnet")
+	}
+
+	c.validateLoadBalancers(v, path)
+	if c.ServiceRegistries != nil {
+		v.required(path+".serviceRegistries.registryArn", c.ServiceRegistries.RegistryArn)
+	}
+}
+
+// validateLoadBalancers checks the load balancers and the scale of the task set.
+func (c TaskSetConfig) validateLoadBalancers(v *validator, path string) {
+	for i, loadBalancer := range c.LoadBalancers {
+		loadBalancerPath := fmt.Sprintf("%s.loadBalancers[%d]", path, i)
+		v.required(loadBalancerPath+".containerName", loadBalancer.ContainerName)
+		v.between(loadBalancerPath+".containerPort", loadBalancer.ContainerPort, 1, 65535)
+		if (loadBalancer.LoadBalancerName == nil) == (loadBalancer.TargetGroupArn == nil) {
+			v.errorf(loadBalancerPath, "must set exactly one of loadBalancerName and targetGroupArn")
+		}
+	}
+
+	if c.Scale != nil {
+		v.oneOf(path+".scale.unit", c.Scale.Unit, "PERCENT")
+		if c.Scale.Value != nil && (*c.Scale.Value < 0 || *c.Scale.Value > 100) {
+			v.errorf(path+".scale.value", "must be between 0 and 100, got %g", *c.Scale.Value)
+		}
+	}
+}
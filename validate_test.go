@@ -0,0 +1,167 @@
+package ecs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fieldErrors returns the field errors of a *ValidationError, keyed by path, and fails the test if err is of another type.
+func fieldErrors(t *testing.T, err error) map[string]string {
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+
+	fields := map[string]string{}
+	for _, fieldError := range validationError.Errors {
+		fields[fieldError.Path] = fieldError.Message
+	}
+	return fields
+}
+
+// TestValidateExamples is a unit test that checks that the configurations in the /examples directory are valid.
+func TestValidateExamples(t *testing.T) {
+	sugar := zap.NewNop().Sugar()
+
+	capacityProvidersConfig, err := getCapacityProvidersConfig(sugar)
+	assert.NoError(t, err)
+	for _, capacityProviderConfig := range capacityProvidersConfig {
+		assert.NoError(t, capacityProviderConfig.Validate())
+	}
+
+	clusterConfig, err := getClusterConfig(sugar)
+	assert.NoError(t, err)
+	assert.NoError(t, clusterConfig.Validate())
+
+	serviceConfig, err := getServiceConfig(sugar)
+	assert.NoError(t, err)
+	assert.NoError(t, serviceConfig.Validate())
+
+	taskDefinitionConfig, err := getTaskDefinitionConfig(sugar)
+	assert.NoError(t, err)
+	assert.NoError(t, taskDefinitionConfig.Validate())
+
+	taskSetsConfig, err := getTaskSetsConfig(sugar)
+	assert.NoError(t, err)
+	for _, taskSetConfig := range taskSetsConfig {
+		assert.NoError(t, taskSetConfig.Validate())
+	}
+}
+
+// TestValidateService is a unit test that checks that every problem in a service configuration is reported at once.
+func TestValidateService(t *testing.T) {
+	serviceConfig, err := getServiceConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+
+	serviceConfig.CapacityProviderStrategies = append(serviceConfig.CapacityProviderStrategies, struct {
+		CapacityProvider string `json:"name"`
+		Base             *int   `json:"base,omitempty"`
+		Weight           *int   `json:"weight,omitempty"`
+	}{CapacityProvider: "FARGATE_SPOT"})
+	serviceConfig.DeploymentMinimumHealthyPercent = pulumi.IntRef(100)
+	serviceConfig.DeploymentMaximumPercent = pulumi.IntRef(50)
+	serviceConfig.NetworkConfiguration.Subnets = nil
+
+	assert.Equal(t, map[string]string{
+		"service.capacityProviderStrategies":      "must not be set together with launchType",
+		"service.deploymentMinimumHealthyPercent": "(100) must not be greater than deploymentMaximumPercent (50)",
+		"service.networkConfiguration.subnets":    "must contain at least one subnet",
+	}, fieldErrors(t, serviceConfig.Validate()))
+}
+
+// TestValidateTaskDefinition is a unit test that checks the Fargate specific task definition checks.
+func TestValidateTaskDefinition(t *testing.T) {
+	taskDefinitionConfig, err := getTaskDefinitionConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+
+	taskDefinitionConfig.NetworkMode = pulumi.StringRef("bridge")
+	taskDefinitionConfig.CPU = pulumi.StringRef("1 vCPU")
+	taskDefinitionConfig.Memory = pulumi.StringRef("1024")
+
+	assert.Equal(t, map[string]string{
+		"taskDefinition.networkMode": `must be awsvpc for FARGATE, got "bridge"`,
+		"taskDefinition.memory":      "1024 MiB is not supported by FARGATE with 1024 CPU units, supported sizes are 2048 to 8192 MiB",
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+
+	taskDefinitionConfig.NetworkMode = pulumi.StringRef("awsvpc")
+	taskDefinitionConfig.Memory = pulumi.StringRef("2 GB")
+	assert.NoError(t, taskDefinitionConfig.Validate())
+}
+
+// TestNewTaskSetsValidation is a unit test that checks that invalid task sets are rejected before any resource is registered.
+func TestNewTaskSetsValidation(t *testing.T) {
+	taskSetsConfig, err := getTaskSetsConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+
+	taskSetsConfig[0].Cluster = ""
+	taskSetsConfig[0].Scale.Value = pulumi.Float64Ref(150)
+
+	m := newMocks()
+	err = pulumi.RunErr(func(ctx *pulumi.Context) error {
+		_, err := NewTaskSets(ctx, taskSetsConfig)
+		return err
+	}, pulumi.WithMocks("project", "stack", m))
+
+	assert.Equal(t, map[string]string{
+		"taskSets[0].cluster":     "is required",
+		"taskSets[0].scale.value": "must be between 0 and 100, got 150",
+	}, fieldErrors(t, err))
+	assert.Equal(t, 0, m.count(TaskSetType))
+}
+
+// TestValidateContainerDependencies is a unit test that checks the checks of the dependsOn, links and volumesFrom of container definitions.
+func TestValidateContainerDependencies(t *testing.T) {
+	taskDefinitionConfig, err := getTaskDefinitionConfig(zap.NewNop().Sugar())
+	assert.NoError(t, err)
+
+	taskDefinitionConfig.ContainerDefinitions = []ContainerDefinition{
+		{
+			Name:      "app",
+			Image:     "my-app",
+			DependsOn: []ContainerDependency{{ContainerName: "proxy", Condition: "HEALTHY"}, {ContainerName: "db", Condition: "START"}},
+		},
+		{
+			Name:        "proxy",
+			Image:       "envoy",
+			DependsOn:   []ContainerDependency{{ContainerName: "init", Condition: "COMPLETE"}},
+			VolumesFrom: []ContainerVolumeFrom{{SourceContainer: pulumi.StringRef("app")}},
+		},
+		{
+			Name:      "init",
+			Image:     "busybox",
+			DependsOn: []ContainerDependency{{ContainerName: "init", Condition: "STARTED"}},
+			Links:     []string{"app:backend"},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"taskDefinition.containerDefinitions[0]":                            "has a dependency cycle: app -> proxy -> app",
+		"taskDefinition.containerDefinitions[0].dependsOn[0].condition":     `HEALTHY requires container "proxy" to have a healthCheck`,
+		"taskDefinition.containerDefinitions[0].dependsOn[1].containerName": `unknown container "db"`,
+		"taskDefinition.containerDefinitions[1].dependsOn[0].condition":     `COMPLETE requires container "init" to be non-essential`,
+		"taskDefinition.containerDefinitions[2].dependsOn[0].condition":     `must be one of START, COMPLETE, SUCCESS, HEALTHY, got "STARTED"`,
+		"taskDefinition.containerDefinitions[2].dependsOn[0].containerName": "must not reference the container itself",
+		"taskDefinition.containerDefinitions[2].links[0]":                   "is only supported in bridge network mode",
+	}, fieldErrors(t, taskDefinitionConfig.Validate()))
+
+	taskDefinitionConfig.ContainerDefinitions[1].DependsOn = nil
+	taskDefinitionConfig.ContainerDefinitions[1].HealthCheck = &ContainerHealthCheck{Command: []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}}
+	taskDefinitionConfig.ContainerDefinitions[1].VolumesFrom = nil
+	taskDefinitionConfig.ContainerDefinitions[0].DependsOn = taskDefinitionConfig.ContainerDefinitions[0].DependsOn[:1]
+	taskDefinitionConfig.ContainerDefinitions[2].DependsOn = nil
+	taskDefinitionConfig.ContainerDefinitions[2].Links = nil
+	assert.NoError(t, taskDefinitionConfig.Validate())
+}
+
+// TestContainerWarnings is a unit test that checks the warning about task definitions without a container marked essential.
+func TestContainerWarnings(t *testing.T) {
+	assert.Empty(t, containerWarnings([]ContainerDefinition{{Name: "app"}}))
+	assert.Empty(t, containerWarnings([]ContainerDefinition{{Name: "app", Essential: pulumi.BoolRef(true)}, {Name: "proxy"}}))
+	assert.Equal(t, []string{
+		"no container is marked essential, so ECS treats every container that does not set essential as essential and stops the task when any of them exits",
+	}, containerWarnings([]ContainerDefinition{{Name: "app"}, {Name: "proxy", Essential: pulumi.BoolRef(false)}}))
+}